@@ -0,0 +1,177 @@
+package core
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 验证码类型
+const (
+	// CaptchaModeChar 字符验证码 去掉了容易混淆的 0 O 1 I
+	CaptchaModeChar = "char"
+	// CaptchaModeMath 算术验证码 回答计算结果
+	CaptchaModeMath = "math"
+)
+
+const (
+	captchaChars         = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	defaultCaptchaLength = 4
+	// 验证码默认有效期 秒
+	defaultCaptchaExpire int64 = 120
+	// 失败次数默认统计时长 秒
+	defaultCaptchaFailExpire int64 = 60 * 60
+)
+
+var captchaManager *CaptchaManager
+
+// GetCaptchaManager 默认 Server 的验证码 处理请求时使用 GetContextCaptchaManager
+func GetCaptchaManager() *CaptchaManager {
+	if defaultDeps != nil {
+		return defaultDeps.captchaManager
+	}
+	if captchaManager == nil {
+		captchaManager = newCaptchaManager(GetConfig(), getRedisClient())
+	}
+	return captchaManager
+}
+
+func newCaptchaManager(cfg *Config, client *redis.Client) *CaptchaManager {
+	return &CaptchaManager{
+		answers: NewRedisCache[string](client, "sys:captcha:answer:"),
+		fails:   NewRedisCache[int64](client, "sys:captcha:fail:"),
+		config:  cfg,
+	}
+}
+
+// CaptchaManager 图片验证码 答案保存在 Redis 中 验证一次之后立即失效
+type CaptchaManager struct {
+	answers *RedisCache[string]
+	fails   *RedisCache[int64]
+	config  *Config
+}
+
+// Captcha 返回给前端的验证码
+type Captcha struct {
+	CaptchaId string `json:"captchaId"`
+	Image     string `json:"image"`    // data:image/png;base64 格式 可以直接放到 img 标签上
+	ExpireAt  int64  `json:"expireAt"` // 过期时间 秒
+}
+
+// Generate 生成验证码 按配置使用字符或者算术验证码
+func (m CaptchaManager) Generate() (Captcha, error) {
+	captchaConfig := m.config.Captcha
+	var text, answer string
+	if captchaConfig.Mode == CaptchaModeMath {
+		text, answer = mathCaptcha()
+	} else {
+		text = charCaptcha(captchaConfig.Length)
+		answer = text
+	}
+	image, err := renderCaptcha(text)
+	if err != nil {
+		return Captcha{}, err
+	}
+	expire := captchaConfig.Expire
+	if expire <= 0 {
+		expire = defaultCaptchaExpire
+	}
+	captchaId := GetUUID()
+	if !m.answers.XSetCodeEX(captchaId, answer, time.Duration(expire)*time.Second) {
+		return Captcha{}, NewErrCode(SERVER_COMMON_ERROR)
+	}
+	return Captcha{
+		CaptchaId: captchaId,
+		Image:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(image),
+		ExpireAt:  GetNowTimeUnix() + expire,
+	}, nil
+}
+
+// Verify 校验验证码 不区分大小写 无论对错验证码都会失效
+func (m CaptchaManager) Verify(captchaId, code string) error {
+	if captchaId == "" || code == "" {
+		return NewErrCode(CAPTCHA_KEY_NOT_FOUND_ERROR)
+	}
+	result, err := m.answers.GetDel(ctx, m.answers.key+captchaId).Result()
+	if err != nil {
+		return NewErrCodeMsg(CAPTCHA_VERIFY_ERROR, "验证码已过期，请刷新")
+	}
+	if !strings.EqualFold(m.answers.UnMarshal(result), strings.TrimSpace(code)) {
+		return NewErrCode(CAPTCHA_VERIFY_ERROR)
+	}
+	return nil
+}
+
+// Required 是否需要验证码 关闭时始终不需要 FailCount 为 0 时始终需要
+// keys 是统计失败次数的维度 例如 IP 和用户名 任意一个达到次数就需要验证码
+func (m CaptchaManager) Required(keys ...string) bool {
+	captchaConfig := m.config.Captcha
+	if !captchaConfig.Enable {
+		return false
+	}
+	if captchaConfig.FailCount <= 0 {
+		return true
+	}
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if _, count := m.fails.XCodeGet(key); count >= captchaConfig.FailCount {
+			return true
+		}
+	}
+	return false
+}
+
+// Fail 记录一次失败
+func (m CaptchaManager) Fail(keys ...string) {
+	expire := m.config.Captcha.FailExpire
+	if expire <= 0 {
+		expire = defaultCaptchaFailExpire
+	}
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		m.fails.Incr(ctx, m.fails.key+key)
+		m.fails.Expire(ctx, m.fails.key+key, time.Duration(expire)*time.Second)
+	}
+}
+
+// Reset 登录成功之后清空失败次数
+func (m CaptchaManager) Reset(keys ...string) {
+	for _, key := range keys {
+		if key != "" {
+			m.fails.XCodeDel(key)
+		}
+	}
+}
+
+func charCaptcha(length int) string {
+	if length <= 0 {
+		length = defaultCaptchaLength
+	}
+	buf := make([]byte, length)
+	for i := range buf {
+		buf[i] = captchaChars[rand.IntN(len(captchaChars))]
+	}
+	return string(buf)
+}
+
+func mathCaptcha() (text string, answer string) {
+	a, b := rand.IntN(20)+1, rand.IntN(9)+1
+	switch rand.IntN(3) {
+	case 0:
+		return fmt.Sprintf("%d+%d=?", a, b), strconv.Itoa(a + b)
+	case 1:
+		a, b = max(a, b), min(a, b)
+		return fmt.Sprintf("%d-%d=?", a, b), strconv.Itoa(a - b)
+	default:
+		a = a%9 + 1
+		return fmt.Sprintf("%d*%d=?", a, b), strconv.Itoa(a * b)
+	}
+}
@@ -10,4 +10,16 @@ const (
 
 const (
 	GormGlobalSkipHookKey = "gorm-global-skip-hook-key"
+	// GormUsePrimaryKey 查询强制走主库
+	GormUsePrimaryKey = "gorm-use-primary-key"
+)
+
+// 同一平台同时登录的策略
+const (
+	// LoginPolicyUnlimited 不限制同时登录的设备
+	LoginPolicyUnlimited = "unlimited"
+	// LoginPolicySingle 只允许一个设备登录 新登录会踢掉之前的会话
+	LoginPolicySingle = "single"
+	// LoginPolicyLimit 限制同时登录的设备数 超出时踢掉最早登录的会话
+	LoginPolicyLimit = "limit"
 )
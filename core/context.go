@@ -22,20 +22,43 @@ func NewSkipGormGlobalHookContext() context.Context {
 	return context.WithValue(ctx, GormGlobalSkipHookKey, true)
 }
 
+// NewBackgroundContext 没有请求的后台任务使用的 echo.Context 例如定时任务 没有登录用户
+// c 来自生命周期钩子时使用对应的 Server 的依赖 否则使用默认的 Server
+func NewBackgroundContext(c context.Context) echo.Context {
+	request, _ := http.NewRequestWithContext(c, http.MethodGet, "/", nil)
+	ec := echo.New().NewContext(request, nil)
+	if deps := serverDepsFromContext(c); deps != nil {
+		ec.Set(serverDepsContextKey, deps)
+		ec.Set(gormDBContextKey, deps.DB)
+	}
+	return ec
+}
+
 // GetContext  第一个泛型是入参的类型
 func GetContext[T any](c echo.Context) *XContext[T] {
 	cc := XContext[T]{
-		c, GetGormDB(), GetValidator(),
+		c, getContextDB(c), GetContextValidator(c),
 	}
 	return &cc
 } // GetContext  第一个泛型是入参的类型
 func GetAnyContext(c echo.Context) *XContext[any] {
 	cc := XContext[any]{
-		c, GetGormDB(), GetValidator(),
+		c, getContextDB(c), GetContextValidator(c),
 	}
 	return &cc
 }
 
+// gormDBContextKey 当前请求使用的数据库连接在 echo.Context 中的 Key
+const gormDBContextKey = "_core_gorm_db"
+
+// getContextDB 优先使用请求上绑定的数据库连接 没有的话使用全局连接
+func getContextDB(c echo.Context) *gorm.DB {
+	if gormDB, ok := c.Get(gormDBContextKey).(*gorm.DB); ok && gormDB != nil {
+		return gormDB
+	}
+	return GetGormDB()
+}
+
 // GetHeardParam 获取请求头参数
 func (c *XContext[V]) GetHeardParam(key string) string {
 	return c.Context.Request().Header.Get(key)
@@ -48,10 +71,14 @@ func (c *XContext[V]) GetAppPlatformCode() string {
 
 // GetLoginUser  获取请求头参数
 func (c *XContext[V]) GetLoginUser() (ClaimsAdditions, error) {
-	claims, err := GetTokenManager().ParseJwt(c.GetUserToken(), c.GetAppPlatformCode())
+	claims, err := GetContextTokenManager(c).ParseJwt(c.GetUserToken(), c.GetAppPlatformCode())
+	if err != nil && err.errCode == TOKEN_KICKED_ERROR {
+		return claims.ClaimsAdditions, err
+	}
 	if err != nil {
 		return claims.ClaimsAdditions, NewErrCodeMsg(TOKEN_EXPIRE_ERROR, "登录身份过期，请重新登录！")
 	}
+	GetContextTokenManager(c).TouchSession(claims.SessionId)
 	return claims.ClaimsAdditions, nil
 }
 
@@ -131,13 +158,111 @@ func SetEchoContext(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		// 创建自定义上下文
 		cc := XContext[any]{
-			c, GetGormDB(), GetValidator(),
+			c, getContextDB(c), GetContextValidator(c),
 		}
 		// 调用下一个处理程序，传递自定义上下文
 		return next(cc)
 	}
 }
 
+// serverDepsContextKey 处理请求的 Server 的依赖在 echo.Context 中的 Key
+const serverDepsContextKey = "_core_server_deps"
+
+// bindServerDeps 把 Server 的依赖和数据库连接绑定到请求上 用于同一进程内运行多个 Server
+// 请求的 context.Context 上也会带上 只拿到 c.Request().Context() 的方法也能使用
+func bindServerDeps(deps *ServerDeps) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(serverDepsContextKey, deps)
+			c.Set(gormDBContextKey, deps.DB)
+			c.SetRequest(c.Request().WithContext(withServerDeps(c.Request().Context(), deps)))
+			return next(c)
+		}
+	}
+}
+
+// GetServerDeps 处理当前请求的 Server 的依赖 不是 Server 处理的请求时返回默认的 Server 可能为空
+func GetServerDeps(c echo.Context) *ServerDeps {
+	if c != nil {
+		if deps, ok := c.Get(serverDepsContextKey).(*ServerDeps); ok && deps != nil {
+			return deps
+		}
+	}
+	return defaultDeps
+}
+
+// serverDepsValueKey Server 的依赖在 context.Context 中的 Key
+type serverDepsValueKey struct{}
+
+// withServerDeps 把 Server 的依赖放到 context.Context 中 用于请求和生命周期钩子
+func withServerDeps(c context.Context, deps *ServerDeps) context.Context {
+	return context.WithValue(c, serverDepsValueKey{}, deps)
+}
+
+func serverDepsFromContext(c context.Context) *ServerDeps {
+	if c == nil {
+		return nil
+	}
+	deps, _ := c.Value(serverDepsValueKey{}).(*ServerDeps)
+	return deps
+}
+
+// GetContextConfig 处理当前请求的 Server 的配置 没有 Server 时使用全局配置
+func GetContextConfig(c echo.Context) *Config {
+	if deps := GetServerDeps(c); deps != nil {
+		return deps.Config
+	}
+	return GetConfig()
+}
+
+// GetContextRedisCache 使用处理当前请求的 Server 的 Redis
+func GetContextRedisCache[T any](c echo.Context, key string) *RedisCache[T] {
+	if deps := GetServerDeps(c); deps != nil {
+		return NewRedisCache[T](deps.Redis, key)
+	}
+	return GetRedisCache[T](key)
+}
+
+// GetContextTokenManager 处理当前请求的 Server 的令牌管理
+func GetContextTokenManager(c echo.Context) *TokenManager {
+	if deps := GetServerDeps(c); deps != nil {
+		return deps.tokenManager
+	}
+	return GetTokenManager()
+}
+
+// GetContextCaptchaManager 处理当前请求的 Server 的图片验证码
+func GetContextCaptchaManager(c echo.Context) *CaptchaManager {
+	if deps := GetServerDeps(c); deps != nil {
+		return deps.captchaManager
+	}
+	return GetCaptchaManager()
+}
+
+// GetContextVerifyCodeManager 处理当前请求的 Server 的邮件和短信验证码
+func GetContextVerifyCodeManager(c echo.Context) *VerifyCodeManager {
+	if deps := GetServerDeps(c); deps != nil {
+		return deps.verifyCodeManager
+	}
+	return GetVerifyCodeManager()
+}
+
+// GetContextPermissionMange 处理当前请求的 Server 的角色权限
+func GetContextPermissionMange(c echo.Context) *RolePermission {
+	if deps := GetServerDeps(c); deps != nil && deps.permission != nil {
+		return deps.permission
+	}
+	return PermissionMange
+}
+
+// GetContextValidator 处理当前请求的 Server 的校验器 密码策略使用 Server 的配置
+func GetContextValidator(c echo.Context) *Validator {
+	if deps := GetServerDeps(c); deps != nil && deps.validator != nil {
+		return deps.validator
+	}
+	return GetValidator()
+}
+
 func (c *XContext[V]) GetDB() *gorm.DB {
 	return c.gorm
 }
@@ -0,0 +1,30 @@
+package coretest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/super-sunshines/echo-server-core/core"
+)
+
+// NewConfig 每次调用都使用新的 sqlite 数据库和内存 Redis 可以在同一个测试中 Build 多个 Server
+func NewConfig(t testing.TB) core.Config {
+	t.Helper()
+	dir := t.TempDir()
+	return core.Config{
+		DataBase:        core.GormConfig{Driver: "sqlite", DataBase: filepath.Join(dir, "test.db")},
+		Redis:           core.RedisConfig{Addr: NewRedis(t)},
+		Jwt:             core.JwtConfig{JwtKey: "test", Expire: 600, MaxLoginFailCount: 5},
+		Ip2RegionConfig: core.Ip2RegionConfig{FilePath: newIp2RegionFile(t, dir)},
+	}
+}
+
+// newIp2RegionFile 只有文件头和空索引的 xdb 查询结果都为空
+func newIp2RegionFile(t testing.TB, dir string) string {
+	path := filepath.Join(dir, "ip2region.xdb")
+	if err := os.WriteFile(path, make([]byte, 256+256*256*8), 0o600); err != nil {
+		t.Fatalf("write ip2region: %v", err)
+	}
+	return path
+}
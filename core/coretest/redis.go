@@ -0,0 +1,347 @@
+// Package coretest 测试使用的依赖 不需要外部的 Redis 和数据库
+package coretest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// NewRedis 启动一个内存中的 Redis 返回监听地址 测试结束时关闭
+// 只实现了 RedisCache 用到的字符串、哈希和集合命令
+func NewRedis(t testing.TB) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen redis: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	r := &memoryRedis{
+		values:  map[string]string{},
+		hashes:  map[string]map[string]string{},
+		sets:    map[string]map[string]bool{},
+		expires: map[string]time.Time{},
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go r.serve(conn)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+type memoryRedis struct {
+	sync.Mutex
+	values  map[string]string
+	hashes  map[string]map[string]string
+	sets    map[string]map[string]bool
+	expires map[string]time.Time
+}
+
+// serve 读取 RESP 格式的命令 每个命令都是字符串数组
+func (r *memoryRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		if _, err = conn.Write([]byte(r.exec(args))); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, count)
+	for i := range args {
+		if line, err = reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err = io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+const (
+	replyOK  = "+OK\r\n"
+	replyNil = "$-1\r\n"
+)
+
+func bulk(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+func integer(n int) string {
+	return fmt.Sprintf(":%d\r\n", n)
+}
+
+func array(items []string) string {
+	reply := fmt.Sprintf("*%d\r\n", len(items))
+	for _, item := range items {
+		reply += bulk(item)
+	}
+	return reply
+}
+
+// exists 检查 key 是否存在 过期的 key 在这里删除
+func (r *memoryRedis) exists(key string) bool {
+	if expire, ok := r.expires[key]; ok && time.Now().After(expire) {
+		r.delete(key)
+	}
+	_, value := r.values[key]
+	_, hash := r.hashes[key]
+	_, set := r.sets[key]
+	return value || hash || set
+}
+
+func (r *memoryRedis) delete(key string) {
+	delete(r.values, key)
+	delete(r.hashes, key)
+	delete(r.sets, key)
+	delete(r.expires, key)
+}
+
+func (r *memoryRedis) hash(key string) map[string]string {
+	r.exists(key)
+	if r.hashes[key] == nil {
+		r.hashes[key] = map[string]string{}
+	}
+	return r.hashes[key]
+}
+
+func (r *memoryRedis) set(key string) map[string]bool {
+	r.exists(key)
+	if r.sets[key] == nil {
+		r.sets[key] = map[string]bool{}
+	}
+	return r.sets[key]
+}
+
+func (r *memoryRedis) exec(args []string) string {
+	r.Lock()
+	defer r.Unlock()
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "CLIENT", "SELECT":
+		return replyOK
+	case "SET":
+		return r.setValue(args)
+	case "GET":
+		if !r.exists(args[1]) {
+			return replyNil
+		}
+		return bulk(r.values[args[1]])
+	case "GETDEL":
+		if !r.exists(args[1]) {
+			return replyNil
+		}
+		value := r.values[args[1]]
+		r.delete(args[1])
+		return bulk(value)
+	case "INCR", "INCRBY", "DECR":
+		by := 1
+		if len(args) > 2 {
+			by, _ = strconv.Atoi(args[2])
+		}
+		if strings.EqualFold(args[0], "DECR") {
+			by = -1
+		}
+		n := 0
+		if r.exists(args[1]) {
+			n, _ = strconv.Atoi(r.values[args[1]])
+		}
+		n += by
+		r.values[args[1]] = strconv.Itoa(n)
+		return integer(n)
+	case "EXPIRE", "PEXPIRE":
+		if !r.exists(args[1]) {
+			return integer(0)
+		}
+		n, _ := strconv.Atoi(args[2])
+		unit := time.Second
+		if strings.EqualFold(args[0], "PEXPIRE") {
+			unit = time.Millisecond
+		}
+		r.expires[args[1]] = time.Now().Add(time.Duration(n) * unit)
+		return integer(1)
+	case "TTL":
+		if !r.exists(args[1]) {
+			return integer(-2)
+		}
+		expire, ok := r.expires[args[1]]
+		if !ok {
+			return integer(-1)
+		}
+		return integer(int(time.Until(expire).Seconds()))
+	case "EXISTS":
+		n := 0
+		for _, key := range args[1:] {
+			if r.exists(key) {
+				n++
+			}
+		}
+		return integer(n)
+	case "DEL":
+		n := 0
+		for _, key := range args[1:] {
+			if r.exists(key) {
+				n++
+			}
+			r.delete(key)
+		}
+		return integer(n)
+	case "HSET", "HMSET":
+		hash := r.hash(args[1])
+		n := 0
+		for i := 2; i+1 < len(args); i += 2 {
+			if _, ok := hash[args[i]]; !ok {
+				n++
+			}
+			hash[args[i]] = args[i+1]
+		}
+		if strings.EqualFold(args[0], "HMSET") {
+			return replyOK
+		}
+		return integer(n)
+	case "HSETNX":
+		hash := r.hash(args[1])
+		if _, ok := hash[args[2]]; ok {
+			return integer(0)
+		}
+		hash[args[2]] = args[3]
+		return integer(1)
+	case "HGET":
+		value, ok := r.hash(args[1])[args[2]]
+		if !ok {
+			return replyNil
+		}
+		return bulk(value)
+	case "HEXISTS":
+		_, ok := r.hash(args[1])[args[2]]
+		return integer(map[bool]int{true: 1}[ok])
+	case "HDEL":
+		hash := r.hash(args[1])
+		n := 0
+		for _, field := range args[2:] {
+			if _, ok := hash[field]; ok {
+				n++
+				delete(hash, field)
+			}
+		}
+		return integer(n)
+	case "HLEN":
+		return integer(len(r.hash(args[1])))
+	case "HKEYS":
+		return array(sortedKeys(r.hash(args[1])))
+	case "HGETALL":
+		hash := r.hash(args[1])
+		items := make([]string, 0, len(hash)*2)
+		for _, field := range sortedKeys(hash) {
+			items = append(items, field, hash[field])
+		}
+		return array(items)
+	case "HMGET":
+		hash := r.hash(args[1])
+		reply := fmt.Sprintf("*%d\r\n", len(args)-2)
+		for _, field := range args[2:] {
+			if value, ok := hash[field]; ok {
+				reply += bulk(value)
+			} else {
+				reply += replyNil
+			}
+		}
+		return reply
+	case "SADD":
+		set := r.set(args[1])
+		n := 0
+		for _, member := range args[2:] {
+			if !set[member] {
+				n++
+				set[member] = true
+			}
+		}
+		return integer(n)
+	case "SREM":
+		set := r.set(args[1])
+		n := 0
+		for _, member := range args[2:] {
+			if set[member] {
+				n++
+				delete(set, member)
+			}
+		}
+		return integer(n)
+	case "SCARD":
+		return integer(len(r.set(args[1])))
+	case "SMEMBERS":
+		return array(sortedKeys(r.set(args[1])))
+	}
+	return "-ERR unsupported command " + args[0] + "\r\n"
+}
+
+// setValue SET key value [NX] [EX seconds] [PX milliseconds]
+func (r *memoryRedis) setValue(args []string) string {
+	nx := false
+	var ttl time.Duration
+	for i := 3; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "NX":
+			nx = true
+		case "EX", "PX":
+			n, _ := strconv.Atoi(args[i+1])
+			ttl = time.Duration(n) * time.Second
+			if strings.EqualFold(args[i], "PX") {
+				ttl = time.Duration(n) * time.Millisecond
+			}
+			i++
+		}
+	}
+	if nx && r.exists(args[1]) {
+		return replyNil
+	}
+	r.delete(args[1])
+	r.values[args[1]] = args[2]
+	if ttl > 0 {
+		r.expires[args[1]] = time.Now().Add(ttl)
+	}
+	return replyOK
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,247 @@
+package core
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/duke-git/lancet/v2/slice"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// CursorParam 游标分页参数 Cursor 为空时从第一页开始
+type CursorParam struct {
+	Cursor   string `json:"cursor" form:"cursor" query:"cursor" zh_comment:"游标" en_comment:"cursor" validate:""`
+	PageSize int    `json:"pageSize" form:"pageSize" query:"pageSize" zh_comment:"每页条数" en_comment:"pageSize" validate:"required,gte=1"`
+}
+
+// CursorSort 游标分页的排序字段 值相同时再按主键排序
+type CursorSort struct {
+	Column string // 数据库字段名 例如 operate_time
+	Desc   bool
+}
+
+type CursorResult struct {
+	PageSize   int    `json:"pageSize"`
+	NextCursor string `json:"nextCursor"` // 为空时没有下一页
+	PrevCursor string `json:"prevCursor"` // 为空时没有上一页
+}
+
+type CursorResultList[T any] struct {
+	CursorResult
+	Items []T `json:"items"`
+}
+
+// PageOrCursorResultList 同时支持页码分页和游标分页的接口返回值 只用于接口文档
+// 页码分页返回 PageResultList 的 page、pageSize、total、lastPage mode=cursor 时返回 CursorResultList 的 pageSize、nextCursor、prevCursor
+type PageOrCursorResultList[T any] struct {
+	Page       int    `json:"page"`       // 页码分页
+	PageSize   int    `json:"pageSize"`   // 两种分页都有
+	Total      int64  `json:"total"`      // 页码分页
+	LastPage   bool   `json:"lastPage"`   // 页码分页
+	NextCursor string `json:"nextCursor"` // 游标分页 为空时没有下一页
+	PrevCursor string `json:"prevCursor"` // 游标分页 为空时没有上一页
+	Items      []T    `json:"items"`
+}
+
+// cursorToken 游标的内容 base64 编码之后返回给前端
+// 排序字段和主键都按数据库驱动的值保存 避免 JSON 格式化丢失精度
+type cursorToken struct {
+	Column string      `json:"c"`
+	Desc   bool        `json:"d"`
+	Prev   bool        `json:"p,omitempty"`
+	Value  cursorValue `json:"v"`
+	Key    cursorValue `json:"k"`
+}
+
+type cursorValue struct {
+	Kind string `json:"t"`
+	Data string `json:"v"`
+}
+
+// FindListByCursor 游标分页 不查询总数 适合数据量大的表
+// 从上一页返回的 NextCursor/PrevCursor 继续查询 游标只能在同一个排序字段上使用
+func (r *Gorm[M, V]) FindListByCursor(param CursorParam, sort CursorSort, conditions ...func(*gorm.DB) *gorm.DB) (error, CursorResultList[M]) {
+	var result CursorResultList[M]
+	result.PageSize = param.PageSize
+	if param.PageSize <= 0 {
+		return errors.New("pageSize must be greater than 0"), result
+	}
+	db := r.DBWithConditions(conditions...)
+	sortField, keyField, err := r.cursorFields(db, sort.Column)
+	if err != nil {
+		return err, result
+	}
+	token, err := decodeCursor(param.Cursor, sort)
+	if err != nil {
+		return err, result
+	}
+	// 向前翻页时反向排序查询 查询完再把结果倒过来
+	backward := token != nil && token.Prev
+	desc := sort.Desc != backward
+	column, key := sortField.DBName, keyField.DBName
+	if token != nil {
+		value, err := token.Value.decode()
+		if err != nil {
+			return err, result
+		}
+		keyValue, err := token.Key.decode()
+		if err != nil {
+			return err, result
+		}
+		op := BooleanTo(desc, "<", ">")
+		db = db.Where(fmt.Sprintf("((%s %s ?) OR (%s = ? AND %s %s ?))", column, op, column, key, op), value, value, keyValue)
+	}
+	order := BooleanTo(desc, "DESC", "ASC")
+	list := r.ModelList()
+	if err = db.Order(column + " " + order).Order(key + " " + order).Limit(param.PageSize + 1).Find(&list).Error; err != nil {
+		return err, result
+	}
+	more := len(list) > param.PageSize
+	if more {
+		list = list[:param.PageSize]
+	}
+	if backward {
+		slice.Reverse(list)
+	}
+	result.Items = list
+	if len(list) == 0 {
+		return nil, result
+	}
+	hasNext := BooleanTo(backward, true, more)
+	hasPrev := BooleanTo(backward, more, token != nil)
+	if hasNext {
+		if result.NextCursor, err = encodeCursor(db, sort, sortField, keyField, list[len(list)-1], false); err != nil {
+			return err, result
+		}
+	}
+	if hasPrev {
+		if result.PrevCursor, err = encodeCursor(db, sort, sortField, keyField, list[0], true); err != nil {
+			return err, result
+		}
+	}
+	return nil, result
+}
+
+func (r *Gorm[M, V]) FindVoListByCursor(param CursorParam, sort CursorSort, conditions ...func(*gorm.DB) *gorm.DB) (error, CursorResultList[V]) {
+	var result CursorResultList[V]
+	err, p := r.FindListByCursor(param, sort, conditions...)
+	if err != nil {
+		return err, result
+	}
+	result.CursorResult = p.CursorResult
+	result.Items = CopyListFrom[V](p.Items)
+	return nil, result
+}
+
+// cursorFields 排序字段和主键必须是模型上的字段 同时避免拼接 SQL 注入
+func (r *Gorm[M, V]) cursorFields(db *gorm.DB, column string) (sortField *schema.Field, keyField *schema.Field, err error) {
+	model := r.createModelInstance()
+	if err = db.Statement.Parse(&model); err != nil {
+		return nil, nil, err
+	}
+	sortField = db.Statement.Schema.LookUpField(column)
+	keyField = db.Statement.Schema.LookUpField(r.config.PrimaryKeyField)
+	if sortField == nil || sortField.DBName == "" {
+		return nil, nil, NewFrontShowErrMsg("不支持的排序字段: " + column)
+	}
+	if keyField == nil || keyField.DBName == "" {
+		return nil, nil, errors.Errorf("primary key %s not found", r.config.PrimaryKeyField)
+	}
+	return sortField, keyField, nil
+}
+
+func decodeCursor(cursor string, sort CursorSort) (*cursorToken, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, NewErrCode(CURD_CURSOR_INVALID_ERROR)
+	}
+	var token cursorToken
+	if err = json.Unmarshal(data, &token); err != nil {
+		return nil, NewErrCode(CURD_CURSOR_INVALID_ERROR)
+	}
+	if token.Column != sort.Column || token.Desc != sort.Desc {
+		return nil, NewErrCode(CURD_CURSOR_INVALID_ERROR)
+	}
+	return &token, nil
+}
+
+func encodeCursor(db *gorm.DB, sort CursorSort, sortField, keyField *schema.Field, item any, prev bool) (string, error) {
+	row := reflect.Indirect(reflect.ValueOf(item))
+	value, err := newCursorValue(db, sortField, row)
+	if err != nil {
+		return "", err
+	}
+	key, err := newCursorValue(db, keyField, row)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(cursorToken{Column: sort.Column, Desc: sort.Desc, Prev: prev, Value: value, Key: key})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// newCursorValue 读取行上字段的数据库值 实现了 driver.Valuer 的类型使用 Value() 的结果
+func newCursorValue(db *gorm.DB, field *schema.Field, row reflect.Value) (cursorValue, error) {
+	value, _ := field.ValueOf(db.Statement.Context, row)
+	if valuer, ok := value.(driver.Valuer); ok {
+		var err error
+		if value, err = valuer.Value(); err != nil {
+			return cursorValue{}, err
+		}
+	}
+	switch v := value.(type) {
+	case time.Time:
+		return cursorValue{Kind: "time", Data: v.Format(time.RFC3339Nano)}, nil
+	case string:
+		return cursorValue{Kind: "string", Data: v}, nil
+	case []byte:
+		return cursorValue{Kind: "string", Data: string(v)}, nil
+	case bool:
+		return cursorValue{Kind: "bool", Data: strconv.FormatBool(v)}, nil
+	case int, int8, int16, int32, int64:
+		return cursorValue{Kind: "int", Data: fmt.Sprint(v)}, nil
+	case uint, uint8, uint16, uint32, uint64:
+		return cursorValue{Kind: "uint", Data: fmt.Sprint(v)}, nil
+	case float32, float64:
+		return cursorValue{Kind: "float", Data: fmt.Sprint(v)}, nil
+	}
+	return cursorValue{}, errors.Errorf("unsupported cursor column %s type %T", field.DBName, value)
+}
+
+func (v cursorValue) decode() (any, error) {
+	var (
+		value any
+		err   error
+	)
+	switch v.Kind {
+	case "time":
+		value, err = time.Parse(time.RFC3339Nano, v.Data)
+	case "string":
+		value = v.Data
+	case "bool":
+		value, err = strconv.ParseBool(v.Data)
+	case "int":
+		value, err = strconv.ParseInt(v.Data, 10, 64)
+	case "uint":
+		value, err = strconv.ParseUint(v.Data, 10, 64)
+	case "float":
+		value, err = strconv.ParseFloat(v.Data, 64)
+	default:
+		err = errors.Errorf("unknown cursor kind %s", v.Kind)
+	}
+	if err != nil {
+		return nil, NewErrCode(CURD_CURSOR_INVALID_ERROR)
+	}
+	return value, nil
+}
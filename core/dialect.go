@@ -0,0 +1,103 @@
+package core
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// 支持的数据库驱动
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// driverName 返回配置的驱动名称 为空时默认 mysql
+func (c GormConfig) driverName() string {
+	if c.Driver == "" {
+		return DriverMySQL
+	}
+	return strings.ToLower(c.Driver)
+}
+
+// dsn 根据配置拼接连接字符串
+// mysql: OtherSettings 以 & 开头追加到 DSN 参数后面
+// postgres: OtherSettings 以空格分隔追加 例如 sslmode=disable TimeZone=Asia/Shanghai
+// sqlite: DataBase 为数据库文件路径 内存库请使用 file::memory:?cache=shared
+func (c GormConfig) dsn() (string, error) {
+	switch c.driverName() {
+	case DriverMySQL:
+		return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true&loc=Local&charset=utf8mb4%s",
+			c.User, c.Pass, c.Host, c.Port, c.DataBase, c.OtherSettings), nil
+	case DriverPostgres:
+		return strings.TrimSpace(fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s %s",
+			c.Host, c.Port, c.User, c.Pass, c.DataBase, c.OtherSettings)), nil
+	case DriverSQLite:
+		return c.DataBase + c.OtherSettings, nil
+	default:
+		return "", fmt.Errorf("不支持的数据库驱动: %s", c.Driver)
+	}
+}
+
+// openDialector 根据 GormConfig.Driver 创建对应的方言
+func openDialector(options GormConfig) (gorm.Dialector, error) {
+	dsn, err := options.dsn()
+	if err != nil {
+		return nil, err
+	}
+	switch options.driverName() {
+	case DriverPostgres:
+		return postgres.Open(dsn), nil
+	case DriverSQLite:
+		return sqlite.Open(dsn), nil
+	default:
+		return mysql.Open(dsn), nil
+	}
+}
+
+// openConnDialector 单独打开一个连接池并用它创建方言 用于需要独立连接池配置的从库
+func openConnDialector(options GormConfig) (gorm.Dialector, *sql.DB, error) {
+	dsn, err := options.dsn()
+	if err != nil {
+		return nil, nil, err
+	}
+	var conn *sql.DB
+	var dialector gorm.Dialector
+	switch options.driverName() {
+	case DriverMySQL:
+		conn, err = sql.Open("mysql", dsn)
+		dialector = mysql.New(mysql.Config{Conn: conn})
+	case DriverPostgres:
+		conn, err = sql.Open("pgx", dsn)
+		dialector = postgres.New(postgres.Config{Conn: conn})
+	default:
+		return nil, nil, fmt.Errorf("驱动 %s 不支持从库", options.driverName())
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	options.applyPool(conn)
+	return dialector, conn, nil
+}
+
+// applyPool 设置连接池参数
+func (c GormConfig) applyPool(conn *sql.DB) {
+	conn.SetConnMaxIdleTime(time.Duration(c.MaxIdleTime) * time.Second)
+	conn.SetConnMaxLifetime(time.Duration(c.ConnMaxLifetime) * time.Second)
+	conn.SetMaxIdleConns(c.SetMaxIdleConn)
+	conn.SetMaxOpenConns(c.SetMaxOpenConn)
+}
+
+// dialectName 返回当前连接的方言名称 mysql/postgres/sqlite
+func dialectName(db *gorm.DB) string {
+	if db == nil || db.Dialector == nil {
+		return DriverMySQL
+	}
+	return db.Dialector.Name()
+}
@@ -1,18 +1,19 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"github.com/jedib0t/go-pretty/table"
 	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
-	echoSwagger "github.com/swaggo/echo-swagger"
 	"go.uber.org/zap"
 	"gorm.io/gorm/logger"
 	"net/http"
 	"os"
+	"os/signal"
 	"runtime"
 	"sort"
 	"strings"
+	"syscall"
 	"time"
 )
 
@@ -36,50 +37,20 @@ type ServerRunOption struct {
 	PermissionsOptions PermissionsOptions    // 角色权限全局钩子
 	BeforeRun          func(echo *echo.Echo) // ServerRun 之前的钩子
 	LoggerOptions      LoggerOptions
+	Hooks              []LifecycleHook // 生命周期钩子 OnStart 按顺序执行 OnStop 逆序执行
+	ShutdownTimeout    time.Duration   // 优雅关闭时等待请求处理完成的时长 为空时读取配置 Server.ShutdownTimeout
+	Migrations         []Migration     // 启动时自动执行的数据库迁移 为空时不执行
+	Notifier           Notifier        // 发送邮件和短信 为空时按配置 Notify 使用 SMTP 或者只写日志
 }
 
-func NewServer(routerGroup []*RouterGroup, option ServerRunOption) {
+// NewServer 读取 ./application.yaml 启动服务 阻塞直到收到 SIGINT/SIGTERM 信号
+// 收到信号后等待正在处理的请求完成 然后执行 OnStop 钩子关闭数据库、Redis等资源
+func NewServer(routerGroup []*RouterGroup, option ServerRunOption) error {
 	CheckFile("./application.yaml")
 	InitConfig()
-	checkIp2RegionFile()
-	initGormConfig(option.GormOptions)
-	initRolePermission(option.PermissionsOptions)
-	initRedis()
-	initLogMiddleware(option.LoggerOptions)
-	e := echo.New()
-	// 关闭Banner
-	e.HideBanner = true
-	// 全局错误方法
-	e.HTTPErrorHandler = EchoError()
-	// 全局日志接管Echo 日志
-	e.Logger = GetLogger()
-	// 使用中间件
-	e.Use(SetEchoContext)
-	e.Use(RequestLoggerMiddleware)
-	e.Use(RecoverMiddleware)
-	e.Use(middleware.CORS())
-	for _, group := range routerGroup {
-		RegisterGroup(e.Group(config.Server.GlobalPrefix), group)
-	}
-	// 生产环境下不打开Swagger
-	if config.Server.Dev {
-		e.GET("/swagger/*", echoSwagger.WrapHandler)
-	}
-	//
-
-	if option.BeforeRun != nil {
-		option.BeforeRun(e)
-	}
-	ResolveRoutes(e)
-	fmt.Println(fmt.Sprintf(`%s==> Server Started !%s`, logger.Green, logger.Reset))
-	if config.Server.Dev {
-		fmt.Println(fmt.Sprintf(`%s==> Swagger FilePath: %s %s`, logger.Green, fmt.Sprintf("http://127.0.0.1:%d/swagger/index.html", config.Server.HttpPort), logger.Reset))
-	}
-	err := e.Start(fmt.Sprintf(":%d", config.Server.HttpPort))
-	if err != nil {
-		panic(err)
-	}
-	return
+	signalCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	return NewServerFromConfig(*config, routerGroup, option).Run(signalCtx)
 }
 
 func EchoError() func(err error, c echo.Context) {
@@ -122,7 +93,7 @@ func RecoverMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 				}
 				// 记录日志
 				logMessage := stackBuf.String()
-				if config.Server.Dev {
+				if GetContextConfig(c).Server.Dev {
 					fmt.Println(fmt.Sprintf("%s %s %s", logger.Red, logMessage, logger.Reset))
 				}
 				c.Logger().Error(logMessage)
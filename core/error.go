@@ -19,6 +19,12 @@ var (
 	TOKEN_GENERATE_ERROR uint32 = 100051
 	TOKEN_FORMAT_ERROR   uint32 = 100052
 	TOKEN_ERROR          uint32 = 100051
+	// TOKEN_REFRESH_INVALID_ERROR 刷新令牌无效或者已过期
+	TOKEN_REFRESH_INVALID_ERROR uint32 = 100053
+	// TOKEN_REFRESH_REUSED_ERROR 刷新令牌被重复使用 该登录已被撤销
+	TOKEN_REFRESH_REUSED_ERROR uint32 = 100054
+	// TOKEN_KICKED_ERROR 账号在其他设备登录 当前会话被踢下线
+	TOKEN_KICKED_ERROR uint32 = 100055
 
 	// DB_ERROR DB相关错误
 	DB_ERROR                      uint32 = 100100
@@ -32,16 +38,33 @@ var (
 	CAPTCHA_KEY_NOT_FOUND_ERROR uint32 = 100200
 	CAPTCHA_VERIFY_ERROR        uint32 = 100201
 
+	// VERIFY_CODE_ERROR 邮件和短信验证码相关
+	VERIFY_CODE_ERROR               uint32 = 100210
+	VERIFY_CODE_EXPIRED_ERROR       uint32 = 100211
+	VERIFY_CODE_ATTEMPT_ERROR       uint32 = 100212
+	VERIFY_CODE_SEND_FREQUENT_ERROR uint32 = 100213
+	VERIFY_CODE_SEND_ERROR          uint32 = 100214
+
+	// RATE_LIMIT_ERROR 请求过于频繁 被限流
+	RATE_LIMIT_ERROR uint32 = 100300
+
 	// USER_NOT_EXIST_ERROR 用户登录相关
 	USER_NOT_EXIST_ERROR uint32 = 100250
 	USER_PASSWORD_ERROR  uint32 = 100251
 	USER_STARTUS_ERROR   uint32 = 100252
 
+	// OIDC_STATE_ERROR OAuth2/OIDC 登录相关
+	OIDC_STATE_ERROR uint32 = 100260
+	OIDC_TOKEN_ERROR uint32 = 100261
+
 	// CURD_AFFECT_NONE_ERROR 通用增删改查相关
 	CURD_AFFECT_NONE_ERROR        uint32 = 101000
 	CURD_UPDATE_AFFECT_NONE_ERROR uint32 = 101001
+	CURD_CURSOR_INVALID_ERROR     uint32 = 101002
 	CURD_DATA_EXIST_ERROR         uint32 = 101010
 	CURD_DATA_NOT_EXIST_ERROR     uint32 = 101010
+	CURD_VERSION_CONFLICT_ERROR   uint32 = 101020
+	CURD_VERSION_REQUIRED_ERROR   uint32 = 101021
 
 	// DICT_NOT_EXIST_ERROR 字典相关
 	DICT_NOT_EXIST_ERROR uint32 = 101200
@@ -61,10 +84,13 @@ var message = map[uint32]string{
 	//REQUEST_PARAM_ERROR: "参数错误",
 	NO_RERMIT_ERROR: "暂无权限！",
 
-	TOKEN_EXPIRE_ERROR:   "token失效，请重新登陆",
-	TOKEN_GENERATE_ERROR: "生成token失败",
-	TOKEN_FORMAT_ERROR:   "Token格式错误",
-	TOKEN_ERROR:          "Token错误",
+	TOKEN_EXPIRE_ERROR:          "token失效，请重新登陆",
+	TOKEN_GENERATE_ERROR:        "生成token失败",
+	TOKEN_FORMAT_ERROR:          "Token格式错误",
+	TOKEN_ERROR:                 "Token错误",
+	TOKEN_REFRESH_INVALID_ERROR: "登录已过期，请重新登陆",
+	TOKEN_REFRESH_REUSED_ERROR:  "登录状态异常，请重新登陆",
+	TOKEN_KICKED_ERROR:          "账号已在其他设备登录，请重新登陆",
 
 	DB_ERROR:                      "数据库繁忙,请稍后再试",
 	DB_UPDATE_AFFECTED_ZERO_ERROR: "更新数据影响行数为0",
@@ -72,14 +98,28 @@ var message = map[uint32]string{
 	CAPTCHA_KEY_NOT_FOUND_ERROR: "请完成验证码",
 	CAPTCHA_VERIFY_ERROR:        "验证码验证失败",
 
+	VERIFY_CODE_ERROR:               "验证码错误",
+	VERIFY_CODE_EXPIRED_ERROR:       "验证码已过期，请重新获取",
+	VERIFY_CODE_ATTEMPT_ERROR:       "验证码错误次数过多，请重新获取",
+	VERIFY_CODE_SEND_FREQUENT_ERROR: "验证码发送过于频繁，请稍后再试",
+	VERIFY_CODE_SEND_ERROR:          "验证码发送失败，请稍后再试",
+
+	RATE_LIMIT_ERROR: "请求过于频繁，请稍后再试",
+
 	USER_NOT_EXIST_ERROR: "用户不存在",
 	USER_PASSWORD_ERROR:  "密码错误",
 	USER_STARTUS_ERROR:   "用户状态异常",
 
+	OIDC_STATE_ERROR: "登录已过期，请重新登录",
+	OIDC_TOKEN_ERROR: "三方登录验证失败，请重新登录",
+
 	CURD_AFFECT_NONE_ERROR:        "未影响行数",
 	CURD_UPDATE_AFFECT_NONE_ERROR: "修改失败",
+	CURD_CURSOR_INVALID_ERROR:     "分页游标无效",
 	CURD_DATA_EXIST_ERROR:         "数据已存在",
 	CURD_DATA_NOT_EXIST_ERROR:     "数据不存在",
+	CURD_VERSION_CONFLICT_ERROR:   "数据已被修改，请刷新后重试",
+	CURD_VERSION_REQUIRED_ERROR:   "缺少版本号，请刷新后重试",
 
 	DICT_NOT_EXIST_ERROR: "字典不存在",
 
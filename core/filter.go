@@ -0,0 +1,267 @@
+package core
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// 查询条件标签支持的操作
+const (
+	FilterEq      = "eq"
+	FilterNe      = "ne"
+	FilterGt      = "gt"
+	FilterGte     = "gte"
+	FilterLt      = "lt"
+	FilterLte     = "lte"
+	FilterLike    = "like"
+	FilterIn      = "in"
+	FilterBetween = "between"
+)
+
+var filterOperators = map[string]string{
+	FilterEq:  "=",
+	FilterNe:  "<>",
+	FilterGt:  ">",
+	FilterGte: ">=",
+	FilterLt:  "<",
+	FilterLte: "<=",
+}
+
+// filterField BO 上一个带 filter 标签的字段
+type filterField struct {
+	index   []int
+	op      string
+	columns []string // 多个字段时用 OR 连接
+}
+
+// 每个 BO 类型只解析一次标签
+var filterFieldCache sync.Map
+
+// Filter 根据 BO 字段上的 filter 标签生成查询条件 零值的字段不参与查询 需要查询零值时使用指针
+//
+//	Module    int64    `query:"module" filter:"eq"`                                 // module = ?
+//	SearchKey string   `query:"searchKey" filter:"like,column=real_name|nick_name"` // (real_name LIKE ? OR nick_name LIKE ?)
+//	Ids       []int64  `query:"ids" filter:"in,column=id"`                          // id IN ?
+//	Time      []string `query:"time" filter:"between,column=create_time"`          // create_time BETWEEN ? AND ?
+//
+// 没有指定 column 时使用模型上同名的字段 字段必须存在于模型中 like 查询时输入中的 % 和 _ 按普通字符匹配
+func Filter(bo any) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		value := reflect.Indirect(reflect.ValueOf(bo))
+		if value.Kind() != reflect.Struct {
+			return db
+		}
+		fields, err := parseFilterFields(value.Type())
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		modelSchema, err := statementSchema(db)
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		for _, field := range fields {
+			fieldValue := value.FieldByIndex(field.index)
+			if fieldValue.Kind() == reflect.Ptr {
+				if fieldValue.IsNil() {
+					continue
+				}
+				fieldValue = fieldValue.Elem()
+			} else if fieldValue.IsZero() || (fieldValue.Kind() == reflect.Slice && fieldValue.Len() == 0) {
+				continue
+			}
+			columns := make([]string, 0, len(field.columns))
+			for _, column := range field.columns {
+				dbName, err := lookUpColumn(modelSchema, column)
+				if err != nil {
+					_ = db.AddError(err)
+					return db
+				}
+				columns = append(columns, db.Statement.Quote(dbName))
+			}
+			if err = applyFilter(db, field.op, columns, fieldValue.Interface()); err != nil {
+				_ = db.AddError(err)
+				return db
+			}
+		}
+		return db
+	}
+}
+
+func applyFilter(db *gorm.DB, op string, columns []string, value any) error {
+	var (
+		parts []string
+		args  []any
+	)
+	for _, column := range columns {
+		switch op {
+		case FilterLike:
+			parts = append(parts, column+" LIKE ? ESCAPE "+likeEscapeLiteral(db))
+			args = append(args, "%"+likeEscaper.Replace(fmt.Sprint(value))+"%")
+		case FilterIn:
+			parts = append(parts, column+" IN ?")
+			args = append(args, value)
+		case FilterBetween:
+			sql, betweenArgs, err := betweenCondition(column, value)
+			if err != nil {
+				return err
+			}
+			if sql == "" {
+				return nil
+			}
+			parts = append(parts, sql)
+			args = append(args, betweenArgs...)
+		default:
+			operator := filterOperators[op]
+			parts = append(parts, column+" "+operator+" ?")
+			args = append(args, value)
+		}
+	}
+	sql := strings.Join(parts, " OR ")
+	if len(parts) > 1 {
+		sql = "(" + sql + ")"
+	}
+	db.Where(sql, args...)
+	return nil
+}
+
+// likeEscaper 模糊查询时转义用户输入的通配符 搜索 % 或者 _ 时按普通字符匹配
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// likeEscapeLiteral ESCAPE 子句中的反斜杠 mysql 的字符串中反斜杠本身也需要转义
+func likeEscapeLiteral(db *gorm.DB) string {
+	if dialectName(db) == DriverMySQL {
+		return `'\\'`
+	}
+	return `'\'`
+}
+
+// betweenCondition 区间查询 只传了一边时改为 >= 或者 <=
+func betweenCondition(column string, value any) (string, []any, error) {
+	v := reflect.ValueOf(value)
+	if (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) || v.Len() != 2 {
+		return "", nil, NewFrontShowErrMsg("区间查询需要两个值")
+	}
+	start, end := v.Index(0), v.Index(1)
+	switch {
+	case !start.IsZero() && !end.IsZero():
+		return column + " BETWEEN ? AND ?", []any{start.Interface(), end.Interface()}, nil
+	case !start.IsZero():
+		return column + " >= ?", []any{start.Interface()}, nil
+	case !end.IsZero():
+		return column + " <= ?", []any{end.Interface()}, nil
+	}
+	return "", nil, nil
+}
+
+func parseFilterFields(t reflect.Type) ([]filterField, error) {
+	if cached, ok := filterFieldCache.Load(t); ok {
+		return cached.([]filterField), nil
+	}
+	var fields []filterField
+	var walk func(t reflect.Type, index []int) error
+	walk = func(t reflect.Type, index []int) error {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			fieldIndex := append(append([]int{}, index...), i)
+			tag, ok := field.Tag.Lookup("filter")
+			if !ok {
+				if field.Anonymous && field.Type.Kind() == reflect.Struct {
+					if err := walk(field.Type, fieldIndex); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			parsed, err := parseFilterTag(field.Name, tag)
+			if err != nil {
+				return err
+			}
+			parsed.index = fieldIndex
+			fields = append(fields, parsed)
+		}
+		return nil
+	}
+	if err := walk(t, nil); err != nil {
+		return nil, err
+	}
+	filterFieldCache.Store(t, fields)
+	return fields, nil
+}
+
+func parseFilterTag(name string, tag string) (filterField, error) {
+	parts := strings.Split(tag, ",")
+	result := filterField{op: strings.TrimSpace(parts[0]), columns: []string{name}}
+	switch result.op {
+	case FilterLike, FilterIn, FilterBetween:
+	default:
+		if _, ok := filterOperators[result.op]; !ok {
+			return result, errors.Errorf("field %s: unknown filter %q", name, result.op)
+		}
+	}
+	for _, option := range parts[1:] {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		switch key {
+		case "column":
+			result.columns = strings.Split(value, "|")
+		default:
+			return result, errors.Errorf("field %s: unknown filter option %q", name, key)
+		}
+	}
+	return result, nil
+}
+
+// Sort 排序条件 排序字段必须是模型上的字段 SortName 为空时不排序
+func Sort(param OrderParam) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		param.Inject(db)
+		return db
+	}
+}
+
+// statementSchema 当前查询的模型结构 需要先调用 Model
+func statementSchema(db *gorm.DB) (*schema.Schema, error) {
+	if db.Statement.Schema != nil {
+		return db.Statement.Schema, nil
+	}
+	if db.Statement.Model == nil {
+		return nil, errors.New("filter requires db.Model")
+	}
+	if err := db.Statement.Parse(db.Statement.Model); err != nil {
+		return nil, err
+	}
+	return db.Statement.Schema, nil
+}
+
+// lookUpColumn 支持模型的字段名 数据库字段名和小驼峰的写法
+func lookUpColumn(modelSchema *schema.Schema, column string) (string, error) {
+	for _, name := range []string{column, LowerCamelCaseToSnake(column)} {
+		if field := modelSchema.LookUpField(name); field != nil && field.DBName != "" {
+			return field.DBName, nil
+		}
+	}
+	return "", NewFrontShowErrMsg("字段不存在: " + column)
+}
+
+func orderByColumn(db *gorm.DB, param OrderParam) error {
+	modelSchema, err := statementSchema(db)
+	if err != nil {
+		return err
+	}
+	column, err := lookUpColumn(modelSchema, param.SortName)
+	if err != nil {
+		return NewFrontShowErrMsg("需要排序的字段不存在！")
+	}
+	db.Order(clause.OrderByColumn{
+		Column: clause.Column{Name: column},
+		Desc:   strings.EqualFold(param.SortType, "DESC"),
+	})
+	return nil
+}
@@ -8,14 +8,16 @@ import (
 	"github.com/jinzhu/copier"
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
 	"gorm.io/plugin/dbresolver"
 	"log"
 	"os"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -31,6 +33,7 @@ var initOptions *InitGormOptions
 var db *gorm.DB
 
 type GormConfig struct {
+	Driver          string // 数据库驱动 mysql(默认)/postgres/sqlite
 	Host            string
 	Port            int64
 	DataBase        string
@@ -41,6 +44,8 @@ type GormConfig struct {
 	MaxIdleTime     int
 	SetMaxIdleConn  int
 	SetMaxOpenConn  int
+	Replicas        []ReplicaConfig // 从库列表 为空时读写都走主库
+	ReplicaPolicy   string          // 从库负载均衡策略 random(默认)/round-robin/least-connections
 }
 
 func initGormConfig(options InitGormOptions) {
@@ -54,12 +59,41 @@ func GetGormDB() *gorm.DB {
 	return db
 }
 
+// closeGormDB 关闭数据库连接池
+func closeGormDB(target *gorm.DB) error {
+	if target == nil {
+		return nil
+	}
+	sqlDB, err := target.DB()
+	if err != nil {
+		return err
+	}
+	if db == target {
+		db = nil
+	}
+	closeReplicas(target)
+	return sqlDB.Close()
+}
+
 func connectDataBase() *gorm.DB {
-	options := GetConfig().DataBase
-	serverConfig := GetConfig().Server
-	connectUrl := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true&loc=Local&charset=utf8mb4%s",
-		options.User, options.Pass, options.Host, options.Port, options.DataBase, options.OtherSettings)
-	mysqlDialectic := mysql.Open(connectUrl)
+	var hook GormGlobalHook
+	if initOptions != nil {
+		hook = initOptions.GormGlobalHook
+	}
+	gormDb, err := openDataBase(GetConfig().DataBase, GetConfig().Server.Dev, hook)
+	if err != nil {
+		panic(err)
+	}
+	db = gormDb
+	return gormDb
+}
+
+// openDataBase 根据配置打开数据库连接 hook 为空时不注册全局钩子
+func openDataBase(options GormConfig, dev bool, hook GormGlobalHook) (*gorm.DB, error) {
+	dialector, err := openDialector(options)
+	if err != nil {
+		return nil, err
+	}
 	newLogger := logger.New(
 		log.New(os.Stdout, "\r\n", log.LstdFlags), // io writer
 		logger.Config{
@@ -67,36 +101,34 @@ func connectDataBase() *gorm.DB {
 			Colorful:                  true,        // 禁用彩色打印
 			IgnoreRecordNotFoundError: true,
 			ParameterizedQueries:      false,
-			LogLevel:                  BooleanTo(serverConfig.Dev, logger.Info, logger.Warn), // Log level
+			LogLevel:                  BooleanTo(dev, logger.Info, logger.Warn), // Log level
 		},
 	)
 
-	gormDb, err := gorm.Open(mysqlDialectic, &gorm.Config{
+	gormDb, err := gorm.Open(dialector, &gorm.Config{
 		Logger: newLogger,
+		// 违反唯一索引时统一返回 gorm.ErrDuplicatedKey 不用区分数据库
+		TranslateError: true,
 	})
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	if initOptions.GormGlobalHook != nil {
-		initOptions.GormGlobalHook(gormDb)
+	if hook != nil {
+		hook(gormDb)
 	}
 
-	resolverConf := dbresolver.Config{
-		Replicas: []gorm.Dialector{mysqlDialectic}, //  读 操作库，查询类
-		Policy:   dbresolver.RandomPolicy{},        // sources/replicas 负载均衡策略适用于
-	}
-	err = gormDb.Use(dbresolver.Register(resolverConf).
-		SetConnMaxIdleTime(time.Duration(options.MaxIdleTime) * time.Second).
-		SetConnMaxLifetime(time.Duration(options.ConnMaxLifetime) * time.Second).
-		SetMaxIdleConns(options.SetMaxIdleConn).
-		SetMaxOpenConns(options.SetMaxOpenConn))
+	sqlDB, err := gormDb.DB()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	db = gormDb
-	return gormDb
+	options.applyPool(sqlDB)
+	if err = registerReplicas(gormDb, options); err != nil {
+		_ = sqlDB.Close()
+		return nil, err
+	}
+	return gormDb, nil
 }
 
 type InjectServiceConfig struct {
@@ -108,6 +140,7 @@ type InjectServiceConfig struct {
 	UpdateTimeField   string
 	UpdateByField     string
 	DeleteTimeField   string
+	VersionField      string // 乐观锁版本号字段 模型上没有该字段时不使用乐观锁
 	limitOne          string
 }
 
@@ -171,8 +204,14 @@ func (r *Gorm[M, V]) SkipGlobalHook() *Gorm[M, V] {
 	return r
 }
 
+// Primary 本次操作强制走主库 用于写入后立即读取的场景
+func (r *Gorm[M, V]) Primary() *Gorm[M, V] {
+	r.DB = r.DB.Clauses(dbresolver.Write)
+	return r
+}
+
 func (r *Gorm[M, V]) Unscoped() *Gorm[M, V] {
-	r.DB.Unscoped()
+	r.DB = r.DB.Unscoped()
 	return r
 }
 
@@ -183,19 +222,14 @@ func (r *Gorm[M, V]) ReplaceDB(db *gorm.DB) *Gorm[M, V] {
 }
 
 func (r *Gorm[M, V]) CheckHasField(column string) error {
-	structure := r.createModelInstance()
-	v := reflect.ValueOf(structure)
-	t := reflect.TypeOf(structure)
-	// 遍历结构体的字段
-	for i := 0; i < v.NumField(); i++ {
-		field := t.Field(i)
-		tag := field.Tag.Get("gorm")
-		existStr := fmt.Sprintf(`column:%s`, LowerCamelCaseToSnake(column))
-		if strings.Contains(tag, existStr) {
-			return nil
-		}
+	modelSchema, err := statementSchema(r.GetModelDb())
+	if err != nil {
+		return err
 	}
-	return NewFrontShowErrMsg("需要排序的字段不存在！")
+	if _, err = lookUpColumn(modelSchema, column); err != nil {
+		return NewFrontShowErrMsg("需要排序的字段不存在！")
+	}
+	return nil
 }
 
 func (r *Gorm[M, V]) FindOneByPrimaryKey(id int64) (error, M) {
@@ -308,11 +342,19 @@ func (r *Gorm[M, V]) FindVoListByPage(param PageParam, conditions ...func(*gorm.
 }
 
 // UpdateByPrimaryKey   更新非零字段 false 0 "" 均不会被更新
+// 模型有版本号字段时使用乐观锁 版本号不一致返回 CURD_VERSION_CONFLICT_ERROR 没有版本号返回 CURD_VERSION_REQUIRED_ERROR
 func (r *Gorm[M, V]) UpdateByPrimaryKey(id int64, entity M) (error, int64) {
 	db := r.GetModelDb()
 	r.removePrimaryKey(&entity)
+	db, versioned, err := r.withVersion(db, &entity)
+	if err != nil {
+		return err, 0
+	}
 	tx := db.Where(fmt.Sprintf("%s = ?", r.config.PrimaryKeyField), id).
 		Updates(entity)
+	if tx.Error == nil && versioned {
+		return r.checkVersionConflict(id, tx.RowsAffected), tx.RowsAffected
+	}
 	return tx.Error, tx.RowsAffected
 }
 
@@ -326,9 +368,14 @@ func (r *Gorm[M, V]) UpdateBy(entity M, conditions func(*gorm.DB) *gorm.DB, sele
 }
 
 // SaveByPrimaryKey 更新所有字段 除了omitKeys
+// 模型有版本号字段时使用乐观锁 版本号不一致返回 CURD_VERSION_CONFLICT_ERROR 没有版本号返回 CURD_VERSION_REQUIRED_ERROR
 func (r *Gorm[M, V]) SaveByPrimaryKey(id int64, entity M, omitKey ...string) (error, int64) {
 	db := r.GetModelDb()
 	r.removePrimaryKey(&entity)
+	db, versioned, err := r.withVersion(db, &entity)
+	if err != nil {
+		return err, 0
+	}
 	var omitFields = []string{
 		r.config.PrimaryKeyField,
 		r.config.CreateDeptField,
@@ -342,6 +389,9 @@ func (r *Gorm[M, V]) SaveByPrimaryKey(id int64, entity M, omitKey ...string) (er
 	tx := db.Where(fmt.Sprintf("%s = ?", r.config.PrimaryKeyField), id).Select("*").
 		Omit(omitFields...).
 		Updates(entity)
+	if tx.Error == nil && versioned {
+		return r.checkVersionConflict(id, tx.RowsAffected), tx.RowsAffected
+	}
 	return tx.Error, tx.RowsAffected
 }
 
@@ -479,6 +529,7 @@ func mergeInjectServiceDefaultConfig(config ...InjectServiceConfig) InjectServic
 		UpdateByField:     "update_by",
 		UpdateTimeField:   "update_time",
 		DeleteTimeField:   "delete_time",
+		VersionField:      "version",
 		limitOne:          "limit 1",
 	}
 	// 如果传入了配置，使用最后一个配置项覆盖默认值
@@ -505,16 +556,41 @@ func mergeInjectServiceDefaultConfig(config ...InjectServiceConfig) InjectServic
 		if lastConfig.DeleteTimeField != "" {
 			defaultConfig.DeleteTimeField = lastConfig.DeleteTimeField
 		}
+		if lastConfig.VersionField != "" {
+			defaultConfig.VersionField = lastConfig.VersionField
+		}
 	}
 	return defaultConfig
 }
 
 type Array[T string | int32 | int8 | int64] []T
 
+// GormDataType gorm 通用数据类型
+func (Array[T]) GormDataType() string {
+	return "json"
+}
+
+// GormDBDataType 不同数据库下的列类型
+func (Array[T]) GormDBDataType(db *gorm.DB, _ *schema.Field) string {
+	switch dialectName(db) {
+	case DriverPostgres:
+		return "jsonb"
+	case DriverSQLite:
+		return "text"
+	}
+	return "json"
+}
+
 // Scan 实现 sql.Scanner 接口，Scan 将 value 扫描至 Jsonb
 func (a *Array[T]) Scan(value interface{}) error {
-	bytes, ok := value.([]byte)
-	if !ok {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
 		return errors.New(fmt.Sprint("Failed to scan Array value:", value))
 	}
 	if len(bytes) > 0 {
@@ -532,6 +608,18 @@ func (a Array[T]) Value() (driver.Value, error) {
 	return convertor.ToString(a), nil
 }
 
+// EqualExpr 字段的值和数组相同 用于比较并更新 mysql 和 postgres 的 json 字段需要把参数转换成 json 再比较
+func (a Array[T]) EqualExpr(db *gorm.DB, column string) clause.Expr {
+	value, _ := a.Value()
+	switch dialectName(db) {
+	case DriverMySQL:
+		return gorm.Expr(column+" = CAST(? AS JSON)", value)
+	case DriverPostgres:
+		return gorm.Expr(column+" = CAST(? AS jsonb)", value)
+	}
+	return gorm.Expr(column+" = ?", value)
+}
+
 // FileURL 自定义文件URL类型
 type FileURL string
 
@@ -642,16 +730,44 @@ func (i IntBool) Value() (driver.Value, error) {
 }
 
 // Scan implements the sql.Scanner interface,
-// and turns the int incoming from MySQL into an IntBool
+// and turns the int incoming from the database into an IntBool
 func (i *IntBool) Scan(src interface{}) error {
-	v, ok := src.(int64)
-	if !ok {
-		return NewFrontShowErrMsg("FilePath 类型转换失败")
+	var v int64
+	switch value := src.(type) {
+	case nil:
+		v = IntBoolFalse
+	case int64:
+		v = value
+	case int32:
+		v = int64(value)
+	case int16:
+		v = int64(value)
+	case bool:
+		v = BooleanTo(value, IntBoolTrue, IntBoolFalse)
+	case []byte, string:
+		parsed, err := strconv.ParseInt(convertor.ToString(value), 10, 64)
+		if err != nil {
+			return NewFrontShowErrMsg("IntBool 类型转换失败")
+		}
+		v = parsed
+	default:
+		return NewFrontShowErrMsg("IntBool 类型转换失败")
 	}
 	*i = v == IntBoolTrue // 1 -> true, otherwise false
 	return nil
 }
 
+// GormDBDataType 不同数据库下的列类型
+func (IntBool) GormDBDataType(db *gorm.DB, _ *schema.Field) string {
+	switch dialectName(db) {
+	case DriverPostgres:
+		return "smallint"
+	case DriverSQLite:
+		return "integer"
+	}
+	return "tinyint"
+}
+
 type Time struct {
 	time.Time
 }
@@ -679,11 +795,51 @@ func (mt Time) Value() (driver.Value, error) {
 	return mt.Time, nil
 }
 
+// 数据库以字符串返回时间时尝试的格式 SQLite 没有原生的时间类型
+var timeScanLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
 func (mt *Time) Scan(value interface{}) error {
-	t, ok := value.(time.Time)
-	if !ok {
+	var str string
+	switch v := value.(type) {
+	case nil:
+		mt.Time = time.Time{}
+		return nil
+	case time.Time:
+		mt.Time = v
+		return nil
+	case []byte:
+		str = string(v)
+	case string:
+		str = v
+	default:
 		return errors.New("type assertion to time.Time failed")
 	}
-	mt.Time = t
-	return nil
+	for _, layout := range timeScanLayouts {
+		if t, err := time.ParseInLocation(layout, str, time.Local); err == nil {
+			mt.Time = t
+			return nil
+		}
+	}
+	return errors.New(fmt.Sprint("Failed to scan Time value:", value))
+}
+
+// GormDataType gorm 通用数据类型
+func (Time) GormDataType() string {
+	return string(schema.Time)
+}
+
+// GormDBDataType 不同数据库下的列类型
+func (Time) GormDBDataType(db *gorm.DB, _ *schema.Field) string {
+	switch dialectName(db) {
+	case DriverPostgres:
+		return "timestamptz"
+	}
+	return "datetime"
 }
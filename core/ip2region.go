@@ -8,11 +8,14 @@ import (
 
 var ip2RegionSearcher *xdb.Searcher
 
-func checkIp2RegionFile() {
-	if GetConfig().Ip2RegionConfig.FilePath == "" {
-		GetConfig().Ip2RegionConfig.FilePath = "./ip2region.xdb"
+func checkIp2RegionFile(cfg *Config) error {
+	if cfg.Ip2RegionConfig.FilePath == "" {
+		cfg.Ip2RegionConfig.FilePath = "./ip2region.xdb"
 	}
-	CheckFile(GetConfig().Ip2RegionConfig.FilePath)
+	if _, err := os.Stat(cfg.Ip2RegionConfig.FilePath); err != nil {
+		return fmt.Errorf("检测文件 '%s' 时发生错误: %w", cfg.Ip2RegionConfig.FilePath, err)
+	}
+	return nil
 }
 
 // LoadContentFromFile 从指定文件中加载内容
@@ -45,5 +48,8 @@ func IPParse(ip string) (string, error) {
 	if ip2RegionSearcher == nil {
 		initIp2Region()
 	}
+	if ip2RegionSearcher == nil {
+		return "", fmt.Errorf("ip2region is not initialized")
+	}
 	return ip2RegionSearcher.SearchByStr(ip)
 }
@@ -0,0 +1,334 @@
+package core
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// 支持的非对称签名算法
+const (
+	JwtAlgorithmRS256 = "RS256"
+	JwtAlgorithmES256 = "ES256"
+	JwtAlgorithmEdDSA = "EdDSA"
+)
+
+// JwksPath 公钥发布地址 不受 GlobalPrefix 影响
+const JwksPath = "/.well-known/jwks.json"
+
+var jwtKeys *JwtKeySet
+
+// jwtKey 一个签名密钥 没有私钥时只用于验证
+type jwtKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// JwtKeySet 签名与验证使用的密钥
+// token 头上有 kid 时使用对应的密钥验证 并且算法必须和密钥一致 没有 kid 时使用 JwtKey 按 HS256 验证
+type JwtKeySet struct {
+	secret  []byte
+	signing *jwtKey
+	keys    map[string]*jwtKey
+	kids    []string
+}
+
+// Jwk 公钥 格式参考 RFC 7517
+type Jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type Jwks struct {
+	Keys []Jwk `json:"keys"`
+}
+
+func getJwtKeySet() *JwtKeySet {
+	if jwtKeys == nil {
+		keys, err := newJwtKeySet(GetConfig().Jwt)
+		if err != nil {
+			zap.L().Error("jwt key error", zap.Error(err))
+			panic(err)
+		}
+		jwtKeys = keys
+	}
+	return jwtKeys
+}
+
+func newJwtKeySet(config JwtConfig) (*JwtKeySet, error) {
+	set := &JwtKeySet{
+		secret: []byte(config.JwtKey),
+		keys:   map[string]*jwtKey{},
+	}
+	for _, item := range config.Keys {
+		if item.Kid == "" {
+			return nil, errors.New("jwt key kid is required")
+		}
+		if _, ok := set.keys[item.Kid]; ok {
+			return nil, errors.Errorf("jwt key %s is duplicated", item.Kid)
+		}
+		key, err := parseJwtKey(item)
+		if err != nil {
+			return nil, errors.Wrapf(err, "jwt key %s", item.Kid)
+		}
+		set.keys[item.Kid] = key
+		set.kids = append(set.kids, item.Kid)
+	}
+	if config.SigningKid != "" {
+		key, ok := set.keys[config.SigningKid]
+		if !ok {
+			return nil, errors.Errorf("jwt signing key %s not found", config.SigningKid)
+		}
+		if key.private == nil {
+			return nil, errors.Errorf("jwt signing key %s has no private key", config.SigningKid)
+		}
+		set.signing = key
+	}
+	return set, nil
+}
+
+func parseJwtKey(config JwtKeyConfig) (*jwtKey, error) {
+	switch config.Algorithm {
+	case JwtAlgorithmRS256, JwtAlgorithmES256, JwtAlgorithmEdDSA:
+	default:
+		return nil, errors.Errorf("unsupported algorithm %q", config.Algorithm)
+	}
+	key := &jwtKey{kid: config.Kid}
+	var (
+		privatePEM, publicPEM []byte
+		err                   error
+	)
+	if config.PrivateKey != "" {
+		if privatePEM, err = readKeyPEM(config.PrivateKey); err != nil {
+			return nil, err
+		}
+	}
+	if config.PublicKey != "" {
+		if publicPEM, err = readKeyPEM(config.PublicKey); err != nil {
+			return nil, err
+		}
+	}
+	if privatePEM == nil && publicPEM == nil {
+		return nil, errors.New("private key or public key is required")
+	}
+	switch config.Algorithm {
+	case JwtAlgorithmRS256:
+		key.method = jwt.SigningMethodRS256
+		if privatePEM != nil {
+			if key.private, err = jwt.ParseRSAPrivateKeyFromPEM(privatePEM); err != nil {
+				return nil, err
+			}
+		}
+		if publicPEM != nil {
+			if key.public, err = jwt.ParseRSAPublicKeyFromPEM(publicPEM); err != nil {
+				return nil, err
+			}
+		}
+	case JwtAlgorithmES256:
+		key.method = jwt.SigningMethodES256
+		if privatePEM != nil {
+			if key.private, err = jwt.ParseECPrivateKeyFromPEM(privatePEM); err != nil {
+				return nil, err
+			}
+		}
+		if publicPEM != nil {
+			if key.public, err = jwt.ParseECPublicKeyFromPEM(publicPEM); err != nil {
+				return nil, err
+			}
+		}
+	case JwtAlgorithmEdDSA:
+		key.method = jwt.SigningMethodEdDSA
+		if privatePEM != nil {
+			privateKey, err := jwt.ParseEdPrivateKeyFromPEM(privatePEM)
+			if err != nil {
+				return nil, err
+			}
+			key.private = privateKey.(crypto.Signer)
+		}
+		if publicPEM != nil {
+			if key.public, err = jwt.ParseEdPublicKeyFromPEM(publicPEM); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if key.private != nil {
+		public := key.private.Public()
+		if key.public != nil && !public.(interface{ Equal(crypto.PublicKey) bool }).Equal(key.public) {
+			return nil, errors.New("public key does not match private key")
+		}
+		key.public = public
+	}
+	if ecKey, ok := key.public.(*ecdsa.PublicKey); ok && ecKey.Curve != elliptic.P256() {
+		return nil, errors.New("ES256 requires a P-256 key")
+	}
+	return key, nil
+}
+
+// readKeyPEM 密钥可以直接配置 PEM 内容 也可以配置文件路径
+func readKeyPEM(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
+
+// Sign 使用当前的签名密钥签名 没有配置 SigningKid 时使用 JwtKey HS256 签名
+func (s *JwtKeySet) Sign(claims jwt.Claims) (string, error) {
+	if s.signing == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	}
+	token := jwt.NewWithClaims(s.signing.method, claims)
+	token.Header["kid"] = s.signing.kid
+	return token.SignedString(s.signing.private)
+}
+
+// KeyFunc 根据 token 头上的 kid 选择验证密钥
+func (s *JwtKeySet) KeyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if len(s.secret) == 0 || token.Method.Alg() != jwt.SigningMethodHS256.Alg() {
+			return nil, errors.New("token kid is required")
+		}
+		return s.secret, nil
+	}
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, errors.Errorf("unknown token kid %s", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, errors.Errorf("token kid %s requires %s", kid, key.method.Alg())
+	}
+	return key.public, nil
+}
+
+// JWKS 全部验证公钥 HS256 的密钥不会发布
+func (s *JwtKeySet) JWKS() Jwks {
+	result := Jwks{Keys: make([]Jwk, 0, len(s.kids))}
+	for _, kid := range s.kids {
+		key := s.keys[kid]
+		jwk := Jwk{Kid: kid, Use: "sig", Alg: key.method.Alg()}
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64URL(public.N.Bytes())
+			jwk.E = base64URL(big.NewInt(int64(public.E)).Bytes())
+		case *ecdsa.PublicKey:
+			ecdh, err := public.ECDH()
+			if err != nil {
+				continue
+			}
+			// 未压缩的点 0x04 || X || Y
+			point := ecdh.Bytes()
+			size := (len(point) - 1) / 2
+			jwk.Kty, jwk.Crv = "EC", "P-256"
+			jwk.X = base64URL(point[1 : 1+size])
+			jwk.Y = base64URL(point[1+size:])
+		case ed25519.PublicKey:
+			jwk.Kty, jwk.Crv = "OKP", "Ed25519"
+			jwk.X = base64URL(public)
+		default:
+			continue
+		}
+		result.Keys = append(result.Keys, jwk)
+	}
+	return result
+}
+
+// PublicKey 解析公钥 支持 RSA、EC(P-256/P-384/P-521) 和 Ed25519
+func (j Jwk) PublicKey() (crypto.PublicKey, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err := decodeBase64URL(j.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBase64URL(j.E)
+		if err != nil {
+			return nil, err
+		}
+		if len(n) == 0 || len(e) == 0 || len(e) > 4 {
+			return nil, errors.Errorf("invalid rsa key %s", j.Kid)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		curves := map[string]struct {
+			curve elliptic.Curve
+			ecdh  ecdh.Curve
+		}{
+			"P-256": {elliptic.P256(), ecdh.P256()},
+			"P-384": {elliptic.P384(), ecdh.P384()},
+			"P-521": {elliptic.P521(), ecdh.P521()},
+		}
+		curve, ok := curves[j.Crv]
+		if !ok {
+			return nil, errors.Errorf("unsupported curve %q", j.Crv)
+		}
+		x, err := decodeBase64URL(j.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBase64URL(j.Y)
+		if err != nil {
+			return nil, err
+		}
+		size := (curve.curve.Params().BitSize + 7) / 8
+		if len(x) != size || len(y) != size {
+			return nil, errors.Errorf("invalid ec key %s", j.Kid)
+		}
+		// 借助 ecdh 校验点在曲线上
+		if _, err = curve.ecdh.NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve.curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "OKP":
+		x, err := decodeBase64URL(j.X)
+		if err != nil {
+			return nil, err
+		}
+		if j.Crv != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, errors.Errorf("unsupported okp key %s", j.Kid)
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, errors.Errorf("unsupported key type %q", j.Kty)
+	}
+}
+
+// JwksHandler 发布验证公钥 其他服务可以自己验证 token
+func JwksHandler(c echo.Context) error {
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=300")
+	if deps := GetServerDeps(c); deps != nil {
+		return c.JSON(http.StatusOK, deps.JwtKeys.JWKS())
+	}
+	return c.JSON(http.StatusOK, getJwtKeySet().JWKS())
+}
+
+func base64URL(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeBase64URL 兼容带填充的写法
+func decodeBase64URL(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"time"
+)
+
+// 默认的优雅关闭等待时长
+const defaultShutdownTimeout = 10 * time.Second
+
+// LifecycleHook 服务生命周期钩子
+// OnStart 在服务开始监听之前按注册顺序执行，任意一个返回错误则服务不启动
+// OnStop 在服务关闭之后按注册的逆序执行，所有钩子都会被执行
+type LifecycleHook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// 核心内置的钩子 最先注册 所以最后关闭
+func coreLifecycleHooks(deps *ServerDeps) []LifecycleHook {
+	return []LifecycleHook{
+		{Name: "Default", OnStop: func(_ context.Context) error {
+			releaseDefaultDeps(deps)
+			return nil
+		}},
+		{Name: "Gorm", OnStop: func(_ context.Context) error {
+			return closeGormDB(deps.DB)
+		}},
+		{Name: "Redis", OnStop: func(_ context.Context) error {
+			return closeRedis(deps.Redis)
+		}},
+	}
+}
+
+// runStartHooks 按顺序执行 OnStart 返回已经成功启动的钩子数量
+func runStartHooks(ctx context.Context, hooks []LifecycleHook) (int, error) {
+	for i, hook := range hooks {
+		if hook.OnStart == nil {
+			continue
+		}
+		if err := hook.OnStart(ctx); err != nil {
+			return i, fmt.Errorf("lifecycle hook %s start: %w", hook.Name, err)
+		}
+	}
+	return len(hooks), nil
+}
+
+// runStopHooks 逆序执行 OnStop 出错不会中断 错误最终合并返回
+func runStopHooks(ctx context.Context, hooks []LifecycleHook) error {
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hook := hooks[i]
+		if hook.OnStop == nil {
+			continue
+		}
+		if err := hook.OnStop(ctx); err != nil {
+			zap.L().Error("lifecycle hook stop error", zap.String("hook", hook.Name), zap.Error(err))
+			errs = append(errs, fmt.Errorf("lifecycle hook %s stop: %w", hook.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// 获取优雅关闭的等待时长 优先使用 ServerRunOption 其次是配置文件
+func shutdownTimeout(option ServerRunOption, serverConfig ServerConfig) time.Duration {
+	if option.ShutdownTimeout > 0 {
+		return option.ShutdownTimeout
+	}
+	if serverConfig.ShutdownTimeout > 0 {
+		return time.Duration(serverConfig.ShutdownTimeout) * time.Second
+	}
+	return defaultShutdownTimeout
+}
@@ -260,6 +260,14 @@ func initLogMiddleware(options LoggerOptions) {
 	loggerOptions = &options
 }
 
+// getContextLoggerOptions 处理当前请求的 Server 的日志配置
+func getContextLoggerOptions(c echo.Context) *LoggerOptions {
+	if deps := GetServerDeps(c); deps != nil {
+		return &deps.loggerOptions
+	}
+	return loggerOptions
+}
+
 type RequestInfo struct {
 	Title           string `json:"title"`           // 标题
 	BusinessType    int64  `json:"businessType"`    // 业务类型
@@ -317,7 +325,7 @@ func Log(title string, operateType ...BusinessType) echo.MiddlewareFunc {
 					errMsg = s
 				}
 			}
-			if loggerOptions != nil && loggerOptions.LoggerSaver != nil {
+			if options := getContextLoggerOptions(c); options != nil && options.LoggerSaver != nil {
 				_operateType := methodBusinessTypeMap[c.Request().Method]
 				if len(operateType) != 0 {
 					_operateType = operateType[0]
@@ -325,7 +333,7 @@ func Log(title string, operateType ...BusinessType) echo.MiddlewareFunc {
 				if _operateType == 0 {
 					_operateType = BusinessTypeAny
 				}
-				loggerOptions.LoggerSaver(
+				options.LoggerSaver(
 					RequestInfo{
 						Title:           title,
 						BusinessType:    int64(_operateType),
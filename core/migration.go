@@ -0,0 +1,150 @@
+package core
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"sort"
+	"time"
+)
+
+// Migration 一个版本的数据库迁移
+// Version 按字符串顺序执行 推荐使用 20250101000001 这种时间格式
+// Up/Down 都在事务中执行 不支持事务DDL的数据库(MySQL) DDL 失败时需要手动处理
+type Migration struct {
+	Version     string
+	Description string
+	Up          func(tx *gorm.DB) error
+	Down        func(tx *gorm.DB) error
+}
+
+// MigrationRecord 已经执行过的迁移记录
+type MigrationRecord struct {
+	Version     string    `gorm:"column:version;primaryKey;size:64;comment:版本号" json:"version"` // 版本号
+	Description string    `gorm:"column:description;size:255;comment:描述" json:"description"`    // 描述
+	AppliedAt   time.Time `gorm:"column:applied_at;comment:执行时间" json:"appliedAt"`              // 执行时间
+}
+
+// TableName 迁移记录表
+func (*MigrationRecord) TableName() string {
+	return "sys_migrations"
+}
+
+// MigrationStatus 迁移状态
+type MigrationStatus struct {
+	Version     string
+	Description string
+	Applied     bool
+	AppliedAt   time.Time
+}
+
+// Migrator 数据库迁移执行器
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator 创建迁移执行器 迁移按 Version 排序
+func NewMigrator(db *gorm.DB, migrations ...Migration) *Migrator {
+	sorted := append([]Migration{}, migrations...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+	return &Migrator{
+		db:         db.WithContext(NewSkipGormGlobalHookContext()),
+		migrations: sorted,
+	}
+}
+
+func (m *Migrator) applied() (map[string]MigrationRecord, error) {
+	if err := m.db.AutoMigrate(&MigrationRecord{}); err != nil {
+		return nil, err
+	}
+	var records []MigrationRecord
+	if err := m.db.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[string]MigrationRecord, len(records))
+	for _, record := range records {
+		result[record.Version] = record
+	}
+	return result, nil
+}
+
+// Up 执行全部未执行的迁移
+func (m *Migrator) Up() error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err = m.db.Transaction(func(tx *gorm.DB) error {
+			if migration.Up != nil {
+				if err := migration.Up(tx); err != nil {
+					return err
+				}
+			}
+			return tx.Create(&MigrationRecord{
+				Version:     migration.Version,
+				Description: migration.Description,
+				AppliedAt:   time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration %s up: %w", migration.Version, err)
+		}
+		zap.L().Info("migration applied", zap.String("version", migration.Version), zap.String("description", migration.Description))
+	}
+	return nil
+}
+
+// Down 回滚最近执行的 steps 个迁移
+func (m *Migrator) Down(steps int) error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+	for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == nil {
+			return fmt.Errorf("migration %s 不支持回滚", migration.Version)
+		}
+		err = m.db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&MigrationRecord{Version: migration.Version}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration %s down: %w", migration.Version, err)
+		}
+		zap.L().Info("migration rolled back", zap.String("version", migration.Version), zap.String("description", migration.Description))
+		steps--
+	}
+	return nil
+}
+
+// Status 返回全部迁移的执行状态
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		record, ok := applied[migration.Version]
+		result = append(result, MigrationStatus{
+			Version:     migration.Version,
+			Description: migration.Description,
+			Applied:     ok,
+			AppliedAt:   record.AppliedAt,
+		})
+	}
+	return result, nil
+}
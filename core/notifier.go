@@ -0,0 +1,197 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"go.uber.org/zap"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// 通知渠道
+const (
+	NotifyChannelEmail = "email"
+	NotifyChannelSms   = "sms"
+)
+
+// NotifyMessage 一条通知
+type NotifyMessage struct {
+	Channel  string            // 渠道 email 或者 sms
+	To       string            // 邮箱地址或者手机号
+	Subject  string            // 标题 短信不使用
+	Content  string            // 正文
+	Template string            // 模板编号 短信服务商一般要求使用模板
+	Params   map[string]string // 模板参数
+}
+
+// Notifier 发送邮件和短信 通过 ServerRunOption.Notifier 替换成自己的实现
+type Notifier interface {
+	Send(ctx context.Context, message NotifyMessage) error
+}
+
+var notifier Notifier
+
+func initNotifier(n Notifier) {
+	if n == nil {
+		n = defaultNotifier(GetConfig().Notify)
+	}
+	notifier = n
+}
+
+// GetNotifier 当前使用的 Notifier 没有配置时只写日志
+func GetNotifier() Notifier {
+	if notifier == nil {
+		initNotifier(nil)
+	}
+	return notifier
+}
+
+// defaultNotifier 配置了 SMTP 时邮件通过 SMTP 发送 其他渠道只写日志
+func defaultNotifier(config NotifyConfig) Notifier {
+	if config.Smtp.Host == "" {
+		return LogNotifier{}
+	}
+	return ChannelNotifier{
+		NotifyChannelEmail: NewSmtpNotifier(config.Smtp),
+		NotifyChannelSms:   LogNotifier{},
+	}
+}
+
+// ChannelNotifier 按渠道分发给不同的 Notifier
+type ChannelNotifier map[string]Notifier
+
+func (n ChannelNotifier) Send(ctx context.Context, message NotifyMessage) error {
+	target, ok := n[message.Channel]
+	if !ok {
+		return fmt.Errorf("notify channel %q not supported", message.Channel)
+	}
+	return target.Send(ctx, message)
+}
+
+// LogNotifier 只把通知写到日志 开发环境使用 不要在生产环境使用 验证码会出现在日志中
+type LogNotifier struct{}
+
+func (LogNotifier) Send(_ context.Context, message NotifyMessage) error {
+	zap.L().Info("notify",
+		zap.String("channel", message.Channel),
+		zap.String("to", message.To),
+		zap.String("subject", message.Subject),
+		zap.String("content", message.Content),
+		zap.String("template", message.Template),
+		zap.Any("params", message.Params),
+	)
+	return nil
+}
+
+// SmtpNotifier 通过 SMTP 发送纯文本邮件
+type SmtpNotifier struct {
+	config SmtpConfig
+}
+
+func NewSmtpNotifier(config SmtpConfig) SmtpNotifier {
+	if config.Port == 0 {
+		config.Port = BooleanTo(config.SSL, 465, 25)
+	}
+	if config.From == "" {
+		config.From = config.Username
+	}
+	return SmtpNotifier{config: config}
+}
+
+func (n SmtpNotifier) Send(ctx context.Context, message NotifyMessage) error {
+	if message.Channel != NotifyChannelEmail {
+		return fmt.Errorf("smtp notifier does not support channel %q", message.Channel)
+	}
+	from, err := mail.ParseAddress(n.config.From)
+	if err != nil {
+		return fmt.Errorf("invalid smtp from address: %w", err)
+	}
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("invalid email address: %w", err)
+	}
+	client, err := n.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if n.config.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)); err != nil {
+			return err
+		}
+	}
+	if err = client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err = client.Rcpt(to.Address); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = writer.Write(buildMail(from, to, message)); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (n SmtpNotifier) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(n.config.Host, strconv.Itoa(n.config.Port))
+	tlsConfig := &tls.Config{ServerName: n.config.Host}
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	var conn net.Conn
+	var err error
+	if n.config.SSL {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, n.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if ok, _ := client.Extension("STARTTLS"); ok && !n.config.SSL {
+		if err = client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+// buildMail 拼接邮件头和 base64 编码的正文
+func buildMail(from, to *mail.Address, message NotifyMessage) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("From: " + from.String() + "\r\n")
+	buf.WriteString("To: " + to.String() + "\r\n")
+	buf.WriteString("Subject: " + mime.BEncoding.Encode("UTF-8", message.Subject) + "\r\n")
+	buf.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+	body := base64.StdEncoding.EncodeToString([]byte(message.Content))
+	for len(body) > 76 {
+		buf.WriteString(body[:76] + "\r\n")
+		body = body[76:]
+	}
+	buf.WriteString(body + "\r\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,327 @@
+package core
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"encoding/json"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	oidcStateExpire     = 10 * time.Minute
+	oidcDiscoveryExpire = time.Hour
+	// 遇到未知的 kid 时重新获取公钥 最多每分钟一次
+	oidcJwksRefreshInterval = time.Minute
+)
+
+// ID Token 允许的签名算法 不接受 none 和 HS 系列
+var oidcAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// OidcDiscovery 身份提供方的配置 参考 OpenID Connect Discovery 1.0
+type OidcDiscovery struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JwksUri                           string   `json:"jwks_uri"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+// oidcState 发起登录时保存在 Redis 中 回调时校验之后删除
+type oidcState struct {
+	Provider string `json:"provider"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+type oidcTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IdToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// OidcClient OpenID Connect 授权码模式的客户端 使用 PKCE 并且按身份提供方的公钥校验 ID Token
+type OidcClient struct {
+	config       OidcConfig
+	client       *http.Client
+	mu           sync.Mutex
+	discovery    *OidcDiscovery
+	discoveredAt time.Time
+	keys         map[string]Jwk
+	keysAt       time.Time
+}
+
+func NewOidcClient(config OidcConfig) *OidcClient {
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "profile", "email"}
+	} else if !slices.Contains(config.Scopes, "openid") {
+		config.Scopes = append([]string{"openid"}, config.Scopes...)
+	}
+	return &OidcClient{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// AuthURL 生成跳转到身份提供方的授权地址 state nonce 和 PKCE 的 code_verifier 保存在 Redis 中
+func (c *OidcClient) AuthURL(ctx context.Context) (string, error) {
+	discovery, err := c.Discovery(ctx)
+	if err != nil {
+		return "", err
+	}
+	saved := oidcState{Provider: c.config.Name}
+	state, err := randomToken()
+	if err == nil {
+		saved.Nonce, err = randomToken()
+	}
+	if err == nil {
+		saved.Verifier, err = randomToken()
+	}
+	if err != nil {
+		return "", err
+	}
+	if !oidcStates(ctx).XSetCodeEX(state, saved, oidcStateExpire) {
+		return "", NewErrCode(SERVER_COMMON_ERROR)
+	}
+	challenge := sha256.Sum256([]byte(saved.Verifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {c.config.ClientId},
+		"redirect_uri":          {c.config.RedirectUrl},
+		"scope":                 {strings.Join(c.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {saved.Nonce},
+		"code_challenge":        {base64URL(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+	separator := BooleanTo(strings.Contains(discovery.AuthorizationEndpoint, "?"), "&", "?")
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange 用回调带回来的 code 和 state 换取并校验 ID Token 返回其中的声明
+// 身份提供方提供 userinfo 接口时 ID Token 中没有的声明从 userinfo 中补充
+func (c *OidcClient) Exchange(ctx context.Context, code, state string) (jwt.MapClaims, error) {
+	saved, err := c.consumeState(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	discovery, err := c.Discovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := c.requestToken(ctx, discovery, code, saved.Verifier)
+	if err != nil {
+		zap.L().Error("oidc token request failed", zap.String("provider", c.config.Name), zap.Error(err))
+		return nil, NewErrCode(OIDC_TOKEN_ERROR)
+	}
+	claims, err := c.verifyIdToken(ctx, discovery, token.IdToken, saved.Nonce)
+	if err != nil {
+		zap.L().Error("oidc id token invalid", zap.String("provider", c.config.Name), zap.Error(err))
+		return nil, NewErrCode(OIDC_TOKEN_ERROR)
+	}
+	if discovery.UserinfoEndpoint != "" && token.AccessToken != "" {
+		info, err := c.userinfo(ctx, discovery, token.AccessToken)
+		if err != nil {
+			// userinfo 只用来补充资料 失败时使用 ID Token 中的声明
+			zap.L().Warn("oidc userinfo request failed", zap.String("provider", c.config.Name), zap.Error(err))
+		} else if info["sub"] == claims["sub"] {
+			for name, value := range info {
+				if _, ok := claims[name]; !ok {
+					claims[name] = value
+				}
+			}
+		}
+	}
+	return claims, nil
+}
+
+// Discovery 获取身份提供方的配置 缓存一小时
+func (c *OidcClient) Discovery(ctx context.Context) (*OidcDiscovery, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.discovery != nil && time.Since(c.discoveredAt) < oidcDiscoveryExpire {
+		return c.discovery, nil
+	}
+	var discovery OidcDiscovery
+	endpoint := strings.TrimRight(c.config.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := c.getJSON(ctx, endpoint, "", &discovery); err != nil {
+		return nil, errors.Wrap(err, "oidc discovery")
+	}
+	if strings.TrimRight(discovery.Issuer, "/") != strings.TrimRight(c.config.Issuer, "/") {
+		return nil, errors.Errorf("oidc discovery issuer %q does not match %q", discovery.Issuer, c.config.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JwksUri == "" {
+		return nil, errors.New("oidc discovery is incomplete")
+	}
+	c.discovery, c.discoveredAt = &discovery, time.Now()
+	return c.discovery, nil
+}
+
+// consumeState state 只能使用一次 并且必须是当前身份提供方发起的
+func (c *OidcClient) consumeState(ctx context.Context, state string) (oidcState, error) {
+	if state == "" {
+		return oidcState{}, NewErrCode(OIDC_STATE_ERROR)
+	}
+	states := oidcStates(ctx)
+	have, saved := states.XCodeGet(state)
+	if !have || !states.XCodeDel(state) || saved.Provider != c.config.Name {
+		return oidcState{}, NewErrCode(OIDC_STATE_ERROR)
+	}
+	return saved, nil
+}
+
+func (c *OidcClient) requestToken(ctx context.Context, discovery *OidcDiscovery, code, verifier string) (oidcTokenResponse, error) {
+	var token oidcTokenResponse
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.config.RedirectUrl},
+		"client_id":     {c.config.ClientId},
+		"code_verifier": {verifier},
+	}
+	// 默认使用 client_secret_basic 身份提供方只支持 client_secret_post 时放在表单中
+	methods := discovery.TokenEndpointAuthMethodsSupported
+	basic := len(methods) == 0 || slices.Contains(methods, "client_secret_basic") || !slices.Contains(methods, "client_secret_post")
+	if c.config.ClientSecret != "" && !basic {
+		form.Set("client_secret", c.config.ClientSecret)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return token, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	if c.config.ClientSecret != "" && basic {
+		request.SetBasicAuth(url.QueryEscape(c.config.ClientId), url.QueryEscape(c.config.ClientSecret))
+	}
+	response, err := c.client.Do(request)
+	if err != nil {
+		return token, err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return token, err
+	}
+	if err = json.Unmarshal(body, &token); err != nil {
+		return token, errors.Errorf("token response status %d", response.StatusCode)
+	}
+	if response.StatusCode != http.StatusOK || token.Error != "" {
+		return token, errors.Errorf("token response status %d %s %s", response.StatusCode, token.Error, token.ErrorDescription)
+	}
+	if token.IdToken == "" {
+		return token, errors.New("token response has no id_token")
+	}
+	return token, nil
+}
+
+// verifyIdToken 校验签名 iss aud exp 和 nonce
+func (c *OidcClient) verifyIdToken(ctx context.Context, discovery *OidcDiscovery, idToken, nonce string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (any, error) {
+		return c.publicKey(ctx, discovery, token)
+	},
+		jwt.WithValidMethods(oidcAlgorithms),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(c.config.ClientId),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if value, _ := claims["nonce"].(string); value == "" || value != nonce {
+		return nil, errors.New("id token nonce mismatch")
+	}
+	if sub, _ := claims["sub"].(string); sub == "" {
+		return nil, errors.New("id token has no sub")
+	}
+	// 多个 aud 时 azp 必须是自己
+	if azp, ok := claims["azp"].(string); ok && azp != c.config.ClientId {
+		return nil, errors.Errorf("id token azp %q does not match", azp)
+	}
+	return claims, nil
+}
+
+// publicKey 按 kid 选择公钥 没有 kid 时只有一个公钥才能使用
+func (c *OidcClient) publicKey(ctx context.Context, discovery *OidcDiscovery, token *jwt.Token) (crypto.PublicKey, error) {
+	kid, _ := token.Header["kid"].(string)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	jwk, ok := c.findKey(kid)
+	if !ok && time.Since(c.keysAt) >= oidcJwksRefreshInterval {
+		var jwks Jwks
+		if err := c.getJSON(ctx, discovery.JwksUri, "", &jwks); err != nil {
+			return nil, errors.Wrap(err, "oidc jwks")
+		}
+		c.keys, c.keysAt = map[string]Jwk{}, time.Now()
+		for _, key := range jwks.Keys {
+			if key.Use == "" || key.Use == "sig" {
+				c.keys[key.Kid] = key
+			}
+		}
+		jwk, ok = c.findKey(kid)
+	}
+	if !ok {
+		return nil, errors.Errorf("unknown id token kid %q", kid)
+	}
+	if jwk.Alg != "" && jwk.Alg != token.Method.Alg() {
+		return nil, errors.Errorf("id token kid %q requires %s", kid, jwk.Alg)
+	}
+	return jwk.PublicKey()
+}
+
+func (c *OidcClient) findKey(kid string) (Jwk, bool) {
+	if kid == "" && len(c.keys) == 1 {
+		for _, key := range c.keys {
+			return key, true
+		}
+	}
+	key, ok := c.keys[kid]
+	return key, ok && kid != ""
+}
+
+func (c *OidcClient) userinfo(ctx context.Context, discovery *OidcDiscovery, accessToken string) (map[string]any, error) {
+	info := map[string]any{}
+	return info, c.getJSON(ctx, discovery.UserinfoEndpoint, accessToken, &info)
+}
+
+func (c *OidcClient) getJSON(ctx context.Context, endpoint, accessToken string, value any) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	response, err := c.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return errors.Errorf("%s status %d", endpoint, response.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(response.Body, 1<<20)).Decode(value)
+}
+
+// oidcStates ctx 是 Server 处理的请求的 context 时使用这个 Server 的 Redis
+func oidcStates(ctx context.Context) *RedisCache[oidcState] {
+	if deps := serverDepsFromContext(ctx); deps != nil {
+		return NewRedisCache[oidcState](deps.Redis, "sys:oidc:state:")
+	}
+	return GetRedisCache[oidcState]("sys:oidc:state:")
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// 密码策略的字符类型
+const (
+	PasswordClassUpper  = "upper"
+	PasswordClassLower  = "lower"
+	PasswordClassDigit  = "digit"
+	PasswordClassSymbol = "symbol"
+)
+
+// 密码策略的校验标签 结构体字段上使用 validate:"password"
+const passwordTag = "password"
+
+// 密码策略的提示 {0} 是字段名
+var passwordMessages = map[string]map[string]string{
+	"zh": {
+		"password_field":        "密码",
+		"password_min":          "{0}长度不能少于{1}个字符",
+		"password_class":        "{0}必须包含{1}",
+		"password_word":         "{0}不能包含{1}",
+		"password_history":      "{0}不能与最近{1}次使用过的密码相同",
+		"password_sep":          "、",
+		"password_class_upper":  "大写字母",
+		"password_class_lower":  "小写字母",
+		"password_class_digit":  "数字",
+		"password_class_symbol": "特殊字符",
+	},
+	"en": {
+		"password_field":        "Password",
+		"password_min":          "{0} must be at least {1} characters long",
+		"password_class":        "{0} must contain {1}",
+		"password_word":         "{0} must not contain {1}",
+		"password_history":      "{0} must not be the same as the last {1} passwords",
+		"password_sep":          ", ",
+		"password_class_upper":  "uppercase letters",
+		"password_class_lower":  "lowercase letters",
+		"password_class_digit":  "digits",
+		"password_class_symbol": "symbols",
+	},
+}
+
+// registerPasswordValidation 注册 password 校验标签和对应语言的提示
+func registerPasswordValidation(v *validator.Validate, trans ut.Translator, policy func() PasswordConfig) error {
+	err := v.RegisterValidation(passwordTag, func(fl validator.FieldLevel) bool {
+		key, _ := passwordViolation(policy(), fl.Field().String())
+		return key == ""
+	})
+	if err != nil {
+		return err
+	}
+	return v.RegisterTranslation(passwordTag, trans, func(trans ut.Translator) error {
+		for key, text := range passwordMessages[trans.Locale()] {
+			if err := trans.Add(key, text, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, func(trans ut.Translator, fe validator.FieldError) string {
+		value, _ := fe.Value().(string)
+		key, param := passwordViolation(policy(), value)
+		return translatePasswordViolation(trans, fe.Field(), key, param)
+	})
+}
+
+// CheckPassword 按配置的密码策略校验密码 words 是额外不能包含的词 例如用户名
+// 返回的错误信息使用当前语言
+func (v *Validator) CheckPassword(password string, words ...string) error {
+	key, param := passwordViolation(v.passwordPolicy(), password, words...)
+	if key == "" {
+		return nil
+	}
+	field, _ := v.selectTranslator.T("password_field")
+	return errors.New(translatePasswordViolation(v.selectTranslator, field, key, param))
+}
+
+// PasswordReusedError 新密码和最近使用过的密码相同
+func (v *Validator) PasswordReusedError() error {
+	field, _ := v.selectTranslator.T("password_field")
+	count := strconv.Itoa(v.passwordPolicy().HistoryCount)
+	return errors.New(translatePasswordViolation(v.selectTranslator, field, "password_history", count))
+}
+
+// passwordViolation 返回第一条不满足的规则和参数 全部满足时返回空字符串
+func passwordViolation(policy PasswordConfig, password string, words ...string) (key string, param string) {
+	if policy.MinLength > 0 && len([]rune(password)) < policy.MinLength {
+		return "password_min", strconv.Itoa(policy.MinLength)
+	}
+	missing := make([]string, 0, len(policy.CharClasses))
+	for _, class := range policy.CharClasses {
+		// 未知的类型跳过 避免配置写错之后所有密码都无法通过
+		if match := passwordClassFunc(class); match != nil && !strings.ContainsFunc(password, match) {
+			missing = append(missing, class)
+		}
+	}
+	if len(missing) > 0 {
+		return "password_class", strings.Join(missing, ",")
+	}
+	lower := strings.ToLower(password)
+	for _, word := range slices.Concat(policy.ForbiddenWords, words) {
+		if word != "" && strings.Contains(lower, strings.ToLower(word)) {
+			return "password_word", word
+		}
+	}
+	return "", ""
+}
+
+func passwordClassFunc(class string) func(rune) bool {
+	switch class {
+	case PasswordClassUpper:
+		return unicode.IsUpper
+	case PasswordClassLower:
+		return unicode.IsLower
+	case PasswordClassDigit:
+		return unicode.IsDigit
+	case PasswordClassSymbol:
+		return func(r rune) bool {
+			return unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r)
+		}
+	default:
+		return nil
+	}
+}
+
+func translatePasswordViolation(trans ut.Translator, field, key, param string) string {
+	if key == "password_class" {
+		sep, _ := trans.T("password_sep")
+		names := strings.Split(param, ",")
+		for i, class := range names {
+			if name, err := trans.T("password_class_" + class); err == nil {
+				names[i] = name
+			}
+		}
+		param = strings.Join(names, sep)
+	}
+	message, err := trans.T(key, field, param)
+	if err != nil {
+		return key
+	}
+	return message
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"github.com/duke-git/lancet/v2/slice"
 	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 //const (
@@ -32,7 +34,7 @@ func HaveOneOfPermissions(roles ...string) echo.MiddlewareFunc {
 		return func(c echo.Context) error {
 			context := GetContext[any](c)
 			user, err := context.GetLoginUser()
-			if err != nil || !PermissionMange.CheckRoleHaveCodePermission(user.RoleCodes, roles, false) {
+			if err != nil || !GetContextPermissionMange(c).CheckRoleHaveCodePermission(user.RoleCodes, roles, false) {
 				context.Error(NewFrontShowErrMsg(fmt.Sprintf("Dont Have OneOf Permission Check Error: %#v", roles)))
 			} else {
 				return next(c)
@@ -46,7 +48,7 @@ func HaveAllPermissions(roles ...string) echo.MiddlewareFunc {
 		return func(c echo.Context) error {
 			context := GetContext[any](c)
 			user, err := context.GetLoginUser()
-			if err != nil || !PermissionMange.CheckRoleHaveCodePermission(user.RoleCodes, roles, true) {
+			if err != nil || !GetContextPermissionMange(c).CheckRoleHaveCodePermission(user.RoleCodes, roles, true) {
 				context.Error(NewFrontShowErrMsg(fmt.Sprintf("Dont All Permission Check Error: %#v", roles)))
 			} else {
 				return next(c)
@@ -61,7 +63,7 @@ func HavePermission(role string) echo.MiddlewareFunc {
 		return func(c echo.Context) error {
 			context := GetContext[any](c)
 			user, err := context.GetLoginUser()
-			if err != nil || !PermissionMange.CheckRoleHaveCodePermission(user.RoleCodes, []string{role}, false) {
+			if err != nil || !GetContextPermissionMange(c).CheckRoleHaveCodePermission(user.RoleCodes, []string{role}, false) {
 				return NewFrontShowErrMsg(fmt.Sprintf("Dont Have Permission Check Error: %#v", role))
 			}
 			return next(c)
@@ -77,6 +79,7 @@ func IgnorePermission() echo.MiddlewareFunc {
 	}
 }
 
+// PermissionMange 默认 Server 的角色权限 处理请求时使用 GetContextPermissionMange
 var PermissionMange *RolePermission
 
 type RolePermission struct {
@@ -84,25 +87,33 @@ type RolePermission struct {
 	RoleMenuIdRedis      *RedisCache[[]int64]
 	RoleHomeRedis        *RedisCache[string]
 	getRolePermissionsFn PermissionsOptions
+	db                   *gorm.DB
 }
 type RoleMap map[string]struct {
 	Codes    []string
 	MenuIds  []int64
 	HomePath string
 }
-type PermissionsOptions func() (RoleMap, error)
 
-func initRolePermission(getRolePermissionsFn PermissionsOptions) {
-	PermissionMange = &RolePermission{
-		RoleCodeRedis:        GetRedisCache[[]string]("role-code-cache-key"),
-		RoleMenuIdRedis:      GetRedisCache[[]int64]("role-menu-cache-key"),
-		RoleHomeRedis:        GetRedisCache[string]("role-home-path-cache-key"),
+// PermissionsOptions 从 Server 的数据库中读取全部角色的权限
+type PermissionsOptions func(db *gorm.DB) (RoleMap, error)
+
+// newRolePermission 角色权限缓存在 client 中 从 db 加载
+func newRolePermission(client *redis.Client, db *gorm.DB, getRolePermissionsFn PermissionsOptions) *RolePermission {
+	permission := &RolePermission{
+		RoleCodeRedis:        NewRedisCache[[]string](client, "role-code-cache-key"),
+		RoleMenuIdRedis:      NewRedisCache[[]int64](client, "role-menu-cache-key"),
+		RoleHomeRedis:        NewRedisCache[string](client, "role-home-path-cache-key"),
 		getRolePermissionsFn: getRolePermissionsFn,
+		db:                   db,
 	}
-	err := PermissionMange.init()
-	if err != nil {
+	if getRolePermissionsFn == nil {
+		return permission
+	}
+	if err := permission.init(); err != nil {
 		zap.L().Error("init role permission failed", zap.Error(err))
 	}
+	return permission
 }
 
 // init 初始化角色权限信息到缓存中。
@@ -113,7 +124,10 @@ func initRolePermission(getRolePermissionsFn PermissionsOptions) {
 // 这样做是为了在后续查询角色权限时能够快速从缓存中获取，提高性能。
 func (r *RolePermission) init() error {
 	// 获取角色权限数据。
-	rolePermissions, err := r.getRolePermissionsFn()
+	if r.getRolePermissionsFn == nil {
+		return nil
+	}
+	rolePermissions, err := r.getRolePermissionsFn(r.db)
 	r.RoleCodeRedis.XDel()
 	r.RoleCodeRedis.XDel()
 	r.RoleCodeRedis.XDel()
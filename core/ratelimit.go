@@ -0,0 +1,201 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitKey 限流的维度 返回空字符串时该维度不参与限流
+type RateLimitKey func(c echo.Context) string
+
+// RateLimitOption 限流规则
+type RateLimitOption struct {
+	Name   string         // 规则名称 同名规则共享计数 为空时使用请求方法和路由
+	Limit  int64          // 窗口内允许的请求数
+	Window time.Duration  // 窗口长度
+	Keys   []RateLimitKey // 每个维度单独计数 任意一个超过限制就拒绝 为空时按 IP 限流
+}
+
+// RateLimitByIP 按客户端 IP 限流
+func RateLimitByIP() RateLimitKey {
+	return func(c echo.Context) string {
+		return "ip:" + c.RealIP()
+	}
+}
+
+// RateLimitByUid 按登录用户限流 未登录的请求不参与
+func RateLimitByUid() RateLimitKey {
+	return func(c echo.Context) string {
+		uid, err := GetContext[any](c).GetLoginUserUid()
+		if err != nil {
+			return ""
+		}
+		return "uid:" + strconv.FormatInt(uid, 10)
+	}
+}
+
+// RateLimitByRoute 整个路由共用一个计数 用于保护下游服务
+func RateLimitByRoute() RateLimitKey {
+	return func(c echo.Context) string {
+		return "route"
+	}
+}
+
+// RateLimitByBody 按 JSON 请求体中的字段限流 例如登录的用户名 请求体中没有时读取同名的查询参数
+func RateLimitByBody(field string) RateLimitKey {
+	return func(c echo.Context) string {
+		value := ""
+		body, err := io.ReadAll(c.Request().Body)
+		if err == nil {
+			c.Request().Body = io.NopCloser(bytes.NewBuffer(body))
+			params := map[string]any{}
+			if json.Unmarshal(body, &params) == nil && params[field] != nil {
+				value = fmt.Sprint(params[field])
+			}
+		}
+		if value == "" {
+			value = c.QueryParam(field)
+		}
+		if value == "" {
+			return ""
+		}
+		return field + ":" + value
+	}
+}
+
+// RateLimitJoin 多个维度组合成一个维度 例如同一个 IP 下的同一个用户名
+func RateLimitJoin(keys ...RateLimitKey) RateLimitKey {
+	return func(c echo.Context) string {
+		parts := make([]string, 0, len(keys))
+		for _, key := range keys {
+			part := key(c)
+			if part == "" {
+				return ""
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, "|")
+	}
+}
+
+// RateLimit 限流中间件 使用 Redis 滑动窗口计数 多个实例共享限额
+// 响应头带上 RateLimit-Limit RateLimit-Remaining RateLimit-Reset 超过限制时返回 429 和 Retry-After
+// Redis 不可用时放行请求
+func RateLimit(option RateLimitOption) echo.MiddlewareFunc {
+	if option.Limit <= 0 || option.Window <= 0 {
+		panic(fmt.Sprintf("rate limit %q requires positive Limit and Window", option.Name))
+	}
+	if len(option.Keys) == 0 {
+		option.Keys = []RateLimitKey{RateLimitByIP()}
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Method == http.MethodOptions {
+				return next(c)
+			}
+			name := option.Name
+			if name == "" {
+				name = c.Request().Method + ":" + c.Path()
+			}
+			limiter := rateLimiter{cache: GetContextRedisCache[int64](c, "sys:ratelimit:"+name+":"), option: option}
+			limited, remaining, retryAfter, reset := false, option.Limit, time.Duration(0), time.Duration(0)
+			for _, key := range option.Keys {
+				value := key(c)
+				if value == "" {
+					continue
+				}
+				result, err := limiter.take(value)
+				if err != nil {
+					zap.L().Warn("rate limit error", zap.String("name", name), zap.Error(err))
+					return next(c)
+				}
+				remaining = min(remaining, result.remaining)
+				reset = max(reset, result.reset)
+				if !result.allowed {
+					limited = true
+					retryAfter = max(retryAfter, result.retryAfter)
+				}
+			}
+			header := c.Response().Header()
+			header.Set("RateLimit-Limit", strconv.FormatInt(option.Limit, 10))
+			header.Set("RateLimit-Remaining", strconv.FormatInt(max(remaining, 0), 10))
+			header.Set("RateLimit-Reset", strconv.FormatInt(ceilSeconds(reset), 10))
+			header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", option.Limit, ceilSeconds(option.Window)))
+			if limited {
+				header.Set(echo.HeaderRetryAfter, strconv.FormatInt(max(ceilSeconds(retryAfter), 1), 10))
+				context := GetContext[any](c)
+				return c.JSON(http.StatusTooManyRequests, context.CreateError(RATE_LIMIT_ERROR, MapErrMsg(RATE_LIMIT_ERROR)))
+			}
+			return next(c)
+		}
+	}
+}
+
+type rateLimiter struct {
+	cache  *RedisCache[int64]
+	option RateLimitOption
+}
+
+type rateLimitResult struct {
+	allowed    bool
+	remaining  int64
+	reset      time.Duration // 当前窗口结束的剩余时间
+	retryAfter time.Duration // 被拒绝时多久之后可以重试
+}
+
+// take 滑动窗口计数 用上一个窗口的计数按剩余比例加上当前窗口的计数估算最近一个窗口内的请求数
+// 先计数再判断 超过限制时撤回这次计数 并发请求不会超出限额
+func (l rateLimiter) take(value string) (rateLimitResult, error) {
+	window := l.option.Window.Milliseconds()
+	now := time.Now().UnixMilli()
+	current := now / window
+	elapsed := float64(now%window) / float64(window)
+	currentKey := l.cache.key + value + ":" + strconv.FormatInt(current, 10)
+	previousKey := l.cache.key + value + ":" + strconv.FormatInt(current-1, 10)
+
+	count, err := l.cache.Incr(ctx, currentKey).Result()
+	if err != nil {
+		return rateLimitResult{}, err
+	}
+	if count == 1 {
+		// 保留到下一个窗口结束 下一个窗口还要用它估算
+		l.cache.PExpire(ctx, currentKey, 2*l.option.Window)
+	}
+	previous, err := l.cache.Get(ctx, previousKey).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return rateLimitResult{}, err
+	}
+	weight := float64(previous) * (1 - elapsed)
+	result := rateLimitResult{
+		reset: time.Duration(float64(window)*(1-elapsed)) * time.Millisecond,
+	}
+	if weight+float64(count) <= float64(l.option.Limit) {
+		result.allowed = true
+		result.remaining = l.option.Limit - int64(math.Ceil(weight)) - count
+		return result, nil
+	}
+	l.cache.Decr(ctx, currentKey)
+	count--
+	result.retryAfter = result.reset
+	if count < l.option.Limit && previous > 0 {
+		// 上一个窗口的权重降到剩余名额以内就可以重试
+		wait := 1 - float64(l.option.Limit-count-1)/float64(previous) - elapsed
+		result.retryAfter = time.Duration(max(wait, 0)*float64(window)) * time.Millisecond
+	}
+	return result, nil
+}
+
+func ceilSeconds(duration time.Duration) int64 {
+	return int64(math.Ceil(duration.Seconds()))
+}
@@ -0,0 +1,219 @@
+package core
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+	"sort"
+	"sync"
+)
+
+// FindDeletedByPage 分页查询已经软删除的数据
+func (r *Gorm[M, V]) FindDeletedByPage(param PageParam, conditions ...func(*gorm.DB) *gorm.DB) (error, PageResultList[M]) {
+	column, err := r.deleteTimeColumn()
+	if err != nil {
+		return err, PageResultList[M]{PageResult: PageResult{PageParam: param}}
+	}
+	deleted := *r
+	deleted.DB = r.GetModelDb().Unscoped()
+	conditions = append([]func(*gorm.DB) *gorm.DB{func(db *gorm.DB) *gorm.DB {
+		return db.Where(fmt.Sprintf("%s IS NOT NULL", column))
+	}}, conditions...)
+	return deleted.FindListByPage(param, conditions...)
+}
+
+func (r *Gorm[M, V]) FindDeletedVoByPage(param PageParam, conditions ...func(*gorm.DB) *gorm.DB) (error, PageResultList[V]) {
+	var result PageResultList[V]
+	err, p := r.FindDeletedByPage(param, conditions...)
+	if err != nil {
+		return err, result
+	}
+	result.PageResult = p.PageResult
+	result.Items = CopyListFrom[V](p.Items)
+	return nil, result
+}
+
+// RestoreByPrimaryKeys 恢复软删除的数据
+func (r *Gorm[M, V]) RestoreByPrimaryKeys(ids []int64) (error, int64) {
+	column, err := r.deleteTimeColumn()
+	if err != nil {
+		return err, 0
+	}
+	tx := r.GetModelDb().Unscoped().
+		Where(fmt.Sprintf("%s IN ?", r.config.PrimaryKeyField), ids).
+		Where(fmt.Sprintf("%s IS NOT NULL", column)).
+		Update(column, nil)
+	return tx.Error, tx.RowsAffected
+}
+
+// PurgeByPrimaryKeys 彻底删除 只会删除已经软删除的数据
+func (r *Gorm[M, V]) PurgeByPrimaryKeys(ids []int64) (error, int64) {
+	column, err := r.deleteTimeColumn()
+	if err != nil {
+		return err, 0
+	}
+	result := r.createModelInstance()
+	tx := r.GetModelDb().Unscoped().
+		Where(fmt.Sprintf("%s IN ?", r.config.PrimaryKeyField), ids).
+		Where(fmt.Sprintf("%s IS NOT NULL", column)).
+		Delete(&result)
+	return tx.Error, tx.RowsAffected
+}
+
+// findDeletedPrimaryKeys ids 中已经软删除 并且符合数据权限的数据
+func (r *Gorm[M, V]) findDeletedPrimaryKeys(ids []int64) (error, []int64) {
+	column, err := r.deleteTimeColumn()
+	if err != nil {
+		return err, nil
+	}
+	var visible []int64
+	err = r.GetModelDb().Unscoped().
+		Where(fmt.Sprintf("%s IN ?", r.config.PrimaryKeyField), ids).
+		Where(fmt.Sprintf("%s IS NOT NULL", column)).
+		Pluck(r.config.PrimaryKeyField, &visible).Error
+	return err, visible
+}
+
+// deleteTimeColumn 模型的软删除字段 没有软删除字段的模型不支持回收站
+func (r *Gorm[M, V]) deleteTimeColumn() (string, error) {
+	modelSchema, err := statementSchema(r.GetModelDb())
+	if err != nil {
+		return "", err
+	}
+	field := modelSchema.LookUpField(r.config.DeleteTimeField)
+	if field == nil || field.DBName == "" {
+		return "", NewFrontShowErrMsg("该数据不支持回收站")
+	}
+	return field.DBName, nil
+}
+
+// RecycleBinItem 回收站中的一类数据
+type RecycleBinItem struct {
+	Name  string `json:"name"`
+	Title string `json:"title"`
+}
+
+// RecycleBinOption 回收站选项
+type RecycleBinOption struct {
+	// BeforePurge 彻底删除之前在同一个事务中执行 ids 是这次会删除的数据
+	// 用来级联删除关联的数据 还有数据引用时返回错误 不会删除任何数据
+	BeforePurge func(tx TxScope, ids []int64) error
+	AfterChange func(c echo.Context) // 恢复或者彻底删除之后执行 例如刷新缓存
+}
+
+type recycleBin struct {
+	RecycleBinItem
+	list    func(c echo.Context, param PageParam) (error, any)
+	restore func(c echo.Context, ids []int64) (error, int64)
+	purge   func(c echo.Context, ids []int64) (error, int64)
+	after   func(c echo.Context)
+}
+
+var recycleBins = struct {
+	sync.RWMutex
+	items map[string]*recycleBin
+}{items: map[string]*recycleBin{}}
+
+// RegisterRecycleBin 注册回收站 模型需要有软删除字段 同名的会被覆盖
+// 列表经过全局 Gorm 钩子 和业务数据一样按数据权限过滤 恢复和彻底删除只处理列表中能看到的数据
+func RegisterRecycleBin[M any, V any](name string, title string, service PreGorm[M, V], option ...RecycleBinOption) {
+	var opt RecycleBinOption
+	if len(option) > 0 {
+		opt = option[len(option)-1]
+	}
+	bin := &recycleBin{
+		RecycleBinItem: RecycleBinItem{Name: name, Title: title},
+		list: func(c echo.Context, param PageParam) (error, any) {
+			return service.WithContext(c).FindDeletedVoByPage(param, func(db *gorm.DB) *gorm.DB {
+				return db.Order(fmt.Sprintf("%s DESC", service.config.DeleteTimeField))
+			})
+		},
+		restore: func(c echo.Context, ids []int64) (error, int64) {
+			err, visible := service.WithContext(c).findDeletedPrimaryKeys(ids)
+			if err != nil || len(visible) == 0 {
+				return err, 0
+			}
+			return service.WithContext(c).RestoreByPrimaryKeys(visible)
+		},
+		purge: func(c echo.Context, ids []int64) (error, int64) {
+			var rows int64
+			err := Transaction(c, func(tx TxScope) error {
+				err, visible := service.WithContext(tx).findDeletedPrimaryKeys(ids)
+				if err != nil || len(visible) == 0 {
+					return err
+				}
+				if opt.BeforePurge != nil {
+					if err = opt.BeforePurge(tx, visible); err != nil {
+						return err
+					}
+				}
+				err, rows = service.WithContext(tx).PurgeByPrimaryKeys(visible)
+				return err
+			})
+			return err, rows
+		},
+		after: opt.AfterChange,
+	}
+	recycleBins.Lock()
+	recycleBins.items[name] = bin
+	recycleBins.Unlock()
+}
+
+// RecycleBins 已经注册的回收站
+func RecycleBins() []RecycleBinItem {
+	recycleBins.RLock()
+	defer recycleBins.RUnlock()
+	items := make([]RecycleBinItem, 0, len(recycleBins.items))
+	for _, bin := range recycleBins.items {
+		items = append(items, bin.RecycleBinItem)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+	return items
+}
+
+func getRecycleBin(name string) (*recycleBin, error) {
+	recycleBins.RLock()
+	defer recycleBins.RUnlock()
+	bin, ok := recycleBins.items[name]
+	if !ok {
+		return nil, NewFrontShowErrMsg("回收站类型不存在")
+	}
+	return bin, nil
+}
+
+// RecycleBinList 分页查询回收站中的数据
+func RecycleBinList(c echo.Context, name string, param PageParam) (error, any) {
+	bin, err := getRecycleBin(name)
+	if err != nil {
+		return err, nil
+	}
+	return bin.list(c, param)
+}
+
+// RecycleBinRestore 恢复回收站中的数据
+func RecycleBinRestore(c echo.Context, name string, ids []int64) (error, int64) {
+	bin, err := getRecycleBin(name)
+	if err != nil {
+		return err, 0
+	}
+	err, rows := bin.restore(c, ids)
+	if err == nil && rows > 0 && bin.after != nil {
+		bin.after(c)
+	}
+	return err, rows
+}
+
+// RecycleBinPurge 彻底删除回收站中的数据
+func RecycleBinPurge(c echo.Context, name string, ids []int64) (error, int64) {
+	bin, err := getRecycleBin(name)
+	if err != nil {
+		return err, 0
+	}
+	err, rows := bin.purge(c, ids)
+	if err == nil && rows > 0 && bin.after != nil {
+		bin.after(c)
+	}
+	return err, rows
+}
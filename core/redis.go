@@ -13,28 +13,62 @@ var innerRedis *redis.Client
 var ctx = context.Background()
 
 func initRedis() {
-	innerRedis = redis.NewClient(&redis.Options{
-		Addr:     config.Redis.Addr,
-		Password: config.Redis.Password,
-		DB:       config.Redis.DB,
-	})
-	_, err := innerRedis.Ping(context.Background()).Result()
+	client, err := newRedisClient(config.Redis)
 	if err != nil {
 		zap.L().Error("redis connect error", zap.Error(err))
 		panic(err)
 	}
+	innerRedis = client
 }
 
-func GetRedisCache[T any](key string) *RedisCache[T] {
-	if innerRedis == nil {
-		initRedis()
+// newRedisClient 根据配置创建 Redis 客户端并检测连接
+func newRedisClient(redisConfig RedisConfig) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     redisConfig.Addr,
+		Password: redisConfig.Password,
+		DB:       redisConfig.DB,
+	})
+	if _, err := client.Ping(context.Background()).Result(); err != nil {
+		_ = client.Close()
+		return nil, err
 	}
+	return client, nil
+}
+
+// closeRedis 关闭 Redis 连接
+func closeRedis(client *redis.Client) error {
+	if client == nil {
+		return nil
+	}
+	if innerRedis == client {
+		innerRedis = nil
+		tokenManager = nil
+		captchaManager = nil
+		verifyCodeManager = nil
+	}
+	return client.Close()
+}
+
+func GetRedisCache[T any](key string) *RedisCache[T] {
+	return NewRedisCache[T](getRedisClient(), key)
+}
+
+// NewRedisCache 使用指定的 Redis 客户端 例如路由构造函数中注入的 *redis.Client
+func NewRedisCache[T any](client *redis.Client, key string) *RedisCache[T] {
 	return &RedisCache[T]{
-		Client: innerRedis,
+		Client: client,
 		key:    key,
 	}
 }
 
+// getRedisClient 默认的 Redis 客户端 没有 Build 过 Server 时按全局配置连接
+func getRedisClient() *redis.Client {
+	if innerRedis == nil {
+		initRedis()
+	}
+	return innerRedis
+}
+
 // RedisCache
 // 结构体需要将字段导出才可以正常使用
 type RedisCache[T any] struct {
@@ -79,6 +113,16 @@ func (client *RedisCache[T]) XSetEX(value T, ex time.Duration) bool {
 	return result == "OK"
 }
 
+// XSetNXEX 值不存在时设置并指定过期时间 可以用作简单的分布式锁
+func (client *RedisCache[T]) XSetNXEX(value T, ex time.Duration) bool {
+	result, err := client.SetNX(ctx, client.key, client.Marshal(value), ex).Result()
+	if err != nil {
+		fmt.Println(err)
+		return false
+	}
+	return result
+}
+
 // XSetCodeEX 设置 指定Code值的值并指定过期时间
 func (client *RedisCache[T]) XSetCodeEX(appendCode string, value T, ex time.Duration) bool {
 	result, err := client.Set(ctx, client.key+appendCode, client.Marshal(value), ex).Result()
@@ -0,0 +1,178 @@
+package core
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+	"time"
+)
+
+// 刷新令牌默认有效期 7 天
+const defaultRefreshExpire int64 = 7 * 24 * 60 * 60
+
+// TokenPair 访问令牌和刷新令牌 访问令牌过期后使用刷新令牌换取新的令牌
+type TokenPair struct {
+	AccessToken     string `json:"accessToken"`
+	ExpireAt        int64  `json:"expireAt"`
+	RefreshToken    string `json:"refreshToken"`
+	RefreshExpireAt int64  `json:"refreshExpireAt"`
+}
+
+// refreshTokenInfo 刷新令牌的内容 Redis 中只保存令牌的摘要
+// 同一次登录轮换出来的刷新令牌属于同一个家族 家族记录在会话上 会话下线后整个家族失效
+type refreshTokenInfo struct {
+	UID       int64  `json:"uid"`
+	Platform  string `json:"platform"`
+	Family    string `json:"family"`
+	SessionId string `json:"sid"`
+}
+
+// RefreshClaimsLoader 刷新时重新加载用户信息 账号被禁用等情况返回错误
+type RefreshClaimsLoader func(uid int64, platform string) (ClaimsAdditions, error)
+
+func (j TokenManager) GetPlatformRefreshExpiration(platform string) int64 {
+	config := j.config.Jwt
+	expire := config.RefreshExpire
+	for _, item := range config.SpecifiedConfig {
+		if platform != "" && item.Platform == platform && item.RefreshExpire > 0 {
+			expire = item.RefreshExpire
+		}
+	}
+	if expire <= 0 {
+		return defaultRefreshExpire
+	}
+	return expire
+}
+
+// GenTokenPair 登录时生成访问令牌和刷新令牌 并记录在线会话
+// 每次登录都会开启新的会话和刷新令牌家族 同时按平台的登录策略踢出多余的会话
+func (j TokenManager) GenTokenPair(platform string, user ClaimsAdditions, meta ...SessionMeta) (TokenPair, error) {
+	var (
+		pair TokenPair
+		err  error
+	)
+	if user.SessionId, err = randomToken(); err != nil {
+		return pair, err
+	}
+	family, err := randomToken()
+	if err != nil {
+		return pair, err
+	}
+	accessToken, claims, err := j.signJwt(platform, user)
+	if err != nil {
+		return pair, err
+	}
+	pair.AccessToken, pair.ExpireAt = accessToken, claims.ExpiresAt.Unix()
+	if err = j.issueRefreshToken(&pair, user.UID, platform, family, user.SessionId); err != nil {
+		return pair, err
+	}
+	j.enforceLoginPolicy(user.UID, platform)
+	j.startSession(claims, AdditionFirst(meta, SessionMeta{}), pair, family)
+	return pair, nil
+}
+
+// RefreshToken 使用刷新令牌换取新的令牌 旧的刷新令牌立即失效
+// 已经使用过的刷新令牌再次使用时视为被盗用 整个家族的令牌都会被撤销
+func (j TokenManager) RefreshToken(refreshToken string, load RefreshClaimsLoader) (TokenPair, error) {
+	var pair TokenPair
+	digest := tokenDigest(refreshToken)
+	have, info := j.refreshTokens.XCodeGet(digest)
+	if refreshToken == "" || !have {
+		return pair, NewErrCode(TOKEN_REFRESH_INVALID_ERROR)
+	}
+	// 标记为已使用 并发刷新时只有一个请求能成功
+	ttl := j.refreshTokens.TTL(ctx, j.refreshTokens.key+digest).Val()
+	if ttl <= 0 {
+		ttl = time.Duration(j.GetPlatformRefreshExpiration(info.Platform)) * time.Second
+	}
+	first, err := j.refreshTokens.SetNX(ctx, j.refreshTokens.key+"used:"+digest, 1, ttl).Result()
+	if err != nil {
+		return pair, err
+	}
+	if !first {
+		zap.L().Warn(fmt.Sprintf("刷新令牌重复使用，撤销登录 uid:%d platform:%s", info.UID, info.Platform))
+		if record := j.sessions.XHGet(info.SessionId); record.SessionId != "" && record.Family == info.Family {
+			j.RemoveSession(record.SessionId)
+		}
+		return pair, NewErrCode(TOKEN_REFRESH_REUSED_ERROR)
+	}
+	if j.IsKicked(info.SessionId) {
+		return pair, NewErrCode(TOKEN_KICKED_ERROR)
+	}
+	record := j.sessions.XHGet(info.SessionId)
+	if record.SessionId == "" || record.Family != info.Family || record.RefreshExpireAt <= GetNowTimeUnix() {
+		return pair, NewErrCode(TOKEN_REFRESH_INVALID_ERROR)
+	}
+	user, err := load(info.UID, info.Platform)
+	if err != nil {
+		return pair, err
+	}
+	user.UID, user.Platform, user.SessionId = info.UID, info.Platform, info.SessionId
+	accessToken, claims, err := j.signJwt(info.Platform, user)
+	if err != nil {
+		return pair, err
+	}
+	pair.AccessToken, pair.ExpireAt = accessToken, claims.ExpiresAt.Unix()
+	if err = j.issueRefreshToken(&pair, info.UID, info.Platform, info.Family, info.SessionId); err != nil {
+		return pair, err
+	}
+	j.renewSession(claims, pair)
+	return pair, nil
+}
+
+func (j TokenManager) issueRefreshToken(pair *TokenPair, uid int64, platform, family, sessionId string) error {
+	token, err := randomToken()
+	if err != nil {
+		return err
+	}
+	expire := j.GetPlatformRefreshExpiration(platform)
+	expireAt := GetNowTimeUnix() + expire
+	info := refreshTokenInfo{UID: uid, Platform: platform, Family: family, SessionId: sessionId}
+	if !j.refreshTokens.XSetCodeEX(tokenDigest(token), info, time.Duration(expire)*time.Second) {
+		return NewErrCode(TOKEN_GENERATE_ERROR)
+	}
+	pair.RefreshToken = token
+	pair.RefreshExpireAt = expireAt
+	return nil
+}
+
+// signJwt 签发新的访问令牌 不复用当前的令牌 每个令牌都有唯一的 jti 用于撤销
+func (j TokenManager) signJwt(platform string, user ClaimsAdditions) (string, *Claims, error) {
+	jti, err := randomToken()
+	if err != nil {
+		return "", nil, err
+	}
+	expirationTime := GetNowLocalTime().Add(time.Second * time.Duration(j.GetPlatformExpiration(platform)))
+	claims := &Claims{
+		ClaimsAdditions: user,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(j.issuedAt(user.UID)),
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+		},
+	}
+	signedString, err := j.keys.Sign(claims)
+	if err != nil {
+		zap.L().Error(fmt.Sprintf("生成Token出错！%#v", err))
+		return "", nil, err
+	}
+	j.SetUserJwt(user.UID, platform, signedString, expirationTime.Unix())
+	return signedString, claims, nil
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func tokenDigest(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,246 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+	"math/rand"
+	"strings"
+	"sync"
+)
+
+// 从库负载均衡策略
+const (
+	ReplicaPolicyRandom     = "random"
+	ReplicaPolicyRoundRobin = "round-robin"
+	ReplicaPolicyLeastConn  = "least-connections"
+)
+
+// ReplicaConfig 从库配置 数据库名称和驱动与主库一致
+// 账号密码与连接池参数不填时使用主库的配置
+type ReplicaConfig struct {
+	Host            string
+	Port            int64
+	User            string
+	Pass            string
+	Weight          int // 权重 默认 1
+	ConnMaxLifetime int
+	MaxIdleTime     int
+	SetMaxIdleConn  int
+	SetMaxOpenConn  int
+}
+
+// 合并主库配置 得到从库的完整配置
+func (r ReplicaConfig) merge(primary GormConfig) GormConfig {
+	replica := primary
+	replica.Host = r.Host
+	replica.Port = BooleanTo(r.Port > 0, r.Port, primary.Port)
+	replica.User = BooleanTo(r.User != "", r.User, primary.User)
+	replica.Pass = BooleanTo(r.Pass != "", r.Pass, primary.Pass)
+	replica.ConnMaxLifetime = BooleanTo(r.ConnMaxLifetime > 0, r.ConnMaxLifetime, primary.ConnMaxLifetime)
+	replica.MaxIdleTime = BooleanTo(r.MaxIdleTime > 0, r.MaxIdleTime, primary.MaxIdleTime)
+	replica.SetMaxIdleConn = BooleanTo(r.SetMaxIdleConn > 0, r.SetMaxIdleConn, primary.SetMaxIdleConn)
+	replica.SetMaxOpenConn = BooleanTo(r.SetMaxOpenConn > 0, r.SetMaxOpenConn, primary.SetMaxOpenConn)
+	return replica
+}
+
+// replicaPools 记录每个数据库实例的从库连接池 关闭时一起关闭
+var replicaPools = struct {
+	sync.Mutex
+	pools map[*gorm.DB][]*sql.DB
+}{pools: map[*gorm.DB][]*sql.DB{}}
+
+// registerReplicas 注册读写分离 没有配置从库时所有读写都走主库
+func registerReplicas(gormDb *gorm.DB, options GormConfig) error {
+	if len(options.Replicas) == 0 {
+		return nil
+	}
+	policy := &weightedPolicy{
+		mode:    strings.ToLower(options.ReplicaPolicy),
+		weights: map[gorm.ConnPool]int{},
+		current: map[gorm.ConnPool]int{},
+	}
+	var dialectors []gorm.Dialector
+	var pools []*sql.DB
+	for _, replica := range options.Replicas {
+		dialector, conn, err := openConnDialector(replica.merge(options))
+		if err != nil {
+			closeSQLDBs(pools)
+			return err
+		}
+		dialectors = append(dialectors, dialector)
+		pools = append(pools, conn)
+		policy.weights[conn] = BooleanTo(replica.Weight > 0, replica.Weight, 1)
+	}
+	err := gormDb.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: dialectors, //  读 操作库，查询类
+		Policy:   policy,     // sources/replicas 负载均衡策略适用于
+	}))
+	if err != nil {
+		closeSQLDBs(pools)
+		return err
+	}
+	if err = registerPrimarySticky(gormDb); err != nil {
+		closeSQLDBs(pools)
+		return err
+	}
+	replicaPools.Lock()
+	replicaPools.pools[gormDb] = pools
+	replicaPools.Unlock()
+	return nil
+}
+
+// closeReplicas 关闭数据库实例对应的从库连接池
+func closeReplicas(gormDb *gorm.DB) {
+	replicaPools.Lock()
+	pools := replicaPools.pools[gormDb]
+	delete(replicaPools.pools, gormDb)
+	replicaPools.Unlock()
+	closeSQLDBs(pools)
+}
+
+func closeSQLDBs(pools []*sql.DB) {
+	for _, pool := range pools {
+		_ = pool.Close()
+	}
+}
+
+// weightedPolicy 带权重的从库选择策略
+type weightedPolicy struct {
+	mode    string
+	weights map[gorm.ConnPool]int
+	mu      sync.Mutex
+	current map[gorm.ConnPool]int
+}
+
+func (p *weightedPolicy) weight(pool gorm.ConnPool) int {
+	if w, ok := p.weights[pool]; ok {
+		return w
+	}
+	return 1
+}
+
+func (p *weightedPolicy) Resolve(pools []gorm.ConnPool) gorm.ConnPool {
+	switch p.mode {
+	case ReplicaPolicyRoundRobin:
+		return p.roundRobin(pools)
+	case ReplicaPolicyLeastConn:
+		return p.leastConnections(pools)
+	default:
+		return p.random(pools)
+	}
+}
+
+// random 按权重随机
+func (p *weightedPolicy) random(pools []gorm.ConnPool) gorm.ConnPool {
+	total := 0
+	for _, pool := range pools {
+		total += p.weight(pool)
+	}
+	n := rand.Intn(total)
+	for _, pool := range pools {
+		if n -= p.weight(pool); n < 0 {
+			return pool
+		}
+	}
+	return pools[len(pools)-1]
+}
+
+// roundRobin 平滑加权轮询 与 nginx 的实现一致
+func (p *weightedPolicy) roundRobin(pools []gorm.ConnPool) gorm.ConnPool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	total := 0
+	var best gorm.ConnPool
+	for _, pool := range pools {
+		w := p.weight(pool)
+		total += w
+		p.current[pool] += w
+		if best == nil || p.current[pool] > p.current[best] {
+			best = pool
+		}
+	}
+	p.current[best] -= total
+	return best
+}
+
+// leastConnections 选择 使用中的连接数/权重 最小的从库
+func (p *weightedPolicy) leastConnections(pools []gorm.ConnPool) gorm.ConnPool {
+	best, bestInUse := pools[0], inUseConnections(pools[0])
+	for _, pool := range pools[1:] {
+		inUse := inUseConnections(pool)
+		if inUse*p.weight(best) < bestInUse*p.weight(pool) {
+			best, bestInUse = pool, inUse
+		}
+	}
+	return best
+}
+
+func inUseConnections(pool gorm.ConnPool) int {
+	if sqlDB, ok := pool.(*sql.DB); ok {
+		return sqlDB.Stats().InUse
+	}
+	return 0
+}
+
+// registerPrimarySticky 同一个请求里写入之后的读取强制走主库 避免主从延迟读不到刚写入的数据
+func registerPrimarySticky(gormDb *gorm.DB) error {
+	usePrimary := func(db *gorm.DB) {
+		if isUsePrimary(db.Statement.Context) {
+			dbresolver.Write.ModifyStatement(db.Statement)
+		}
+	}
+	markPrimary := func(db *gorm.DB) {
+		if db.Error != nil || db.RowsAffected == 0 {
+			return
+		}
+		if c, ok := db.Statement.Context.(*XContext[any]); ok && c != nil {
+			c.Set(GormUsePrimaryKey, true)
+		}
+	}
+	callback := gormDb.Callback()
+	for _, err := range []error{
+		callback.Query().After("gorm:db_resolver").Before("gorm:query").Register("core:use_primary", usePrimary),
+		callback.Row().After("gorm:db_resolver").Before("gorm:row").Register("core:use_primary", usePrimary),
+		callback.Raw().After("gorm:db_resolver").Before("gorm:raw").Register("core:use_primary", usePrimary),
+		callback.Create().After("gorm:create").Register("core:mark_primary", markPrimary),
+		callback.Update().After("gorm:update").Register("core:mark_primary", markPrimary),
+		callback.Delete().After("gorm:delete").Register("core:mark_primary", markPrimary),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isUsePrimary 当前上下文是否需要强制走主库
+func isUsePrimary(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	if c, ok := ctx.(*XContext[any]); ok {
+		if c == nil {
+			return false
+		}
+		use, _ := c.Get(GormUsePrimaryKey).(bool)
+		return use
+	}
+	use, _ := ctx.Value(GormUsePrimaryKey).(bool)
+	return use
+}
+
+// UsePrimary 路由中间件 该请求内所有的查询都走主库
+func UsePrimary(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		c.Set(GormUsePrimaryKey, true)
+		return next(c)
+	}
+}
+
+// NewUsePrimaryContext 没有 echo.Context 时使用 查询强制走主库
+func NewUsePrimaryContext(parent context.Context) context.Context {
+	return context.WithValue(parent, GormUsePrimaryKey, true)
+}
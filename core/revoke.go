@@ -0,0 +1,74 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IsRevoked token 是否已经被撤销 所有平台都会检查
+// jti 在黑名单中 或者签发时间不晚于账号的撤销时间都视为已撤销
+func (j TokenManager) IsRevoked(claims Claims) bool {
+	if claims.ID != "" && j.deniedTokens.XCodeExists(claims.ID) {
+		return true
+	}
+	revokedAt := j.revokedUsers.XHGet(strconv.FormatInt(claims.UID, 10))
+	if revokedAt == 0 {
+		return false
+	}
+	return claims.IssuedAt == nil || claims.IssuedAt.Unix() <= revokedAt
+}
+
+// issuedAt 签发时间 踢出之后立即重新登录时签发时间排在撤销时间之后
+func (j TokenManager) issuedAt(uid int64) time.Time {
+	now := GetNowLocalTime()
+	if revokedAt := j.revokedUsers.XHGet(strconv.FormatInt(uid, 10)); now.Unix() <= revokedAt {
+		return time.Unix(revokedAt+1, 0).In(now.Location())
+	}
+	return now
+}
+
+// RevokeClaims 把 token 加入黑名单 保留到 token 过期
+func (j TokenManager) RevokeClaims(claims Claims) bool {
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return false
+	}
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if ttl <= 0 {
+		return false
+	}
+	return j.deniedTokens.XSetCodeEX(claims.ID, claims.ExpiresAt.Unix(), ttl)
+}
+
+// RevokeToken 撤销指定的 token 无效的 token 忽略
+func (j TokenManager) RevokeToken(token string) bool {
+	claims, ok := j.tokenClaims(token)
+	if !ok {
+		return false
+	}
+	return j.RevokeClaims(claims)
+}
+
+// RemoveToken 踢出账号在指定平台的登录 当前的访问令牌和刷新令牌都会失效
+func (j TokenManager) RemoveToken(uid int64, platform string) bool {
+	field := fmt.Sprintf("%d:%s", uid, platform)
+	if info := j.XHGet(field); info.Token != "" {
+		j.RevokeToken(info.Token)
+	}
+	j.removeUserSessions(uid, platform)
+	return j.XHDel(field)
+}
+
+// RemoveTokenByUid 踢出账号在所有平台的登录 在此之前签发的 token 全部失效
+func (j TokenManager) RemoveTokenByUid(uid int64) bool {
+	j.revokedUsers.XHSet(strconv.FormatInt(uid, 10), GetNowTimeUnix())
+	prefix := fmt.Sprintf("%d:", uid)
+	for _, field := range j.XHKeys() {
+		if strings.HasPrefix(field, prefix) {
+			j.RemoveToken(uid, strings.TrimPrefix(field, prefix))
+		}
+	}
+	j.removeUserSessions(uid, "")
+	return true
+}
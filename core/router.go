@@ -22,16 +22,18 @@ func NewDigContainer() *DigContainer {
 func GetDig() *DigContainer {
 	if container == nil {
 		container = NewDigContainer()
+		// RegisterGroup 注册的路由组使用全局配置
+		container.Provide(func() *Config { return GetConfig() })
 	}
 	return container
 }
 
 func (r *DigContainer) Provide(constructor interface{}, opts ...dig.ProvideOption) {
-	_ = GetDig().Container.Provide(constructor, opts...)
+	_ = r.Container.Provide(constructor, opts...)
 
 }
 func (r *DigContainer) DI(function interface{}, opts ...dig.InvokeOption) error {
-	return GetDig().Container.Invoke(function, opts...)
+	return r.Container.Invoke(function, opts...)
 }
 
 type RouterGroup struct {
@@ -39,6 +41,7 @@ type RouterGroup struct {
 	initHandle  any
 	regHandle   func(g *echo.Group, group *RouterGroup) error
 	middleWares []echo.MiddlewareFunc
+	container   *DigContainer // 注册时使用的依赖容器 为空时使用全局容器
 }
 
 func NewRouterGroup(relativePath string, initHandle interface{}, regHandle func(rg *echo.Group, group *RouterGroup) error,
@@ -53,17 +56,28 @@ func NewRouterGroup(relativePath string, initHandle interface{}, regHandle func(
 
 // RegisterGroup 将路由组注册到gin
 func RegisterGroup(rg *echo.Group, group *RouterGroup) {
+	if err := registerGroup(rg, group, GetDig()); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// registerGroup 使用指定的依赖容器注册路由组
+func registerGroup(rg *echo.Group, group *RouterGroup, container *DigContainer) error {
 	r := rg.Group(group.path)
 	if len(group.middleWares) > 0 {
 		r.Use(group.middleWares...)
 	}
-	GetDig().Provide(group.initHandle)
-	if err := group.regHandle(r, group); err != nil {
-		log.Fatalln(err)
-	}
+	// 复制一份再设置容器 包级的 RouterGroup 变量可以被多个 Server 共用
+	scoped := *group
+	scoped.container = container
+	container.Provide(scoped.initHandle)
+	return scoped.regHandle(r, &scoped)
 }
 
 // Reg registers handle by DI
 func (group RouterGroup) Reg(function interface{}, opts ...dig.InvokeOption) error {
+	if group.container != nil {
+		return group.container.DI(function, opts...)
+	}
 	return GetDig().DI(function, opts...)
 }
@@ -0,0 +1,267 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/redis/go-redis/v9"
+	echoSwagger "github.com/swaggo/echo-swagger"
+	"go.uber.org/dig"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ServerDeps Build 之后解析出来的依赖
+// 同时注册到 Server 自己的依赖容器中 路由的构造函数可以直接声明 *Config、*gorm.DB、*redis.Client 或者 *ServerDeps 参数
+type ServerDeps struct {
+	Config    *Config
+	DB        *gorm.DB
+	Redis     *redis.Client
+	JwtKeys   *JwtKeySet
+	Container *DigContainer
+
+	tokenManager      *TokenManager
+	captchaManager    *CaptchaManager
+	verifyCodeManager *VerifyCodeManager
+	permission        *RolePermission
+	validator         *Validator
+	loggerOptions     LoggerOptions
+}
+
+// defaultDeps GetConfig、GetGormDB、GetRedisCache 等包级方法使用的 Server 只用于兼容旧的调用方式
+// 处理请求时使用 GetContextConfig、GetContextTokenManager 等方法 读取处理这个请求的 Server 的依赖
+// 第一个 Build 的 Server 成为默认 关闭之后由下一个 Build 的 Server 接替 之后 Build 的 Server 不会覆盖
+var defaultDeps *ServerDeps
+
+// Server 可构建的服务对象
+// Build 返回装配好的 *echo.Echo 可以直接配合 httptest 使用 Run 负责监听端口与优雅关闭
+// 配置和依赖通过 Server 的依赖容器和请求上下文传递 同一进程内可以 Build 多个 Server
+type Server struct {
+	config      Config
+	routerGroup []*RouterGroup
+	option      ServerRunOption
+	echo        *echo.Echo
+	deps        *ServerDeps
+}
+
+// NewServerFromConfig 使用显式传入的配置创建服务 不会读取 ./application.yaml
+func NewServerFromConfig(cfg Config, routerGroup []*RouterGroup, option ServerRunOption) *Server {
+	return &Server{
+		config:      cfg,
+		routerGroup: routerGroup,
+		option:      option,
+	}
+}
+
+// Build 连接数据库与 Redis 并注册全部路由 重复调用返回同一个实例
+func (s *Server) Build() (*echo.Echo, *ServerDeps, error) {
+	if s.echo != nil {
+		return s.echo, s.deps, nil
+	}
+	if err := checkIp2RegionFile(&s.config); err != nil {
+		return nil, nil, err
+	}
+	keySet, err := newJwtKeySet(s.config.Jwt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load jwt keys: %w", err)
+	}
+	redisClient, err := newRedisClient(s.config.Redis)
+	if err != nil {
+		return nil, nil, fmt.Errorf("connect redis: %w", err)
+	}
+	deps := &ServerDeps{
+		Config:    &s.config,
+		Redis:     redisClient,
+		JwtKeys:   keySet,
+		Container: &DigContainer{Container: dig.New()},
+
+		tokenManager:   newTokenManager(&s.config, redisClient, keySet),
+		captchaManager: newCaptchaManager(&s.config, redisClient),
+		validator:      newValidator(&s.config),
+		loggerOptions:  s.option.LoggerOptions,
+	}
+	notifier := s.option.Notifier
+	if notifier == nil {
+		notifier = defaultNotifier(s.config.Notify)
+	}
+	deps.verifyCodeManager = newVerifyCodeManager(&s.config, redisClient, notifier)
+	// 出错时关闭已经打开的连接 并且不再作为默认的 Server
+	abort := func(err error) (*echo.Echo, *ServerDeps, error) {
+		_ = runStopHooks(context.Background(), coreLifecycleHooks(deps))
+		return nil, nil, err
+	}
+	// GormGlobalHook 中可能会用到 GetRedisCache 等包级方法 所以在连接数据库之前设置默认值
+	isDefault := defaultDeps == nil
+	if isDefault {
+		useDefaultDeps(deps, s.option)
+	}
+	if deps.DB, err = openDataBase(s.config.DataBase, s.config.Server.Dev, s.option.GormOptions.GormGlobalHook); err != nil {
+		return abort(fmt.Errorf("connect database: %w", err))
+	}
+	if isDefault {
+		db = deps.DB
+	}
+	if len(s.option.Migrations) > 0 {
+		if err = NewMigrator(deps.DB, s.option.Migrations...).Up(); err != nil {
+			return abort(err)
+		}
+	}
+	// 权限缓存从数据库加载 需要在迁移之后初始化
+	deps.permission = newRolePermission(redisClient, deps.DB, s.option.PermissionsOptions)
+	if isDefault {
+		PermissionMange = deps.permission
+		initLogMiddleware(s.option.LoggerOptions)
+		initNotifier(notifier)
+	}
+	deps.Container.Provide(func() *ServerDeps { return deps })
+	deps.Container.Provide(func() *Config { return deps.Config })
+	deps.Container.Provide(func() *gorm.DB { return deps.DB })
+	deps.Container.Provide(func() *redis.Client { return deps.Redis })
+
+	e := echo.New()
+	// 关闭Banner
+	e.HideBanner = true
+	// 全局错误方法
+	e.HTTPErrorHandler = EchoError()
+	// 客户端 IP 只信任配置的反向代理 登录锁定和限流都依赖它
+	if e.IPExtractor, err = ipExtractor(s.config.Server.TrustedProxies); err != nil {
+		return abort(err)
+	}
+	// 全局日志接管Echo 日志
+	e.Logger = GetLogger()
+	// 使用中间件
+	e.Use(bindServerDeps(deps))
+	e.Use(SetEchoContext)
+	e.Use(RequestLoggerMiddleware)
+	e.Use(RecoverMiddleware)
+	e.Use(middleware.CORS())
+	for _, group := range s.routerGroup {
+		if err = registerGroup(e.Group(s.config.Server.GlobalPrefix), group, deps.Container); err != nil {
+			return abort(err)
+		}
+	}
+	e.GET(JwksPath, JwksHandler)
+	// 生产环境下不打开Swagger
+	if s.config.Server.Dev {
+		e.GET("/swagger/*", echoSwagger.WrapHandler)
+	}
+	if s.option.BeforeRun != nil {
+		s.option.BeforeRun(e)
+	}
+	ResolveRoutes(e)
+	s.echo = e
+	s.deps = deps
+	return e, deps, nil
+}
+
+// Run 启动服务 阻塞直到 ctx 结束或者监听失败
+// 结束后等待正在处理的请求完成 然后逆序执行 OnStop 钩子
+func (s *Server) Run(ctx context.Context) error {
+	e, deps, err := s.Build()
+	if err != nil {
+		return err
+	}
+	hooks := append(coreLifecycleHooks(deps), s.option.Hooks...)
+	timeout := shutdownTimeout(s.option, s.config.Server)
+
+	// 钩子的 ctx 带上 Server 的依赖 后台任务通过 NewBackgroundContext 使用
+	started, err := runStartHooks(withServerDeps(ctx, deps), hooks)
+	if err != nil {
+		stopCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// 只关闭已经启动的钩子 内置钩子排在最前面 始终会被关闭
+		return errors.Join(err, runStopHooks(withServerDeps(stopCtx, deps), hooks[:started]))
+	}
+
+	startErr := make(chan error, 1)
+	go func() {
+		startErr <- e.Start(fmt.Sprintf(":%d", s.config.Server.HttpPort))
+	}()
+	fmt.Println(fmt.Sprintf(`%s==> Server Started !%s`, logger.Green, logger.Reset))
+	if s.config.Server.Dev {
+		fmt.Println(fmt.Sprintf(`%s==> Swagger FilePath: %s %s`, logger.Green, fmt.Sprintf("http://127.0.0.1:%d/swagger/index.html", s.config.Server.HttpPort), logger.Reset))
+	}
+
+	var serveErr error
+	select {
+	case serveErr = <-startErr:
+		if errors.Is(serveErr, http.ErrServerClosed) {
+			serveErr = nil
+		}
+	case <-ctx.Done():
+		zap.L().Info("server context done, draining in-flight requests", zap.Duration("timeout", timeout))
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err = e.Shutdown(stopCtx); err != nil {
+		serveErr = errors.Join(serveErr, fmt.Errorf("echo shutdown: %w", err))
+	}
+	serveErr = errors.Join(serveErr, runStopHooks(withServerDeps(stopCtx, deps), hooks))
+	fmt.Println(fmt.Sprintf(`%s==> Server Stopped !%s`, logger.Green, logger.Reset))
+	return serveErr
+}
+
+// Close 关闭 Build 打开的数据库和 Redis 连接 用于只 Build 没有 Run 的 Server 例如测试 Run 结束时会自动关闭
+func (s *Server) Close() error {
+	if s.deps == nil {
+		return nil
+	}
+	err := runStopHooks(context.Background(), coreLifecycleHooks(s.deps))
+	s.echo = nil
+	s.deps = nil
+	return err
+}
+
+// useDefaultDeps 把 Server 的依赖设置为包级方法的默认值 数据库在连接之后再设置
+func useDefaultDeps(deps *ServerDeps, option ServerRunOption) {
+	defaultDeps = deps
+	config = deps.Config
+	db = deps.DB
+	innerRedis = deps.Redis
+	jwtKeys = deps.JwtKeys
+	tokenManager = nil
+	captchaManager = nil
+	verifyCodeManager = nil
+	ip2RegionSearcher = nil
+	initGormConfig(option.GormOptions)
+}
+
+// releaseDefaultDeps Server 关闭之后不再作为默认值
+func releaseDefaultDeps(deps *ServerDeps) {
+	if defaultDeps == deps {
+		defaultDeps = nil
+	}
+}
+
+// ipExtractor 没有配置可信代理时使用连接的 IP 配置之后只从可信代理转发的 X-Forwarded-For 中读取
+func ipExtractor(proxies []string) (echo.IPExtractor, error) {
+	if len(proxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", proxy)
+			}
+			proxy = ip.String() + "/128"
+			if ip.To4() != nil {
+				proxy = ip.String() + "/32"
+			}
+		}
+		_, ipRange, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", proxy)
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
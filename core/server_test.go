@@ -0,0 +1,47 @@
+package core
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+// 只有来自可信代理的请求才读取 X-Forwarded-For
+func TestIPExtractorTrustsConfiguredProxies(t *testing.T) {
+	direct, err := ipExtractor(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trusted, err := ipExtractor([]string{"10.0.0.1", "192.168.0.0/16"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name    string
+		remote  string
+		forward string
+		direct  string
+		trusted string
+	}{
+		{"client", "203.0.113.9:1234", "", "203.0.113.9", "203.0.113.9"},
+		{"forged header", "203.0.113.9:1234", "198.51.100.1", "203.0.113.9", "203.0.113.9"},
+		{"trusted proxy", "10.0.0.1:1234", "198.51.100.1", "10.0.0.1", "198.51.100.1"},
+		{"trusted range", "192.168.3.4:1234", "198.51.100.1, 10.0.0.1", "192.168.3.4", "198.51.100.1"},
+		{"private network", "172.16.0.1:1234", "198.51.100.1", "172.16.0.1", "172.16.0.1"},
+	}
+	for _, item := range cases {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = item.remote
+		if item.forward != "" {
+			req.Header.Set("X-Forwarded-For", item.forward)
+		}
+		if ip := direct(req); ip != item.direct {
+			t.Errorf("%s: direct got %s want %s", item.name, ip, item.direct)
+		}
+		if ip := trusted(req); ip != item.trusted {
+			t.Errorf("%s: trusted got %s want %s", item.name, ip, item.trusted)
+		}
+	}
+	if _, err = ipExtractor([]string{"not-an-ip"}); err == nil {
+		t.Fatal("invalid proxy accepted")
+	}
+}
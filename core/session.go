@@ -0,0 +1,241 @@
+package core
+
+import (
+	"fmt"
+	"github.com/duke-git/lancet/v2/slice"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"sort"
+	"time"
+)
+
+// 最后活跃时间的更新间隔 毫秒 避免每个请求都写 Redis
+const sessionTouchInterval int64 = 60 * 1000
+
+// SessionInfo 在线会话 每次登录生成一个会话 刷新令牌时沿用同一个会话
+type SessionInfo struct {
+	SessionId       string `json:"sessionId"`
+	UID             int64  `json:"uid"`
+	Username        string `json:"username"`
+	NickName        string `json:"nickName"`
+	Platform        string `json:"platform"`
+	Ip              string `json:"ip"`
+	Location        string `json:"location"`
+	Os              string `json:"os"`
+	Browser         string `json:"browser"`
+	LoginAt         int64  `json:"loginAt"`         // 登录时间 毫秒
+	LastSeen        int64  `json:"lastSeen"`        // 最后活跃时间 毫秒
+	ExpireAt        int64  `json:"expireAt"`        // 访问令牌过期时间 秒
+	RefreshExpireAt int64  `json:"refreshExpireAt"` // 刷新令牌过期时间 秒
+}
+
+// SessionMeta 登录设备的信息
+type SessionMeta struct {
+	Ip       string
+	Location string
+	Os       string
+	Browser  string
+}
+
+// sessionRecord 保存在 Redis 中的会话 记录当前的访问令牌和刷新令牌家族 下线时一起撤销
+type sessionRecord struct {
+	SessionInfo
+	Jti    string `json:"jti"`
+	Family string `json:"family"`
+}
+
+// NewSessionMeta 从请求中读取 IP 归属地和设备信息
+func NewSessionMeta(c echo.Context) SessionMeta {
+	os, browser, _ := GetOs(c)
+	location, _ := IPParse(c.RealIP())
+	return SessionMeta{Ip: c.RealIP(), Location: location, Os: os, Browser: browser}
+}
+
+func (j TokenManager) startSession(claims *Claims, meta SessionMeta, pair TokenPair, family string) {
+	now := GetNowTimeUnixMilli()
+	j.sessions.XHSet(claims.SessionId, sessionRecord{
+		SessionInfo: SessionInfo{
+			SessionId:       claims.SessionId,
+			UID:             claims.UID,
+			Username:        claims.Username,
+			NickName:        claims.NickName,
+			Platform:        claims.Platform,
+			Ip:              meta.Ip,
+			Location:        meta.Location,
+			Os:              meta.Os,
+			Browser:         meta.Browser,
+			LoginAt:         now,
+			ExpireAt:        pair.ExpireAt,
+			RefreshExpireAt: pair.RefreshExpireAt,
+		},
+		Jti:    claims.ID,
+		Family: family,
+	})
+	j.sessionSeen.XHSet(claims.SessionId, now)
+	j.userSessionIndex(claims.UID).XHSet(claims.SessionId, claims.Platform)
+}
+
+// userSessionIndex 用户的会话ID和平台 下线和同时登录策略只读取这个索引 不扫描全部会话
+func (j TokenManager) userSessionIndex(uid int64) *RedisCache[string] {
+	return &RedisCache[string]{Client: j.sessions.Client, key: fmt.Sprintf("sys:token:session:user:%d", uid)}
+}
+
+// userSessions 用户在指定平台的会话 platform 为空时返回全部平台 顺便清理索引中已经失效的会话
+func (j TokenManager) userSessions(uid int64, platform string) []sessionRecord {
+	now := GetNowTimeUnix()
+	index := j.userSessionIndex(uid)
+	result := make([]sessionRecord, 0)
+	for sessionId, sessionPlatform := range index.XHGetAll() {
+		if platform != "" && sessionPlatform != platform {
+			continue
+		}
+		record := j.sessions.XHGet(sessionId)
+		if record.SessionId == "" {
+			index.XHDel(sessionId)
+			continue
+		}
+		if record.ExpireAt <= now && record.RefreshExpireAt <= now {
+			j.dropSession(record)
+			continue
+		}
+		result = append(result, record)
+	}
+	return result
+}
+
+// renewSession 刷新令牌之后更新会话的令牌
+func (j TokenManager) renewSession(claims *Claims, pair TokenPair) {
+	if claims.SessionId == "" {
+		return
+	}
+	record := j.sessions.XHGet(claims.SessionId)
+	if record.SessionId == "" {
+		return
+	}
+	record.Jti = claims.ID
+	record.ExpireAt = pair.ExpireAt
+	record.RefreshExpireAt = pair.RefreshExpireAt
+	j.sessions.XHSet(claims.SessionId, record)
+	j.sessionSeen.XHSet(claims.SessionId, GetNowTimeUnixMilli())
+}
+
+// TouchSession 更新会话的最后活跃时间
+func (j TokenManager) TouchSession(sessionId string) {
+	if sessionId == "" {
+		return
+	}
+	now := GetNowTimeUnixMilli()
+	if now-j.sessionSeen.XHGet(sessionId) < sessionTouchInterval {
+		return
+	}
+	j.sessionSeen.XHSet(sessionId, now)
+}
+
+// Sessions 全部在线会话 按最后活跃时间倒序 顺便清理已经失效的会话 需要扫描全部会话 只用于在线用户列表
+func (j TokenManager) Sessions() []SessionInfo {
+	now := GetNowTimeUnix()
+	seen := j.sessionSeen.XHGetAll()
+	result := make([]SessionInfo, 0)
+	for sessionId, record := range j.sessions.XHGetAll() {
+		// 访问令牌过期之后 刷新令牌有效的会话还能继续使用
+		if record.ExpireAt <= now && record.RefreshExpireAt <= now {
+			j.dropSession(record)
+			continue
+		}
+		record.LastSeen = seen[sessionId]
+		result = append(result, record.SessionInfo)
+	}
+	sort.Slice(result, func(a, b int) bool {
+		return result[a].LastSeen > result[b].LastSeen
+	})
+	return result
+}
+
+// RemoveSession 强制会话下线 会话的访问令牌和刷新令牌都会失效
+func (j TokenManager) RemoveSession(sessionId string) bool {
+	if sessionId == "" {
+		return false
+	}
+	record := j.sessions.XHGet(sessionId)
+	if record.SessionId == "" {
+		return false
+	}
+	field := fmt.Sprintf("%d:%s", record.UID, record.Platform)
+	if claims, ok := j.tokenClaims(j.XHGet(field).Token); ok && claims.ID == record.Jti {
+		j.XHDel(field)
+	}
+	j.endSession(record)
+	return true
+}
+
+// IsKicked 会话是否因为同时登录策略被踢下线
+func (j TokenManager) IsKicked(sessionId string) bool {
+	return sessionId != "" && j.kickedSessions.XCodeExists(sessionId)
+}
+
+// enforceLoginPolicy 按平台的同时登录策略 从最早登录的会话开始踢下线 给新会话留出位置
+func (j TokenManager) enforceLoginPolicy(uid int64, platform string) {
+	policy, maxDevices := j.GetPlatformLoginPolicy(platform)
+	var keep int
+	switch policy {
+	case LoginPolicySingle:
+		keep = 0
+	case LoginPolicyLimit:
+		keep = max(maxDevices-1, 0)
+	default:
+		return
+	}
+	sessions := slice.Map(j.userSessions(uid, platform), func(_ int, item sessionRecord) SessionInfo {
+		return item.SessionInfo
+	})
+	if len(sessions) <= keep {
+		return
+	}
+	sort.Slice(sessions, func(a, b int) bool {
+		return sessions[a].LoginAt < sessions[b].LoginAt
+	})
+	for _, item := range sessions[:len(sessions)-keep] {
+		j.kickSession(item)
+	}
+}
+
+// kickSession 踢下线 保留标记到会话的令牌全部过期 期间使用该会话的令牌返回 TOKEN_KICKED_ERROR
+func (j TokenManager) kickSession(info SessionInfo) {
+	if ttl := time.Until(time.Unix(max(info.ExpireAt, info.RefreshExpireAt), 0)); ttl > 0 {
+		j.kickedSessions.XSetCodeEX(info.SessionId, GetNowTimeUnix(), ttl)
+	}
+	j.RemoveSession(info.SessionId)
+}
+
+// removeUserSessions 下线用户在指定平台的会话 platform 为空时下线全部平台
+func (j TokenManager) removeUserSessions(uid int64, platform string) {
+	for _, record := range j.userSessions(uid, platform) {
+		j.endSession(record)
+	}
+}
+
+func (j TokenManager) endSession(record sessionRecord) {
+	if record.Jti != "" {
+		j.RevokeClaims(Claims{RegisteredClaims: jwt.RegisteredClaims{
+			ID:        record.Jti,
+			ExpiresAt: jwt.NewNumericDate(time.Unix(record.ExpireAt, 0)),
+		}})
+	}
+	j.dropSession(record)
+}
+
+// dropSession 删除会话和索引 不撤销令牌
+func (j TokenManager) dropSession(record sessionRecord) {
+	j.sessions.XHDel(record.SessionId)
+	j.sessionSeen.XHDel(record.SessionId)
+	j.userSessionIndex(record.UID).XHDel(record.SessionId)
+}
+
+func (j TokenManager) tokenClaims(token string) (Claims, bool) {
+	claims := Claims{}
+	if token == "" {
+		return claims, false
+	}
+	_, err := jwt.ParseWithClaims(token, &claims, j.keys.KeyFunc)
+	return claims, err == nil
+}
@@ -4,21 +4,33 @@ import (
 	"fmt"
 	"github.com/duke-git/lancet/v2/slice"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
-	"time"
 )
 
 var tokenManager *TokenManager
 
+// GetTokenManager 默认 Server 的令牌管理 处理请求时使用 GetContextTokenManager
 func GetTokenManager() *TokenManager {
+	if defaultDeps != nil {
+		return defaultDeps.tokenManager
+	}
 	if tokenManager == nil {
-		initTokenManger()
+		tokenManager = newTokenManager(GetConfig(), getRedisClient(), getJwtKeySet())
 	}
 	return tokenManager
 }
 
 type TokenManager struct {
 	*RedisCache[TokenInfo]
+	refreshTokens  *RedisCache[refreshTokenInfo]
+	deniedTokens   *RedisCache[int64]
+	revokedUsers   *RedisCache[int64]
+	sessions       *RedisCache[sessionRecord]
+	sessionSeen    *RedisCache[int64]
+	kickedSessions *RedisCache[int64]
+	config         *Config
+	keys           *JwtKeySet
 }
 type TokenInfo struct {
 	Token    string `json:"token"`
@@ -31,38 +43,53 @@ type ClaimsAdditions struct {
 	NickName     string   `json:"nickName"`     // 昵称
 	RoleCodes    []string `json:"roleCodes"`    // 角色码
 	Platform     string   `json:"platform"`
+	SessionId    string   `json:"sid"` // 会话ID 同一次登录刷新令牌时不变
 }
 type Claims struct {
 	ClaimsAdditions
 	jwt.RegisteredClaims
 }
 
-func initTokenManger() {
-	tokenManager = &TokenManager{
-		RedisCache: GetRedisCache[TokenInfo]("sys:token:info:"),
+// newTokenManager 令牌和会话保存在 client 中 使用 keys 签名 过期时间和登录策略读取 cfg
+func newTokenManager(cfg *Config, client *redis.Client, keys *JwtKeySet) *TokenManager {
+	return &TokenManager{
+		RedisCache:     NewRedisCache[TokenInfo](client, "sys:token:info:"),
+		refreshTokens:  NewRedisCache[refreshTokenInfo](client, "sys:token:refresh:"),
+		deniedTokens:   NewRedisCache[int64](client, "sys:token:deny:"),
+		revokedUsers:   NewRedisCache[int64](client, "sys:token:revoke"),
+		sessions:       NewRedisCache[sessionRecord](client, "sys:token:session"),
+		sessionSeen:    NewRedisCache[int64](client, "sys:token:session:seen"),
+		kickedSessions: NewRedisCache[int64](client, "sys:token:kicked:"),
+		config:         cfg,
+		keys:           keys,
 	}
 }
 func (j TokenManager) GetJwtExpirationTime(tokenStr string) int64 {
 	claims := &Claims{}
-	_, _ = jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(GetConfig().Jwt.JwtKey), nil
-	})
+	_, _ = jwt.ParseWithClaims(tokenStr, claims, j.keys.KeyFunc)
 	return claims.ExpiresAt.Unix()
 }
 func (j TokenManager) ParseJwt(token string, platform ...string) (Claims, *CodeError) {
 	selectPlatform := AdditionFirst(platform, "")
-	key := GetConfig().Jwt.JwtKey
 	claims := Claims{}
-	tkn, err := jwt.ParseWithClaims(token, &claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(key), nil
-	})
+	tkn, err := jwt.ParseWithClaims(token, &claims, j.keys.KeyFunc)
 	if (err != nil) || (tkn != nil && !tkn.Valid) {
 		zap.L().Info(fmt.Sprintf("Token 解析出错！%#v", err))
 		return claims, NewErrCodeMsg(TOKEN_EXPIRE_ERROR, err.Error())
 	}
+	if j.IsKicked(claims.SessionId) {
+		return claims, NewErrCode(TOKEN_KICKED_ERROR)
+	}
+	if j.IsRevoked(claims) {
+		return claims, NewErrCodeMsg(TOKEN_EXPIRE_ERROR, "token已失效")
+	}
 	// 检测严格模式
+	// 有会话的 token 要求会话仍然存在
 	if j.GetPlatformStrict(selectPlatform) {
-		exists := GetTokenManager().XHExists(fmt.Sprintf("%d:%s", claims.UID, claims.Platform))
+		exists := j.sessions.XHExists(claims.SessionId)
+		if claims.SessionId == "" {
+			exists = j.XHExists(fmt.Sprintf("%d:%s", claims.UID, claims.Platform))
+		}
 		if !exists {
 			return claims, NewErrCodeMsg(TOKEN_EXPIRE_ERROR, "token已过期")
 		}
@@ -71,7 +98,7 @@ func (j TokenManager) ParseJwt(token string, platform ...string) (Claims, *CodeE
 }
 
 func (j TokenManager) GetPlatformExpiration(platform string) int64 {
-	config := GetConfig().Jwt
+	config := j.config.Jwt
 	if platform == "" {
 		return config.Expire
 	}
@@ -85,7 +112,7 @@ func (j TokenManager) GetPlatformExpiration(platform string) int64 {
 }
 
 func (j TokenManager) GetPlatformStrict(platform string) bool {
-	config := GetConfig().Jwt
+	config := j.config.Jwt
 	if platform == "" {
 		return config.Strict
 	}
@@ -98,35 +125,17 @@ func (j TokenManager) GetPlatformStrict(platform string) bool {
 	return config.Strict
 }
 
-func (j TokenManager) GenJwtString(platform string, user ClaimsAdditions) (string, error) {
-	userJwt := j.GetUserJwt(user.UID, platform)
-	expiration := j.GetPlatformExpiration(platform)
-
-	// 当前的Token 有效 且 过期时间 大于 当前时间 加上 缓存时间/2
-	if userJwt.Token != "" && userJwt.ExpireAt > GetNowLocalTime().Unix()+expiration/2 {
-		return userJwt.Token, nil
-	}
-	// 声明 token 的过期时间
-	expirationTime := GetNowLocalTime().Add(time.Second * time.Duration(expiration))
-	// 创建 JWT claims ，其中包括用户名和过期时间
-	claims := &Claims{
-		ClaimsAdditions: user,
-		RegisteredClaims: jwt.RegisteredClaims{
-			// 在 JWT 中，过期时间表示为 Unix 毫秒时间戳
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-		},
-	}
-	// 声明使用的算法和 claims 来创建 token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	// 创建 JWT 字符串
-	signedString, err := token.SignedString([]byte(GetConfig().Jwt.JwtKey))
-	if err != nil {
-		zap.L().Error(fmt.Sprintf("生成Token出错！%#v", err))
-		return "", err
+// GetPlatformLoginPolicy 平台的同时登录策略 平台没有配置时使用全局配置
+func (j TokenManager) GetPlatformLoginPolicy(platform string) (string, int) {
+	config := j.config.Jwt
+	for _, item := range config.SpecifiedConfig {
+		if platform != "" && item.Platform == platform && item.LoginPolicy != "" {
+			return item.LoginPolicy, item.MaxDevices
+		}
 	}
-	j.SetUserJwt(user.UID, platform, signedString, expirationTime.Unix())
-	return signedString, nil
+	return config.LoginPolicy, config.MaxDevices
 }
+
 func (j TokenManager) SetUserJwt(uid int64, platform, token string, expireAt int64) {
 	j.XHSet(fmt.Sprintf("%d:%s", uid, platform), TokenInfo{
 		Token: token, ExpireAt: expireAt,
@@ -139,9 +148,3 @@ func (j TokenManager) ValidToken(uid int64, platform, token string) bool {
 	tokenInfo := j.XHGet(fmt.Sprintf("%d:%s", uid, platform))
 	return token != "" && tokenInfo.ExpireAt > GetNowTimeUnix()
 }
-func (j TokenManager) RemoveTokenByUid(uid int64) bool {
-	return j.XHDel(fmt.Sprintf("%d:*", uid))
-}
-func (j TokenManager) RemoveToken(uid int64, platform string) bool {
-	return j.XHDel(fmt.Sprintf("%d:%s", uid, platform))
-}
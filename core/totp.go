@@ -0,0 +1,83 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// TOTP 参数 RFC 6238 常用的认证器 App 只支持这组默认值
+const (
+	totpPeriod int64 = 30
+	totpDigits       = 6
+	// 允许前后各一个时间步的误差 兼容手机时间不准
+	totpSkew int64 = 1
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenTotpSecret 生成 TOTP 密钥 base32 编码 160 位
+func GenTotpSecret() (string, error) {
+	buf := make([]byte, 20)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(buf), nil
+}
+
+// TotpURI 认证器 App 扫码使用的 otpauth:// 链接
+func TotpURI(issuer, account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+	label := url.PathEscape(issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// TotpCode 指定时间步的验证码
+func TotpCode(secret string, counter int64) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(strings.TrimSpace(secret)))
+	if err != nil {
+		return "", err
+	}
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000), nil
+}
+
+// ValidateTotp 校验验证码 lastCounter 之前(含)的时间步不再接受 防止同一个验证码被重复使用
+// 校验通过时返回验证码对应的时间步 调用方需要保存下来作为下次的 lastCounter
+func ValidateTotp(secret, code string, lastCounter int64) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits {
+		return 0, false
+	}
+	now := GetNowTimeUnix() / totpPeriod
+	for counter := now - totpSkew; counter <= now+totpSkew; counter++ {
+		if counter <= lastCounter {
+			continue
+		}
+		expect, err := TotpCode(secret, counter)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expect), []byte(code)) == 1 {
+			return counter, true
+		}
+	}
+	return 0, false
+}
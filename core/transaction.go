@@ -0,0 +1,32 @@
+package core
+
+import (
+	"database/sql"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// TxScope 事务作用域
+// 事务执行期间 同一个请求里所有 PreGorm.WithContext 拿到的都是同一个事务
+// 所以传入 tx 或者原来的 echo.Context 都可以
+type TxScope struct {
+	echo.Context
+}
+
+// GetDB 当前事务
+func (s TxScope) GetDB() *gorm.DB {
+	return getContextDB(s.Context)
+}
+
+// Transaction 在事务中执行 fc 返回错误或者 panic 时回滚
+// 嵌套调用时使用 SavePoint 内层回滚不影响外层
+// 事务中的 Statement.Context 仍然是请求的 XContext 全局 Gorm 钩子的数据权限照常生效
+func Transaction(c echo.Context, fc func(tx TxScope) error, opts ...*sql.TxOptions) error {
+	parent := getContextDB(c)
+	previous := c.Get(gormDBContextKey)
+	return parent.WithContext(GetAnyContext(c)).Transaction(func(tx *gorm.DB) error {
+		c.Set(gormDBContextKey, tx)
+		defer c.Set(gormDBContextKey, previous)
+		return fc(TxScope{Context: c})
+	}, opts...)
+}
@@ -12,20 +12,149 @@ type Config struct {
 	Logger          LogConfig
 	Redis           RedisConfig
 	Jwt             JwtConfig
+	Captcha         CaptchaConfig
+	Password        PasswordConfig
+	Notify          NotifyConfig
+	Ldap            LdapConfig
+	Oidc            []OidcConfig
 	Tencent         TencentConfig
 	Ip2RegionConfig Ip2RegionConfig
 }
 type JwtConfig struct {
-	JwtKey            string
-	Expire            int64
+	JwtKey            string         // HS256 密钥 没有 kid 的 token 使用它验证 不需要兼容时可以留空
+	SigningKid        string         // 签名使用的密钥 为空时使用 JwtKey HS256 签名
+	Keys              []JwtKeyConfig // 非对称密钥 轮换时保留旧的公钥直到旧 token 过期
+	Expire            int64          // 访问令牌有效期 秒
+	RefreshExpire     int64          // 刷新令牌有效期 秒 默认 7 天
 	MaxLoginFailCount int64
+	LoginLockDuration int64 // 登录失败次数达到上限之后锁定的秒数 默认 15 分钟 到期自动解锁
 	Strict            bool
+	LoginPolicy       string // 同时登录策略 single 单设备 limit 限制设备数 unlimited 或者为空不限制
+	MaxDevices        int    // limit 策略允许同时登录的设备数
+	TotpIssuer        string // 两步验证 otpauth 链接中的发行方 认证器 App 中显示的名称
 	SpecifiedConfig   []SpecifiedPlatform
 }
+type JwtKeyConfig struct {
+	Kid        string
+	Algorithm  string // RS256 ES256 EdDSA
+	PrivateKey string // PEM 内容或者文件路径 只用于验证的密钥可以不配置
+	PublicKey  string // PEM 内容或者文件路径 为空时从私钥中获取
+}
 type SpecifiedPlatform struct {
-	Platform string
-	Expire   int64
-	Strict   bool
+	Platform      string
+	Expire        int64
+	RefreshExpire int64
+	Strict        bool
+	LoginPolicy   string // 为空时使用全局配置
+	MaxDevices    int
+}
+
+// CaptchaConfig 图片验证码
+type CaptchaConfig struct {
+	Enable     bool   // 登录是否校验验证码
+	FailCount  int64  // 同一个 IP 或者用户名登录失败多少次之后需要验证码 0 表示每次登录都需要
+	FailExpire int64  // 失败次数的统计时长 秒 默认 1 小时
+	Mode       string // char 字符(默认) math 算术
+	Length     int    // 字符验证码的长度 默认 4
+	Expire     int64  // 验证码有效期 秒 默认 120
+}
+
+// PasswordConfig 密码策略
+type PasswordConfig struct {
+	MinLength      int      // 最小长度 0 表示不限制
+	CharClasses    []string // 必须包含的字符类型 upper 大写字母 lower 小写字母 digit 数字 symbol 特殊字符
+	ForbiddenWords []string // 不能包含的词 不区分大小写 用户名总是不能包含
+	HistoryCount   int      // 不能和最近几次使用过的密码相同 0 表示不限制
+	MaxAge         int      // 密码有效期 天 过期之后登录需要修改密码 0 表示不过期
+}
+
+// LdapConfig LDAP 和 Active Directory 登录
+type LdapConfig struct {
+	Url                string         // ldap://host:389 或者 ldaps://host:636 为空时不启用
+	StartTLS           bool           // ldap:// 连接之后升级为 TLS
+	InsecureSkipVerify bool           // 不校验服务器证书 只在测试环境使用
+	Timeout            int64          // 连接和查询的超时时间 秒 默认 10
+	BindDN             string         // 查询用户使用的账号 为空时匿名查询
+	BindPassword       string         // 查询用户使用的密码
+	BaseDN             string         // 搜索用户的根节点
+	UserFilter         string         // 搜索用户的过滤器 {username} 替换为登录账号 默认 (uid={username}) AD 一般是 (sAMAccountName={username})
+	GroupBaseDN        string         // 搜索组的根节点 为空时只使用用户的 memberOf 属性
+	GroupFilter        string         // 搜索组的过滤器 {dn} 替换为用户 DN {username} 替换为登录账号 默认 (member={dn})
+	Attributes         LdapAttributes // 用户属性和 SysUser 字段的对应关系
+	GroupRoles         []LdapGroupRole
+	DefaultRoles       []string // 没有匹配到任何组时的角色
+	AutoRegister       bool     // 第一次登录时自动创建用户 否则创建禁用的用户等待管理员开通
+}
+
+// LdapAttributes 用户属性名 为空时使用默认值
+type LdapAttributes struct {
+	ID       string // 唯一标识 默认使用用户 DN AD 可以使用 objectGUID
+	Username string // 默认 uid AD 一般是 sAMAccountName
+	NickName string // 默认 displayName
+	RealName string // 默认 cn
+	Email    string // 默认 mail
+	Phone    string // 默认 mobile
+}
+
+// LdapGroupRole 组和角色的对应关系
+type LdapGroupRole struct {
+	Group string   // 组的 DN 或者 cn 不区分大小写
+	Roles []string // 角色编码
+}
+
+// OidcConfig OAuth2/OIDC 登录 可以配置多个身份提供方
+type OidcConfig struct {
+	Name         string          // 登录方式名称 也是三方绑定的平台 不能和其他登录方式重名 例如 keycloak
+	Issuer       string          // 发行方 从 {Issuer}/.well-known/openid-configuration 获取接口地址
+	ClientId     string          // 客户端 ID
+	ClientSecret string          // 客户端密钥 公共客户端为空 只使用 PKCE
+	RedirectUrl  string          // 回调地址 身份提供方带着 code 和 state 跳转到这里
+	Scopes       []string        // 默认 openid profile email
+	Claims       OidcClaims      // 声明和 SysUser 字段的对应关系
+	ClaimRoles   []OidcClaimRole // Claims.Roles 中的值和角色的对应关系
+	DefaultRoles []string        // 没有匹配到任何角色时的角色
+	AutoRegister bool            // 第一次登录时自动创建用户 否则创建禁用的用户等待管理员开通
+}
+
+// OidcClaims 声明名称 为空时使用默认值
+type OidcClaims struct {
+	Username string // 默认 preferred_username 没有时使用 sub
+	NickName string // 默认 name
+	RealName string // 默认 name
+	Email    string // 默认 email
+	Phone    string // 默认 phone_number
+	Avatar   string // 默认 picture
+	Roles    string // 角色或者组的声明 例如 groups 为空或者没有配置 ClaimRoles 时不同步角色
+}
+
+// OidcClaimRole 声明值和角色的对应关系
+type OidcClaimRole struct {
+	Value string   // Claims.Roles 中的一个值 区分大小写
+	Roles []string // 角色编码
+}
+
+// NotifyConfig 邮件和短信通知
+type NotifyConfig struct {
+	Smtp       SmtpConfig       // 配置了 Host 时通过 SMTP 发送邮件 否则只写日志
+	VerifyCode VerifyCodeConfig // 验证码
+}
+
+// SmtpConfig 发送邮件的 SMTP 服务
+type SmtpConfig struct {
+	Host     string
+	Port     int    // 默认 SSL 时 465 否则 25
+	Username string // 为空时不认证
+	Password string
+	From     string // 发件人 例如 系统通知 <noreply@example.com> 为空时使用 Username
+	SSL      bool   // 使用 SSL 连接 一般是 465 端口 否则服务器支持时使用 STARTTLS
+}
+
+// VerifyCodeConfig 邮件和短信验证码
+type VerifyCodeConfig struct {
+	Length      int   // 验证码长度 默认 6
+	Expire      int64 // 有效期 秒 默认 300
+	Interval    int64 // 同一个账号两次发送的最小间隔 秒 默认 60
+	MaxAttempts int64 // 最多可以尝试几次 超过之后验证码作废 默认 5
 }
 type LogConfig struct {
 	Level         string // Level 最低日志等级，DEBUG<INFO<WARN<ERROR<FATAL 例如：info-->收集info等级以上的日志
@@ -44,6 +173,8 @@ type ServerConfig struct {
 	ServerDomain     string
 	FrontDomain      string
 	BaseStaticFolder string
+	ShutdownTimeout  int      // 优雅关闭等待的秒数 默认10秒
+	TrustedProxies   []string // 可信的反向代理 IP 或者 CIDR 请求来自这些地址时从 X-Forwarded-For 读取客户端 IP 为空时使用连接的 IP
 }
 
 type RedisConfig struct {
@@ -60,6 +191,13 @@ type TencentConfig struct {
 	WorkWechat WorkWechat
 	Cos        Cos
 	WechatApp  WechatApp
+	Wechat     Wechat
+}
+
+// Wechat 微信网页授权 公众号或者开放平台网站应用 用于绑定微信账号
+type Wechat struct {
+	AppId     string
+	AppSecret string
 }
 
 type WechatApp struct {
@@ -77,6 +215,23 @@ type WorkWechat struct {
 	AgentId        int64
 	RedirectionUrl string
 	DefaultRoles   []string
+	ApiHost        string         // 企业微信接口地址 为空时使用 https://qyapi.weixin.qq.com 测试时可以指向本地的模拟服务
+	Sync           WorkWechatSync // 通讯录同步
+	Callback       WorkWechatCallback
+}
+
+// WorkWechatCallback 企业微信回调 在应用的接收消息或者通讯录同步的接收事件服务器中设置
+type WorkWechatCallback struct {
+	Token          string
+	EncodingAESKey string // 43 位的消息加密密钥
+}
+
+// WorkWechatSync 企业微信通讯录同步 部门和成员同步到 sys_department 和 sys_user
+type WorkWechatSync struct {
+	Interval     int64  // 定时同步的间隔 秒 0 表示不定时同步 只能手动同步
+	Secret       string // 通讯录同步的 Secret 为空时使用 CorpSecret 只能同步应用可见范围内的部门和成员
+	DepartmentId int64  // 从哪个企业微信部门开始同步 默认 1 根部门
+	ParentId     int64  // 同步到本地的哪个部门下面 默认 0 顶级部门
 }
 
 type Cos struct {
@@ -95,8 +250,14 @@ type OrderParam struct {
 	SortType string `json:"sortType" form:"sortType" query:"sortType" zh_comment:"排序规则" en_comment:"sortType" validate:"oneof=ASC DESC ''"` // ASC DESC
 }
 
+// Inject 排序字段必须是模型上的字段 不存在时查询返回错误
 func (r OrderParam) Inject(db *gorm.DB) {
-	db.Order(LowerCamelCaseToSnake(r.SortName) + " " + r.SortType)
+	if r.SortName == "" {
+		return
+	}
+	if err := orderByColumn(db, r); err != nil {
+		_ = db.AddError(err)
+	}
 }
 
 type PageParam struct {
@@ -104,6 +265,32 @@ type PageParam struct {
 	PageSize int `json:"pageSize" form:"pageSize" query:"pageSize" zh_comment:"每页条数" en_comment:"pageSize" validate:"required,gte=1"` // 必填，每页条数值>=1
 }
 
+// 分页模式
+const (
+	PageModeOffset = "page"
+	PageModeCursor = "cursor"
+)
+
+// PageCursorParam 同时支持页码分页和游标分页 Mode=cursor 时使用游标分页 不需要 Page
+type PageCursorParam struct {
+	Page     int    `json:"page" form:"page" query:"page" zh_comment:"当前页数" en_comment:"page" validate:"required_unless=Mode cursor,omitempty,gte=1"`
+	PageSize int    `json:"pageSize" form:"pageSize" query:"pageSize" zh_comment:"每页条数" en_comment:"pageSize" validate:"required,gte=1"`
+	Mode     string `json:"mode" form:"mode" query:"mode" zh_comment:"分页模式" en_comment:"mode" validate:"omitempty,oneof=page cursor"` // page cursor 默认 page
+	Cursor   string `json:"cursor" form:"cursor" query:"cursor" zh_comment:"游标" en_comment:"cursor" validate:""`
+}
+
+func (r PageCursorParam) IsCursor() bool {
+	return r.Mode == PageModeCursor
+}
+
+func (r PageCursorParam) ToPageParam() PageParam {
+	return PageParam{Page: r.Page, PageSize: r.PageSize}
+}
+
+func (r PageCursorParam) ToCursorParam() CursorParam {
+	return CursorParam{Cursor: r.Cursor, PageSize: r.PageSize}
+}
+
 type PageResult struct {
 	PageParam
 	Total    int64 `json:"total"`
@@ -121,17 +308,27 @@ func NewPageResultList[T any](item ...T) PageResultList[T] {
 	}
 }
 
+// NewPageResultListFrom 在内存中分页 适合数据不在数据库中的列表
+func NewPageResultListFrom[T any](param PageParam, items []T) PageResultList[T] {
+	result := PageResultList[T]{PageResult: PageResult{PageParam: param, Total: int64(len(items))}}
+	start := min(max(param.Page-1, 0)*param.PageSize, len(items))
+	end := min(start+param.PageSize, len(items))
+	result.Items = items[start:end]
+	result.LastPage = end >= len(items)
+	return result
+}
+
 type QueryIds struct {
 	Ids []int64 `json:"ids" query:"ids" form:"ids"  zh_comment:"UID" en_comment:"ids" validate:"required"`
 }
 
 type BaseModel struct {
-	ID           int64          `gorm:"column:id;type:int(255);primaryKey;autoIncrement:true;comment:主键" json:"id"`     // 主键
-	EnableStatus int64          `gorm:"column:enable_status;type:int(1);comment:启用状态" json:"enableStatus"`              // 启用状态
-	CreateDept   string         `gorm:"column:create_dept;type:varchar(255);comment:创建部门" json:"createDept"`            // 创建部门
-	CreateBy     int64          `gorm:"column:create_by;type:int(11);comment:创建者" json:"createBy"`                      // 创建者
-	CreateTime   Time           `gorm:"column:create_time;autoCreateTime;type:datetime;comment:创建时间" json:"createTime"` // 创建时间
-	UpdateBy     int64          `gorm:"column:update_by;type:int(11);comment:更新者" json:"updateBy"`                      // 更新者
-	UpdateTime   Time           `gorm:"column:update_time;autoUpdateTime;type:datetime;comment:更新时间" json:"updateTime"` // 更新时间
-	DeleteTime   gorm.DeletedAt `gorm:"column:delete_time;type:datetime;comment:删除时间" json:"deleteTime"`                // 删除时间
+	ID           int64          `gorm:"column:id;primaryKey;autoIncrement:true;comment:主键" json:"id"`        // 主键
+	EnableStatus int64          `gorm:"column:enable_status;comment:启用状态" json:"enableStatus"`               // 启用状态
+	CreateDept   string         `gorm:"column:create_dept;type:varchar(255);comment:创建部门" json:"createDept"` // 创建部门
+	CreateBy     int64          `gorm:"column:create_by;comment:创建者" json:"createBy"`                        // 创建者
+	CreateTime   Time           `gorm:"column:create_time;autoCreateTime;comment:创建时间" json:"createTime"`    // 创建时间
+	UpdateBy     int64          `gorm:"column:update_by;comment:更新者" json:"updateBy"`                        // 更新者
+	UpdateTime   Time           `gorm:"column:update_time;autoUpdateTime;comment:更新时间" json:"updateTime"`    // 更新时间
+	DeleteTime   gorm.DeletedAt `gorm:"column:delete_time;comment:删除时间" json:"deleteTime"`                   // 删除时间
 }
@@ -20,6 +20,7 @@ type Validator struct {
 	englishTranslator ut.Translator
 	chineseValidator  *validator.Validate
 	englishValidator  *validator.Validate
+	config            *Config // 密码策略使用的配置 为空时使用全局配置
 }
 
 func GetValidator() *Validator {
@@ -29,6 +30,16 @@ func GetValidator() *Validator {
 	return validate
 }
 func initValidator() *Validator {
+	v := newValidator(nil)
+	if v != nil {
+		validate = v
+	}
+	return v
+}
+
+// newValidator 创建校验器 cfg 是 Server 的配置 password 标签按它的密码策略校验
+func newValidator(cfg *Config) *Validator {
+	v := &Validator{config: cfg}
 	chineseDict := zh.New()
 	englishDict := en.New()
 	//设置国际化翻译器
@@ -44,6 +55,9 @@ func initValidator() *Validator {
 	if err != nil {
 		return nil
 	}
+	if err = registerPasswordValidation(chineseValidator, chineseTranslator, v.passwordPolicy); err != nil {
+		return nil
+	}
 	chineseValidator.RegisterTagNameFunc(func(fld reflect.StructField) string {
 		return fld.Tag.Get("zh_comment")
 	})
@@ -51,21 +65,29 @@ func initValidator() *Validator {
 	if err != nil {
 		return nil
 	}
+	if err = registerPasswordValidation(englishValidator, englishTranslator, v.passwordPolicy); err != nil {
+		return nil
+	}
 	englishValidator.RegisterTagNameFunc(func(fld reflect.StructField) string {
 		return fld.Name
 	})
-	var v = &Validator{
-		selectTranslator:  chineseTranslator,
-		selectValidator:   chineseValidator,
-		chineseTranslator: chineseTranslator,
-		englishTranslator: englishTranslator,
-		chineseValidator:  chineseValidator,
-		englishValidator:  englishValidator,
-	}
-	validate = v
+	v.selectTranslator = chineseTranslator
+	v.selectValidator = chineseValidator
+	v.chineseTranslator = chineseTranslator
+	v.englishTranslator = englishTranslator
+	v.chineseValidator = chineseValidator
+	v.englishValidator = englishValidator
 	return v
 }
 
+// passwordPolicy 当前使用的密码策略
+func (v *Validator) passwordPolicy() PasswordConfig {
+	if v.config != nil {
+		return v.config.Password
+	}
+	return GetConfig().Password
+}
+
 // ValidateStruct 用于绑定地址栏参数，支持结构体和结构体数组的验证
 func (v *Validator) ValidateStruct(param interface{}) (err error) {
 	if v.selectValidator == nil {
@@ -0,0 +1,151 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultVerifyCodeLength            = 6
+	defaultVerifyCodeExpire      int64 = 5 * 60
+	defaultVerifyCodeInterval    int64 = 60
+	defaultVerifyCodeMaxAttempts int64 = 5
+)
+
+var verifyCodeManager *VerifyCodeManager
+
+// GetVerifyCodeManager 默认 Server 的验证码 处理请求时使用 GetContextVerifyCodeManager
+func GetVerifyCodeManager() *VerifyCodeManager {
+	if defaultDeps != nil {
+		return defaultDeps.verifyCodeManager
+	}
+	if verifyCodeManager == nil {
+		verifyCodeManager = newVerifyCodeManager(GetConfig(), getRedisClient(), GetNotifier())
+	}
+	return verifyCodeManager
+}
+
+func newVerifyCodeManager(cfg *Config, client *redis.Client, notifier Notifier) *VerifyCodeManager {
+	return &VerifyCodeManager{
+		codes:     NewRedisCache[string](client, "sys:verify:code:"),
+		attempts:  NewRedisCache[int64](client, "sys:verify:attempt:"),
+		intervals: NewRedisCache[int64](client, "sys:verify:interval:"),
+		config:    cfg,
+		notifier:  notifier,
+	}
+}
+
+// VerifyCodeManager 邮件和短信验证码 保存在 Redis 中 验证成功或者错误次数过多之后失效
+type VerifyCodeManager struct {
+	codes     *RedisCache[string]
+	attempts  *RedisCache[int64]
+	intervals *RedisCache[int64]
+	config    *Config
+	notifier  Notifier
+}
+
+// Send 生成验证码并通过 Notifier 发送 scene 区分用途 key 是验证码归属 例如用户ID
+// message.Content 中的 {code} 替换为验证码 {expire} 替换为有效期(分钟) 模板参数中也会带上 code 和 expire
+func (m VerifyCodeManager) Send(c context.Context, scene, key string, message NotifyMessage) error {
+	config := verifyCodeConfig(m.config.Notify.VerifyCode)
+	id := scene + ":" + key
+	// 占住发送间隔 并发请求只有一个能发送
+	if !m.intervals.SetNX(ctx, m.intervals.key+id, 1, time.Duration(config.Interval)*time.Second).Val() {
+		return NewErrCode(VERIFY_CODE_SEND_FREQUENT_ERROR)
+	}
+	code, err := randomDigits(config.Length)
+	if err != nil {
+		m.intervals.XCodeDel(id)
+		return err
+	}
+	m.attempts.XCodeDel(id)
+	if !m.codes.XSetCodeEX(id, code, time.Duration(config.Expire)*time.Second) {
+		m.intervals.XCodeDel(id)
+		return NewErrCode(SERVER_COMMON_ERROR)
+	}
+	expire := strconv.FormatInt(max(config.Expire/60, 1), 10)
+	message.Content = strings.NewReplacer("{code}", code, "{expire}", expire).Replace(message.Content)
+	params := map[string]string{"code": code, "expire": expire}
+	for name, value := range message.Params {
+		params[name] = value
+	}
+	message.Params = params
+	if err = m.notifier.Send(c, message); err != nil {
+		zap.L().Error("send verify code failed", zap.String("scene", scene), zap.String("channel", message.Channel), zap.Error(err))
+		m.codes.XCodeDel(id)
+		m.intervals.XCodeDel(id)
+		return NewErrCode(VERIFY_CODE_SEND_ERROR)
+	}
+	return nil
+}
+
+// Verify 校验验证码 验证成功后立即失效 错误次数达到 MaxAttempts 之后验证码作废
+func (m VerifyCodeManager) Verify(scene, key, code string) error {
+	config := verifyCodeConfig(m.config.Notify.VerifyCode)
+	id := scene + ":" + key
+	have, expect := m.codes.XCodeGet(id)
+	if !have {
+		return NewErrCode(VERIFY_CODE_EXPIRED_ERROR)
+	}
+	// 先计数再比较 并发提交时也不会超过次数限制
+	attempts, err := m.attempts.Incr(ctx, m.attempts.key+id).Result()
+	if err != nil {
+		return err
+	}
+	if attempts == 1 {
+		m.attempts.Expire(ctx, m.attempts.key+id, time.Duration(config.Expire)*time.Second)
+	}
+	if attempts > config.MaxAttempts {
+		m.codes.XCodeDel(id)
+		return NewErrCode(VERIFY_CODE_ATTEMPT_ERROR)
+	}
+	if subtle.ConstantTimeCompare([]byte(expect), []byte(strings.TrimSpace(code))) != 1 {
+		if attempts == config.MaxAttempts {
+			m.codes.XCodeDel(id)
+			return NewErrCode(VERIFY_CODE_ATTEMPT_ERROR)
+		}
+		return NewErrCode(VERIFY_CODE_ERROR)
+	}
+	m.attempts.XCodeDel(id)
+	// 同一个验证码只能使用一次
+	if !m.codes.XCodeDel(id) {
+		return NewErrCode(VERIFY_CODE_EXPIRED_ERROR)
+	}
+	return nil
+}
+
+func verifyCodeConfig(config VerifyCodeConfig) VerifyCodeConfig {
+	if config.Length <= 0 {
+		config.Length = defaultVerifyCodeLength
+	}
+	if config.Expire <= 0 {
+		config.Expire = defaultVerifyCodeExpire
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaultVerifyCodeInterval
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultVerifyCodeMaxAttempts
+	}
+	return config
+}
+
+// randomDigits 密码学安全的数字验证码
+func randomDigits(length int) (string, error) {
+	buf := make([]byte, length)
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		buf[i] = byte('0' + n.Int64())
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+	"reflect"
+)
+
+// versionField 模型上的乐观锁版本号字段 没有时返回 nil
+func (r *Gorm[M, V]) versionField() *schema.Field {
+	if r.config.VersionField == "" {
+		return nil
+	}
+	modelSchema, err := statementSchema(r.GetModelDb())
+	if err != nil {
+		return nil
+	}
+	field := modelSchema.LookUpField(r.config.VersionField)
+	if field == nil || field.DBName == "" {
+		return nil
+	}
+	return field
+}
+
+// withVersion 更新条件加上版本号 同时实体的版本号加一
+// 版本号从 1 开始 实体上没有带版本号时拒绝更新
+func (r *Gorm[M, V]) withVersion(db *gorm.DB, entity *M) (*gorm.DB, bool, error) {
+	field := r.versionField()
+	if field == nil {
+		return db, false, nil
+	}
+	row := reflect.ValueOf(entity).Elem()
+	value, _ := field.ValueOf(db.Statement.Context, row)
+	current := reflect.ValueOf(value)
+	if !current.CanInt() {
+		return db, false, errors.Errorf("version field %s must be an integer", field.Name)
+	}
+	version := current.Int()
+	if version == 0 {
+		return db, false, NewErrCode(CURD_VERSION_REQUIRED_ERROR)
+	}
+	if err := field.Set(db.Statement.Context, row, version+1); err != nil {
+		return db, false, err
+	}
+	return db.Where(fmt.Sprintf("%s = ?", field.DBName), version), true, nil
+}
+
+// checkVersionConflict 没有更新到数据但是数据存在 说明版本号已经被别人修改
+func (r *Gorm[M, V]) checkVersionConflict(id int64, affected int64) error {
+	if affected > 0 {
+		return nil
+	}
+	exist := r.Exist(func(db *gorm.DB) *gorm.DB {
+		return db.Where(fmt.Sprintf("%s = ?", r.config.PrimaryKeyField), id)
+	})
+	if exist {
+		return NewErrCode(CURD_VERSION_CONFLICT_ERROR)
+	}
+	return nil
+}
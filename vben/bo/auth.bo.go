@@ -1,8 +1,21 @@
 package bo
 
 type LoginBo struct {
-	Username string `validate:"required" zh_comment:"账号" json:"username" query:"username"` // 账号
-	Password string `validate:"required" zh_comment:"密码" json:"password" query:"password"` // 密码
+	Username    string `validate:"required" zh_comment:"账号" json:"username" query:"username"` // 账号
+	Password    string `validate:"required" zh_comment:"密码" json:"password" query:"password"` // 密码
+	CaptchaId   string `json:"captchaId" query:"captchaId"`                                   // 验证码ID 需要验证码时必填
+	CaptchaCode string `json:"captchaCode" query:"captchaCode"`                               // 验证码
+	Provider    string `json:"provider" query:"provider"`                                     // 登录方式 默认 local 启用 LDAP 时可以使用 Ldap
+}
+type RefreshTokenBo struct {
+	RefreshToken string `validate:"required" zh_comment:"刷新令牌" json:"refreshToken" query:"refreshToken"` // 刷新令牌
+}
+type TwoFactorLoginBo struct {
+	ChallengeToken string `validate:"required" zh_comment:"验证令牌" json:"challengeToken" query:"challengeToken"` // 密码登录返回的验证令牌
+	Code           string `validate:"required" zh_comment:"验证码" json:"code" query:"code"`                      // 认证器上的验证码或者恢复码
+}
+type TotpCodeBo struct {
+	Code string `validate:"required" zh_comment:"验证码" json:"code" query:"code"` // 认证器上的验证码或者恢复码
 }
 type UpdateUserInfoBo struct {
 	NickName string `gorm:"column:nick_name;type:varchar(255);comment:昵称" json:"nickName"` // 昵称
@@ -10,9 +23,18 @@ type UpdateUserInfoBo struct {
 }
 type ChangePasswordBo struct {
 	OldPassword string `validate:"required,min=5" zh_comment:"旧密码" json:"oldPassword" query:"oldPassword"` // 旧密码
-	NewPassword string `validate:"required,min=5" zh_comment:"新密码" json:"newPassword" query:"newPassword"`
+	NewPassword string `validate:"required,min=5,password" zh_comment:"新密码" json:"newPassword" query:"newPassword"`
 }
 type ChangePasswordByCodeBo struct {
 	ChangePasswordBo
 	Code string `validate:"required" zh_comment:"验证码" json:"code" query:"code"`
 }
+type ForgotPasswordBo struct {
+	Username string `validate:"required" zh_comment:"账号" json:"username" query:"username"`                 // 账号
+	Channel  string `validate:"required,oneof=email sms" zh_comment:"接收方式" json:"channel" query:"channel"` // 接收方式 email 邮箱 sms 短信
+}
+type ForgotPasswordResetBo struct {
+	Username    string `validate:"required" zh_comment:"账号" json:"username" query:"username"`                       // 账号
+	Code        string `validate:"required" zh_comment:"验证码" json:"code" query:"code"`                              // 邮件或者短信收到的验证码
+	NewPassword string `validate:"required,min=5,password" zh_comment:"新密码" json:"newPassword" query:"newPassword"` // 新密码
+}
@@ -11,10 +11,12 @@ type SysDictBo struct {
 	Name         string `json:"name"`         // 字典名称
 	Describe     string `json:"describe"`     // 字典描述
 	EnableStatus int64  `json:"enableStatus"` // 字典状态
+	Version      int64  `json:"version"`      // 版本号 修改时传入查询到的版本号
 }
 type SysDictPageBo struct {
-	Module int64 `query:"module"` // 所属模块
+	Module int64 `query:"module" filter:"eq"` // 所属模块
 	core.PageParam
+	core.OrderParam
 }
 
 type SysDictChildBo struct {
@@ -3,8 +3,8 @@ package bo
 import "github.com/super-sunshines/echo-server-core/core"
 
 type SysLogOperatePageBo struct {
-	core.PageParam
+	core.PageCursorParam
 }
 type SysLogLoginPageBo struct {
-	core.PageParam
+	core.PageCursorParam
 }
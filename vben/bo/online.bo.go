@@ -0,0 +1,9 @@
+package bo
+
+import "github.com/super-sunshines/echo-server-core/core"
+
+type SysOnlinePageBo struct {
+	core.PageParam
+	Username string `json:"username" query:"username" zh_comment:"账号"` // 账号 模糊查询
+	Platform string `json:"platform" query:"platform" zh_comment:"平台"` // 平台
+}
@@ -0,0 +1,7 @@
+package bo
+
+import "github.com/super-sunshines/echo-server-core/core"
+
+type SysRecyclePageBo struct {
+	core.PageParam
+}
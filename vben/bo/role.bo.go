@@ -4,12 +4,13 @@ import "github.com/super-sunshines/echo-server-core/core"
 
 type SysRolePageBo struct {
 	core.PageParam
-	ID           int64             `json:"id"`           // 主键
-	Code         string            `json:"code"`         // 权限代码
-	Description  string            `json:"description"`  // 权限描述
-	HomePath     string            ` json:"homePath"`    // 主页目录
-	MenuIDList   core.Array[int64] `json:"menuIdList"`   // 目录列表
-	EnableStatus core.IntBool      `json:"enableStatus"` // 启用状态
+	ID           int64             `json:"id"`                                            // 主键
+	Code         string            `json:"code" query:"code" filter:"like"`               // 权限代码
+	Description  string            `json:"description" query:"description" filter:"like"` // 权限描述
+	HomePath     string            ` json:"homePath"`                                     // 主页目录
+	MenuIDList   core.Array[int64] `json:"menuIdList"`                                    // 目录列表
+	EnableStatus *core.IntBool     `json:"enableStatus" query:"enableStatus" filter:"eq"` // 启用状态 不传时查询全部
+	core.OrderParam
 }
 type SysRoleBo struct {
 	ID             int64             `json:"id"`             // 主键
@@ -21,4 +22,5 @@ type SysRoleBo struct {
 	HomePath       string            `json:"homePath"`       // 主页目录
 	MenuIDList     core.Array[int64] `json:"menuIdList"`     // 目录列表
 	EnableStatus   core.IntBool      `json:"enableStatus"`   // 启用状态
+	Version        int64             `json:"version"`        // 版本号 修改时传入查询到的版本号
 }
@@ -0,0 +1,17 @@
+package bo
+
+type ThirdBindBo struct {
+	Provider string `validate:"required" zh_comment:"登录方式" json:"provider" query:"provider"` // 登录方式 WorkWeChat WeChat WeChatApp Ldap 或者 OIDC 的名称
+	Code     string `json:"code" query:"code"`                                               // 授权码类的登录方式回调带上的 code
+	State    string `json:"state" query:"state"`                                             // OIDC 回调带上的 state
+	Username string `json:"username" query:"username"`                                       // 账号密码类的登录方式使用 例如 Ldap
+	Password string `json:"password" query:"password"`                                       // 三方账号的密码
+}
+type ThirdUnbindBo struct {
+	Platform string `validate:"required" zh_comment:"三方平台" json:"platform" query:"platform"` // 三方平台 对应绑定列表中的 loginType
+	Password string `json:"password" query:"password"`                                       // 登录密码 解绑最后一个三方账号时必填
+}
+type SysUserMergeBo struct {
+	SourceId int64 `validate:"required" zh_comment:"重复用户" json:"sourceId" query:"sourceId"` // 重复的用户 合并之后删除
+	TargetId int64 `validate:"required" zh_comment:"目标用户" json:"targetId" query:"targetId"` // 保留的用户
+}
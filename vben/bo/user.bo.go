@@ -9,8 +9,9 @@ type UserInfoBo struct {
 
 type SysUserPageBo struct {
 	DepartmentId int64  `query:"departmentId"`
-	SearchKey    string `query:"searchKey"`
+	SearchKey    string `query:"searchKey" filter:"like,column=real_name|nick_name"`
 	core.PageParam
+	core.OrderParam
 }
 
 type SysUserBo struct {
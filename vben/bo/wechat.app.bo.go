@@ -7,3 +7,12 @@ type WechatAppServerResp struct {
 	Errcode    int    `json:"errcode"`
 	Errmsg     string `json:"errmsg"`
 }
+
+type WechatOauth2Resp struct {
+	AccessToken string `json:"access_token"`
+	Openid      string `json:"openid"`
+	Unionid     string `json:"unionid"`
+	Scope       string `json:"scope"`
+	Errcode     int    `json:"errcode"`
+	Errmsg      string `json:"errmsg"`
+}
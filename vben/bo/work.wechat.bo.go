@@ -4,3 +4,7 @@ type BindRealNameBo struct {
 	QywxUid  string `json:"qywxUid" zh_comment:"企业微信UID" en_comment:"uid" validate:"required"`
 	RealName string `json:"realName" zh_comment:"真实姓名" en_comment:"real name" validate:"required"`
 }
+
+type WorkWechatSyncBo struct {
+	DryRun bool `json:"dryRun"` // 只预览变化 不写入数据库
+}
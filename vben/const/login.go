@@ -5,4 +5,12 @@ type LoginType int64
 const (
 	LoginTypePassword LoginType = 1
 	LoginTypeQywx     LoginType = 2
+	// LoginTypeTwoFactor 密码登录之后的两步验证
+	LoginTypeTwoFactor LoginType = 3
+	// LoginTypeLdap LDAP 账号密码登录
+	LoginTypeLdap LoginType = 4
+	// LoginTypeOidc OAuth2/OIDC 登录
+	LoginTypeOidc LoginType = 5
+	// LoginTypeWechatApp 微信小程序登录
+	LoginTypeWechatApp LoginType = 6
 )
@@ -18,6 +18,11 @@ var GeneratePermissionCodes = []string{
 	"SYS::MENU::ADD",
 	"SYS::MENU::DEL",
 	"SYS::MENU::CODE::ADD",
+	"SYS::ONLINE::QUERY",
+	"SYS::ONLINE::KICK",
+	"SYS::RECYCLE::QUERY",
+	"SYS::RECYCLE::RESTORE",
+	"SYS::RECYCLE::PURGE",
 	"SYS::ROLE::QUERY",
 	"SYS::ROLE::ADD",
 	"SYS::ROLE::UPDATE",
@@ -29,4 +34,8 @@ var GeneratePermissionCodes = []string{
 	"SYS::USER::DEL",
 	"SYS::USER::UNLOCK",
 	"SYS::USER::LOCK",
+	"SYS::USER::KICK",
+	"SYS::USER::UNBIND",
+	"SYS::USER::MERGE",
+	"SYS::DEPART::SYNC",
 }
@@ -4,4 +4,5 @@ const (
 	ThirdPlatformWorkWeChat = "WorkWeChat"
 	ThirdPlatformWeChat     = "WeChat"
 	ThirdPlatformWeChatApp  = "WeChatApp"
+	ThirdPlatformLdap       = "Ldap"
 )
@@ -6,10 +6,77 @@ const (
 	TencentWorkWeChatNewUserEventBusKey = "TencentWorkWeChatNewUserEventBusKey"
 )
 
+// 企业微信回调的事件 在回调接口中同步发布 耗时的处理请使用 SubscribeAsync 订阅
+// 企业微信 5 秒内收不到响应会重试 订阅者需要能处理重复的事件
+const (
+	// TencentWorkWeChatCallbackEventBusKey 所有的回调消息 包括下面没有单独定义的事件 数据是 TencentWorkWeChatCallbackEventBusData
+	TencentWorkWeChatCallbackEventBusKey = "TencentWorkWeChatCallbackEventBusKey"
+	// 成员变更 数据是 TencentWorkWeChatUserEventBusData
+	TencentWorkWeChatCreateUserEventBusKey = "TencentWorkWeChatCreateUserEventBusKey"
+	TencentWorkWeChatUpdateUserEventBusKey = "TencentWorkWeChatUpdateUserEventBusKey"
+	TencentWorkWeChatDeleteUserEventBusKey = "TencentWorkWeChatDeleteUserEventBusKey"
+	// 部门变更 数据是 TencentWorkWeChatPartyEventBusData
+	TencentWorkWeChatCreatePartyEventBusKey = "TencentWorkWeChatCreatePartyEventBusKey"
+	TencentWorkWeChatUpdatePartyEventBusKey = "TencentWorkWeChatUpdatePartyEventBusKey"
+	TencentWorkWeChatDeletePartyEventBusKey = "TencentWorkWeChatDeletePartyEventBusKey"
+	// 审批申请状态变化 数据是 TencentWorkWeChatApprovalEventBusData
+	TencentWorkWeChatApprovalChangeEventBusKey = "TencentWorkWeChatApprovalChangeEventBusKey"
+)
+
 type TencentWorkWeChatNewUserEventBusData struct {
 	SysUid           int64
 	WorkWechatName   string
 	WorkWechatUserId string
 }
 
+// TencentWorkWeChatCallbackEventBusData 解密之后的回调消息
+type TencentWorkWeChatCallbackEventBusData struct {
+	ToUserName   string // 企业ID
+	FromUserName string // 成员UserID 通讯录事件是 sys
+	CreateTime   int64
+	MsgType      string // 消息类型 事件是 event
+	Event        string // 事件类型 例如 change_contact
+	ChangeType   string // 变更类型 例如 create_user
+	AgentID      int64
+	Raw          string // 解密之后的 XML 原文
+}
+
+// TencentWorkWeChatUserEventBusData 成员变更 删除成员时只有 UserID 更新成员时只有修改过的字段有值
+type TencentWorkWeChatUserEventBusData struct {
+	UserID         string
+	NewUserID      string // 更新成员时修改了 UserID
+	Name           string
+	Department     []int64
+	MainDepartment int64
+	Mobile         string
+	Email          string
+	Position       string
+	Gender         int // 1 男 2 女
+	Status         int // 1 已激活 2 已禁用 4 未激活 5 退出企业
+	Avatar         string
+	Alias          string
+	CreateTime     int64
+}
+
+// TencentWorkWeChatPartyEventBusData 部门变更 删除部门时只有 ID
+type TencentWorkWeChatPartyEventBusData struct {
+	ID         int64
+	Name       string
+	ParentID   int64
+	Order      int64
+	CreateTime int64
+}
+
+// TencentWorkWeChatApprovalEventBusData 审批申请状态变化
+type TencentWorkWeChatApprovalEventBusData struct {
+	SpNo              string // 审批编号
+	SpName            string // 审批模板名称
+	SpStatus          int    // 1 审批中 2 已通过 3 已驳回 4 已撤销 6 通过后撤销 7 已删除 10 已支付
+	TemplateID        string
+	ApplyTime         int64
+	ApplyerUserID     string // 提交人
+	StatusChangeEvent int    // 1 提单 2 同意 3 驳回 4 转审 5 催办 6 撤销 8 通过后撤销 10 添加备注
+	CreateTime        int64
+}
+
 var TencentWorkWeChatEventBus = EventBus.New()
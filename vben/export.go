@@ -2,7 +2,9 @@ package vben
 
 import (
 	"github.com/super-sunshines/echo-server-core/core"
+	"github.com/super-sunshines/echo-server-core/vben/migrations"
 	"github.com/super-sunshines/echo-server-core/vben/routers"
+	"github.com/super-sunshines/echo-server-core/vben/services"
 )
 
 var BaseRouters = []*core.RouterGroup{
@@ -13,7 +15,10 @@ var BaseRouters = []*core.RouterGroup{
 	routers.SysUserRouterGroup,
 	routers.SysRoleRouterGroup,
 	routers.SysDepartmentRouterGroup,
+	routers.SysRecycleRouterGroup,
+	routers.SysOnlineRouterGroup,
 	routers.SysFileRouterGroup,
+	routers.ThirdBindRouterGroup,
 }
 
 var TencentRouters = []*core.RouterGroup{
@@ -24,10 +29,30 @@ var TencentWorkWechatRouters = []*core.RouterGroup{
 	routers.WorkWechatRouterGroup,
 }
 
+// TencentWorkWechatCallbackRouters 企业微信回调 需要配置 Tencent.WorkWechat.Callback
+var TencentWorkWechatCallbackRouters = []*core.RouterGroup{
+	routers.WorkWechatCallbackRouterGroup,
+}
+
 var TencentWechatAppRouters = []*core.RouterGroup{
 	routers.WechatAppRouterGroup,
 }
 
+// OidcRouters OAuth2/OIDC 登录 需要配置 Oidc
+var OidcRouters = []*core.RouterGroup{
+	routers.OidcRouterGroup,
+}
+
+// LifecycleHooks vben 模块的生命周期钩子 需要放入 ServerRunOption.Hooks
+var LifecycleHooks = []core.LifecycleHook{
+	{Name: "TencentWorkWechat", OnStop: services.StopTencentWorkWeChatService},
+	{Name: "TencentWorkWechatSync", OnStart: services.StartWorkWeChatSync, OnStop: services.StopWorkWeChatSync},
+}
+
+// Migrations vben 模块的数据库迁移 放入 ServerRunOption.Migrations 启动时自动执行
+// 也可以使用 go run ./vben/gen/migrate up 手动执行
+var Migrations = migrations.Migrations
+
 func AddPermissionCodes(codes []string) {
 	routers.AddRoleCodes(codes)
 }
@@ -0,0 +1,32 @@
+package vben
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/super-sunshines/echo-server-core/core"
+	"github.com/super-sunshines/echo-server-core/core/coretest"
+)
+
+// 忘记密码 账号存在和不存在时返回相同的结果
+func TestForgotPasswordResetHidesAccounts(t *testing.T) {
+	cfg := coretest.NewConfig(t)
+	cfg.Password.MinLength = 12
+	server := newTestServer(t, cfg, "alice", "alice-password")
+
+	reset := func(username, password string) testResponse {
+		return callTestServer(t, server, http.MethodPost, "/auth/password/forgot/reset", "", map[string]string{
+			"username": username, "code": "123456", "newPassword": password,
+		})
+	}
+	// 包含用户名的密码不满足密码策略
+	known, unknown := reset("alice", "alice-long-password"), reset("nobody", "nobody-long-password")
+	if known.Code == core.OK || known.Code != unknown.Code {
+		t.Fatalf("password with username: known account got %+v, unknown account got %+v", known, unknown)
+	}
+	// 满足密码策略时验证码错误
+	known, unknown = reset("alice", "long-enough-password9"), reset("nobody", "long-enough-password9")
+	if known.Code == core.OK || known.Code != unknown.Code || known.Msg != unknown.Msg {
+		t.Fatalf("wrong code: known account got %+v, unknown account got %+v", known, unknown)
+	}
+}
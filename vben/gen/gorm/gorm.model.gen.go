@@ -7,6 +7,7 @@ import (
 	"gorm.io/gen"
 	"gorm.io/gen/field"
 	"gorm.io/gorm"
+	"regexp"
 	"strings"
 )
 
@@ -16,6 +17,7 @@ func main() {
 
 var genModels = []string{
 	"sys_department",
+	"sys_department_third_bind",
 	"sys_dict",
 	"sys_dict_child",
 	"sys_log_operate",
@@ -23,7 +25,9 @@ var genModels = []string{
 	"sys_menu",
 	"sys_menu_meta",
 	"sys_user_department",
+	"sys_user_password_history",
 	"sys_user_third_bind",
+	"sys_user_totp",
 	"sys_role",
 	"sys_user",
 }
@@ -51,8 +55,16 @@ var specialModelTagGenConfig = map[string][]gen.ModelOpt{
 		gen.FieldType("role_code_list", "core.Array[string]"),
 		gen.FieldType("need_change_password", "core.IntBool"),
 	},
+
+	"sys_user_totp": {
+		gen.FieldType("enabled", "core.IntBool"),
+		gen.FieldType("recovery_codes", "core.Array[string]"),
+	},
 }
 
+// MySQL 专有的列类型 生成时去掉 保证模型可以在 postgres/sqlite 下使用
+var mysqlOnlyTypeReg = regexp.MustCompile(`^(?i)((tinyint|smallint|mediumint|int|bigint)(\(\d+\))?( unsigned)?|datetime|json)$`)
+
 // 通用配置生成
 var allModelTagGenConfig = []gen.ModelOpt{
 	gen.FieldGORMTag("create_time", func(tag field.GormTag) field.GormTag {
@@ -63,6 +75,13 @@ var allModelTagGenConfig = []gen.ModelOpt{
 		tag.Set("column", "update_time;autoUpdateTime")
 		return tag
 	}),
+	// 去掉 MySQL 专有的列类型 由 gorm 和 core 类型的 GormDBDataType 按方言决定
+	gen.FieldGORMTagReg(".*", func(tag field.GormTag) field.GormTag {
+		if types, ok := tag[field.TagKeyGormType]; ok && len(types) > 0 && mysqlOnlyTypeReg.MatchString(types[0]) {
+			tag.Remove(field.TagKeyGormType)
+		}
+		return tag
+	}),
 	gen.FieldType("delete_time", "gorm.DeletedAt"),
 	gen.FieldType("create_time", "core.Time"),
 	gen.FieldType("update_time", "core.Time"),
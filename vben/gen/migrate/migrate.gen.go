@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"github.com/super-sunshines/echo-server-core/core"
+	"github.com/super-sunshines/echo-server-core/vben/migrations"
+	"log"
+	"os"
+	"strconv"
+)
+
+// 数据库迁移命令 读取当前目录的 application.yaml
+// go run ./vben/gen/migrate up        执行全部未执行的迁移
+// go run ./vben/gen/migrate down [n]  回滚最近 n 个迁移 默认 1
+// go run ./vben/gen/migrate status    查看迁移状态
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println("usage: migrate up | down [n] | status")
+		os.Exit(2)
+	}
+	core.InitConfig()
+	migrator := core.NewMigrator(core.GetGormDB(), migrations.Migrations...)
+	switch os.Args[1] {
+	case "up":
+		if err := migrator.Up(); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	case "down":
+		steps := 1
+		if len(os.Args) > 2 {
+			n, err := strconv.Atoi(os.Args[2])
+			if err != nil || n < 1 {
+				log.Fatalf("Error: invalid steps %q", os.Args[2])
+			}
+			steps = n
+		}
+		if err := migrator.Down(steps); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	case "status":
+		status, err := migrator.Status()
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		for _, item := range status {
+			applied := "pending"
+			if item.Applied {
+				applied = item.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%s  %-20s  %s\n", item.Version, applied, item.Description)
+		}
+	default:
+		fmt.Println("usage: migrate up | down [n] | status")
+		os.Exit(2)
+	}
+}
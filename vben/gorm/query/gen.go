@@ -16,23 +16,27 @@ import (
 )
 
 var (
-	Q                 = new(Query)
-	SysDepartment     *sysDepartment
-	SysDict           *sysDict
-	SysDictChild      *sysDictChild
-	SysLogLogin       *sysLogLogin
-	SysLogOperate     *sysLogOperate
-	SysMenu           *sysMenu
-	SysMenuMetum      *sysMenuMetum
-	SysRole           *sysRole
-	SysUser           *sysUser
-	SysUserDepartment *sysUserDepartment
-	SysUserThirdBind  *sysUserThirdBind
+	Q                      = new(Query)
+	SysDepartment          *sysDepartment
+	SysDepartmentThirdBind *sysDepartmentThirdBind
+	SysDict                *sysDict
+	SysDictChild           *sysDictChild
+	SysLogLogin            *sysLogLogin
+	SysLogOperate          *sysLogOperate
+	SysMenu                *sysMenu
+	SysMenuMetum           *sysMenuMetum
+	SysRole                *sysRole
+	SysUser                *sysUser
+	SysUserDepartment      *sysUserDepartment
+	SysUserPasswordHistory *sysUserPasswordHistory
+	SysUserThirdBind       *sysUserThirdBind
+	SysUserTotp            *sysUserTotp
 )
 
 func SetDefault(db *gorm.DB, opts ...gen.DOOption) {
 	*Q = *Use(db, opts...)
 	SysDepartment = &Q.SysDepartment
+	SysDepartmentThirdBind = &Q.SysDepartmentThirdBind
 	SysDict = &Q.SysDict
 	SysDictChild = &Q.SysDictChild
 	SysLogLogin = &Q.SysLogLogin
@@ -42,58 +46,69 @@ func SetDefault(db *gorm.DB, opts ...gen.DOOption) {
 	SysRole = &Q.SysRole
 	SysUser = &Q.SysUser
 	SysUserDepartment = &Q.SysUserDepartment
+	SysUserPasswordHistory = &Q.SysUserPasswordHistory
 	SysUserThirdBind = &Q.SysUserThirdBind
+	SysUserTotp = &Q.SysUserTotp
 }
 
 func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 	return &Query{
-		db:                db,
-		SysDepartment:     newSysDepartment(db, opts...),
-		SysDict:           newSysDict(db, opts...),
-		SysDictChild:      newSysDictChild(db, opts...),
-		SysLogLogin:       newSysLogLogin(db, opts...),
-		SysLogOperate:     newSysLogOperate(db, opts...),
-		SysMenu:           newSysMenu(db, opts...),
-		SysMenuMetum:      newSysMenuMetum(db, opts...),
-		SysRole:           newSysRole(db, opts...),
-		SysUser:           newSysUser(db, opts...),
-		SysUserDepartment: newSysUserDepartment(db, opts...),
-		SysUserThirdBind:  newSysUserThirdBind(db, opts...),
+		db:                     db,
+		SysDepartment:          newSysDepartment(db, opts...),
+		SysDepartmentThirdBind: newSysDepartmentThirdBind(db, opts...),
+		SysDict:                newSysDict(db, opts...),
+		SysDictChild:           newSysDictChild(db, opts...),
+		SysLogLogin:            newSysLogLogin(db, opts...),
+		SysLogOperate:          newSysLogOperate(db, opts...),
+		SysMenu:                newSysMenu(db, opts...),
+		SysMenuMetum:           newSysMenuMetum(db, opts...),
+		SysRole:                newSysRole(db, opts...),
+		SysUser:                newSysUser(db, opts...),
+		SysUserDepartment:      newSysUserDepartment(db, opts...),
+		SysUserPasswordHistory: newSysUserPasswordHistory(db, opts...),
+		SysUserThirdBind:       newSysUserThirdBind(db, opts...),
+		SysUserTotp:            newSysUserTotp(db, opts...),
 	}
 }
 
 type Query struct {
 	db *gorm.DB
 
-	SysDepartment     sysDepartment
-	SysDict           sysDict
-	SysDictChild      sysDictChild
-	SysLogLogin       sysLogLogin
-	SysLogOperate     sysLogOperate
-	SysMenu           sysMenu
-	SysMenuMetum      sysMenuMetum
-	SysRole           sysRole
-	SysUser           sysUser
-	SysUserDepartment sysUserDepartment
-	SysUserThirdBind  sysUserThirdBind
+	SysDepartment          sysDepartment
+	SysDepartmentThirdBind sysDepartmentThirdBind
+	SysDict                sysDict
+	SysDictChild           sysDictChild
+	SysLogLogin            sysLogLogin
+	SysLogOperate          sysLogOperate
+	SysMenu                sysMenu
+	SysMenuMetum           sysMenuMetum
+	SysRole                sysRole
+	SysUser                sysUser
+	SysUserDepartment      sysUserDepartment
+	SysUserPasswordHistory sysUserPasswordHistory
+	SysUserThirdBind       sysUserThirdBind
+	SysUserTotp            sysUserTotp
 }
 
 func (q *Query) Available() bool { return q.db != nil }
 
 func (q *Query) clone(db *gorm.DB) *Query {
 	return &Query{
-		db:                db,
-		SysDepartment:     q.SysDepartment.clone(db),
-		SysDict:           q.SysDict.clone(db),
-		SysDictChild:      q.SysDictChild.clone(db),
-		SysLogLogin:       q.SysLogLogin.clone(db),
-		SysLogOperate:     q.SysLogOperate.clone(db),
-		SysMenu:           q.SysMenu.clone(db),
-		SysMenuMetum:      q.SysMenuMetum.clone(db),
-		SysRole:           q.SysRole.clone(db),
-		SysUser:           q.SysUser.clone(db),
-		SysUserDepartment: q.SysUserDepartment.clone(db),
-		SysUserThirdBind:  q.SysUserThirdBind.clone(db),
+		db:                     db,
+		SysDepartment:          q.SysDepartment.clone(db),
+		SysDepartmentThirdBind: q.SysDepartmentThirdBind.clone(db),
+		SysDict:                q.SysDict.clone(db),
+		SysDictChild:           q.SysDictChild.clone(db),
+		SysLogLogin:            q.SysLogLogin.clone(db),
+		SysLogOperate:          q.SysLogOperate.clone(db),
+		SysMenu:                q.SysMenu.clone(db),
+		SysMenuMetum:           q.SysMenuMetum.clone(db),
+		SysRole:                q.SysRole.clone(db),
+		SysUser:                q.SysUser.clone(db),
+		SysUserDepartment:      q.SysUserDepartment.clone(db),
+		SysUserPasswordHistory: q.SysUserPasswordHistory.clone(db),
+		SysUserThirdBind:       q.SysUserThirdBind.clone(db),
+		SysUserTotp:            q.SysUserTotp.clone(db),
 	}
 }
 
@@ -107,48 +122,57 @@ func (q *Query) WriteDB() *Query {
 
 func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 	return &Query{
-		db:                db,
-		SysDepartment:     q.SysDepartment.replaceDB(db),
-		SysDict:           q.SysDict.replaceDB(db),
-		SysDictChild:      q.SysDictChild.replaceDB(db),
-		SysLogLogin:       q.SysLogLogin.replaceDB(db),
-		SysLogOperate:     q.SysLogOperate.replaceDB(db),
-		SysMenu:           q.SysMenu.replaceDB(db),
-		SysMenuMetum:      q.SysMenuMetum.replaceDB(db),
-		SysRole:           q.SysRole.replaceDB(db),
-		SysUser:           q.SysUser.replaceDB(db),
-		SysUserDepartment: q.SysUserDepartment.replaceDB(db),
-		SysUserThirdBind:  q.SysUserThirdBind.replaceDB(db),
+		db:                     db,
+		SysDepartment:          q.SysDepartment.replaceDB(db),
+		SysDepartmentThirdBind: q.SysDepartmentThirdBind.replaceDB(db),
+		SysDict:                q.SysDict.replaceDB(db),
+		SysDictChild:           q.SysDictChild.replaceDB(db),
+		SysLogLogin:            q.SysLogLogin.replaceDB(db),
+		SysLogOperate:          q.SysLogOperate.replaceDB(db),
+		SysMenu:                q.SysMenu.replaceDB(db),
+		SysMenuMetum:           q.SysMenuMetum.replaceDB(db),
+		SysRole:                q.SysRole.replaceDB(db),
+		SysUser:                q.SysUser.replaceDB(db),
+		SysUserDepartment:      q.SysUserDepartment.replaceDB(db),
+		SysUserPasswordHistory: q.SysUserPasswordHistory.replaceDB(db),
+		SysUserThirdBind:       q.SysUserThirdBind.replaceDB(db),
+		SysUserTotp:            q.SysUserTotp.replaceDB(db),
 	}
 }
 
 type queryCtx struct {
-	SysDepartment     ISysDepartmentDo
-	SysDict           ISysDictDo
-	SysDictChild      ISysDictChildDo
-	SysLogLogin       ISysLogLoginDo
-	SysLogOperate     ISysLogOperateDo
-	SysMenu           ISysMenuDo
-	SysMenuMetum      ISysMenuMetumDo
-	SysRole           ISysRoleDo
-	SysUser           ISysUserDo
-	SysUserDepartment ISysUserDepartmentDo
-	SysUserThirdBind  ISysUserThirdBindDo
+	SysDepartment          ISysDepartmentDo
+	SysDepartmentThirdBind ISysDepartmentThirdBindDo
+	SysDict                ISysDictDo
+	SysDictChild           ISysDictChildDo
+	SysLogLogin            ISysLogLoginDo
+	SysLogOperate          ISysLogOperateDo
+	SysMenu                ISysMenuDo
+	SysMenuMetum           ISysMenuMetumDo
+	SysRole                ISysRoleDo
+	SysUser                ISysUserDo
+	SysUserDepartment      ISysUserDepartmentDo
+	SysUserPasswordHistory ISysUserPasswordHistoryDo
+	SysUserThirdBind       ISysUserThirdBindDo
+	SysUserTotp            ISysUserTotpDo
 }
 
 func (q *Query) WithContext(ctx context.Context) *queryCtx {
 	return &queryCtx{
-		SysDepartment:     q.SysDepartment.WithContext(ctx),
-		SysDict:           q.SysDict.WithContext(ctx),
-		SysDictChild:      q.SysDictChild.WithContext(ctx),
-		SysLogLogin:       q.SysLogLogin.WithContext(ctx),
-		SysLogOperate:     q.SysLogOperate.WithContext(ctx),
-		SysMenu:           q.SysMenu.WithContext(ctx),
-		SysMenuMetum:      q.SysMenuMetum.WithContext(ctx),
-		SysRole:           q.SysRole.WithContext(ctx),
-		SysUser:           q.SysUser.WithContext(ctx),
-		SysUserDepartment: q.SysUserDepartment.WithContext(ctx),
-		SysUserThirdBind:  q.SysUserThirdBind.WithContext(ctx),
+		SysDepartment:          q.SysDepartment.WithContext(ctx),
+		SysDepartmentThirdBind: q.SysDepartmentThirdBind.WithContext(ctx),
+		SysDict:                q.SysDict.WithContext(ctx),
+		SysDictChild:           q.SysDictChild.WithContext(ctx),
+		SysLogLogin:            q.SysLogLogin.WithContext(ctx),
+		SysLogOperate:          q.SysLogOperate.WithContext(ctx),
+		SysMenu:                q.SysMenu.WithContext(ctx),
+		SysMenuMetum:           q.SysMenuMetum.WithContext(ctx),
+		SysRole:                q.SysRole.WithContext(ctx),
+		SysUser:                q.SysUser.WithContext(ctx),
+		SysUserDepartment:      q.SysUserDepartment.WithContext(ctx),
+		SysUserPasswordHistory: q.SysUserPasswordHistory.WithContext(ctx),
+		SysUserThirdBind:       q.SysUserThirdBind.WithContext(ctx),
+		SysUserTotp:            q.SysUserTotp.WithContext(ctx),
 	}
 }
 
@@ -41,6 +41,7 @@ func newSysDict(db *gorm.DB, opts ...gen.DOOption) sysDict {
 	_sysDict.UpdateBy = field.NewInt64(tableName, "update_by")
 	_sysDict.UpdateTime = field.NewField(tableName, "update_time")
 	_sysDict.DeleteTime = field.NewField(tableName, "delete_time")
+	_sysDict.Version = field.NewInt64(tableName, "version")
 
 	_sysDict.fillFieldMap()
 
@@ -65,6 +66,7 @@ type sysDict struct {
 	UpdateBy     field.Int64  // 更新者
 	UpdateTime   field.Field  // 更新时间
 	DeleteTime   field.Field  // 删除时间
+	Version      field.Int64  // 版本号
 
 	fieldMap map[string]field.Expr
 }
@@ -95,6 +97,7 @@ func (s *sysDict) updateTableName(table string) *sysDict {
 	s.UpdateBy = field.NewInt64(table, "update_by")
 	s.UpdateTime = field.NewField(table, "update_time")
 	s.DeleteTime = field.NewField(table, "delete_time")
+	s.Version = field.NewInt64(table, "version")
 
 	s.fillFieldMap()
 
@@ -111,7 +114,7 @@ func (s *sysDict) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (s *sysDict) fillFieldMap() {
-	s.fieldMap = make(map[string]field.Expr, 14)
+	s.fieldMap = make(map[string]field.Expr, 15)
 	s.fieldMap["id"] = s.ID
 	s.fieldMap["module"] = s.Module
 	s.fieldMap["code"] = s.Code
@@ -126,6 +129,7 @@ func (s *sysDict) fillFieldMap() {
 	s.fieldMap["update_by"] = s.UpdateBy
 	s.fieldMap["update_time"] = s.UpdateTime
 	s.fieldMap["delete_time"] = s.DeleteTime
+	s.fieldMap["version"] = s.Version
 }
 
 func (s sysDict) clone(db *gorm.DB) sysDict {
@@ -42,6 +42,7 @@ func newSysRole(db *gorm.DB, opts ...gen.DOOption) sysRole {
 	_sysRole.UpdateBy = field.NewInt64(tableName, "update_by")
 	_sysRole.UpdateTime = field.NewField(tableName, "update_time")
 	_sysRole.DeleteTime = field.NewField(tableName, "delete_time")
+	_sysRole.Version = field.NewInt64(tableName, "version")
 
 	_sysRole.fillFieldMap()
 
@@ -67,6 +68,7 @@ type sysRole struct {
 	UpdateBy       field.Int64  // 更新者
 	UpdateTime     field.Field  // 更新时间
 	DeleteTime     field.Field  // 删除时间
+	Version        field.Int64  // 版本号
 
 	fieldMap map[string]field.Expr
 }
@@ -98,6 +100,7 @@ func (s *sysRole) updateTableName(table string) *sysRole {
 	s.UpdateBy = field.NewInt64(table, "update_by")
 	s.UpdateTime = field.NewField(table, "update_time")
 	s.DeleteTime = field.NewField(table, "delete_time")
+	s.Version = field.NewInt64(table, "version")
 
 	s.fillFieldMap()
 
@@ -114,7 +117,7 @@ func (s *sysRole) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (s *sysRole) fillFieldMap() {
-	s.fieldMap = make(map[string]field.Expr, 15)
+	s.fieldMap = make(map[string]field.Expr, 16)
 	s.fieldMap["id"] = s.ID
 	s.fieldMap["code"] = s.Code
 	s.fieldMap["name"] = s.Name
@@ -130,6 +133,7 @@ func (s *sysRole) fillFieldMap() {
 	s.fieldMap["update_by"] = s.UpdateBy
 	s.fieldMap["update_time"] = s.UpdateTime
 	s.fieldMap["delete_time"] = s.DeleteTime
+	s.fieldMap["version"] = s.Version
 }
 
 func (s sysRole) clone(db *gorm.DB) sysRole {
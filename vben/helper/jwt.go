@@ -1,20 +1,26 @@
 package helper
 
 import (
+	"github.com/labstack/echo/v4"
 	"github.com/super-sunshines/echo-server-core/core"
 	"github.com/super-sunshines/echo-server-core/vben/gorm/model"
 )
 
-func GenJwtByUserInfo(platform string, a model.SysUser) (string, error) {
+// GenTokenPairByUserInfo 登录时生成访问令牌和刷新令牌 并记录登录设备
+func GenTokenPairByUserInfo(c echo.Context, platform string, a model.SysUser) (core.TokenPair, error) {
 	if platform == "" {
 		platform = "Unknown"
 	}
-	return core.GetTokenManager().GenJwtString(platform, core.ClaimsAdditions{
+	return core.GetContextTokenManager(c).GenTokenPair(platform, UserClaims(platform, a), core.NewSessionMeta(c))
+}
+
+func UserClaims(platform string, a model.SysUser) core.ClaimsAdditions {
+	return core.ClaimsAdditions{
 		UID:          a.ID,
 		NickName:     a.NickName,
 		Username:     a.Username,
 		DepartmentId: a.DepartmentID,
 		RoleCodes:    a.RoleCodeList,
 		Platform:     platform,
-	})
+	}
 }
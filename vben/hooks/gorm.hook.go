@@ -35,7 +35,9 @@ func queryStrategy(roles []model.SysRole, db *gorm.DB, context *core.XContext[an
 		break
 	case DepartmentBelow:
 		childrenIds, _ := departService.GetChildren(context, user.DepartmentId)
-		db.Where("create_dept IN (?)", childrenIds).Or("create_by = ?", user.UID)
+		// 加上括号 否则 OR 会绕过前面的查询条件
+		scope := db.Session(&gorm.Session{NewDB: true})
+		db.Where(scope.Where("create_dept IN (?)", childrenIds).Or("create_by = ?", user.UID))
 		break
 	}
 }
@@ -56,7 +58,9 @@ func updateStrategy(roles []model.SysRole, db *gorm.DB, context *core.XContext[a
 		break
 	case DepartmentBelow:
 		childrenIds, _ := departService.GetChildren(context, user.DepartmentId)
-		db.Where("create_dept IN (?)", childrenIds).Or("create_by = ?", user.UID)
+		// 加上括号 否则 OR 会绕过前面的查询条件
+		scope := db.Session(&gorm.Session{NewDB: true})
+		db.Where(scope.Where("create_dept IN (?)", childrenIds).Or("create_by = ?", user.UID))
 		break
 	}
 }
@@ -84,6 +88,8 @@ func GlobalGormHook(globalDb *gorm.DB) {
 			_ = context.Fail(err)
 			return
 		}
+		// 角色和部门缓存失效时会用 SkipGlobalHook 读取 它会让整个请求跳过钩子 读取之后恢复
+		defer context.Set(core.GormGlobalSkipHookKey, false)
 		updateStrategy(roleService.GetRoleConfigByCodes(context, user.RoleCodes...), _db, context, departService)
 		// 使用 Assign 方法确保多列都能设置
 		_db.Statement.Assign(map[string]interface{}{
@@ -148,6 +154,8 @@ func GlobalGormHook(globalDb *gorm.DB) {
 			_ = context.Fail(err)
 			return
 		}
+		// 角色和部门缓存失效时会用 SkipGlobalHook 读取 它会让整个请求跳过钩子 读取之后恢复
+		defer context.Set(core.GormGlobalSkipHookKey, false)
 		codes := roleService.GetRoleConfigByCodes(context, user.RoleCodes...)
 		queryStrategy(codes, _db, context, departService)
 	})
@@ -16,7 +16,7 @@ func LoggerMiddlewareHook(info core.RequestInfo, c echo.Context) {
 				fmt.Printf("LoggerMiddlewareHook:  %#v", r)
 			}
 		}()
-		logQuery := query.Use(core.GetGormDB()).SysLogOperate.WithContext(core.NewSkipGormGlobalHookContext())
+		logQuery := query.Use(core.GetAnyContext(c).GetDB()).SysLogOperate.WithContext(core.NewSkipGormGlobalHookContext())
 		from := core.CopyFrom[model.SysLogOperate](info)
 		context := core.GetContext[any](c)
 		if context.IsLogin() {
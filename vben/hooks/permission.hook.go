@@ -6,15 +6,16 @@ import (
 	_const "github.com/super-sunshines/echo-server-core/vben/const"
 	"github.com/super-sunshines/echo-server-core/vben/gorm/model"
 	"github.com/super-sunshines/echo-server-core/vben/gorm/query"
+	"gorm.io/gorm"
 )
 
 // RolePermissionHook 返回一个预定义的管理员角色权限映射
-// 该函数无需参数
+// 参数: db 为 Server 的数据库
 // 返回值: core.RoleMap 类型的映射
-func RolePermissionHook() (roleMap core.RoleMap, err error) {
+func RolePermissionHook(db *gorm.DB) (roleMap core.RoleMap, err error) {
 	roleMap = make(core.RoleMap)
-	roleQuery := query.Use(core.GetGormDB()).SysRole.WithContext(core.NewSkipGormGlobalHookContext())
-	menuQuery := query.Use(core.GetGormDB()).SysMenu.WithContext(core.NewSkipGormGlobalHookContext())
+	roleQuery := query.Use(db).SysRole.WithContext(core.NewSkipGormGlobalHookContext())
+	menuQuery := query.Use(db).SysMenu.WithContext(core.NewSkipGormGlobalHookContext())
 	allRole, err := roleQuery.Find()
 	allMenu, err := menuQuery.Find()
 	if err != nil {
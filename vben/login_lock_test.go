@@ -0,0 +1,38 @@
+package vben
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/super-sunshines/echo-server-core/core"
+	"github.com/super-sunshines/echo-server-core/core/coretest"
+)
+
+// 登录失败次数达到上限之后临时锁定 到期之后可以重新登录
+func TestLoginLockExpires(t *testing.T) {
+	cfg := coretest.NewConfig(t)
+	cfg.Jwt.MaxLoginFailCount = 3
+	cfg.Jwt.LoginLockDuration = 1
+	server := newTestServer(t, cfg, "alice", "alice-pass")
+
+	login := func(password string) testResponse {
+		return callTestServer(t, server, http.MethodPost, "/auth/login", "", map[string]string{"username": "alice", "password": password})
+	}
+	var resp testResponse
+	for i := 0; i < 3; i++ {
+		if resp = login("wrong-pass"); resp.Code == core.OK {
+			t.Fatalf("wrong password accepted: %+v", resp)
+		}
+	}
+	if !strings.Contains(resp.Msg, "分钟后再试") {
+		t.Fatalf("last failure should lock the account: %+v", resp)
+	}
+	// 锁定期间密码正确也不能登录
+	if resp = login("alice-pass"); resp.Code == core.OK || !strings.Contains(resp.Msg, "分钟后再试") {
+		t.Fatalf("locked account logged in: %+v", resp)
+	}
+	time.Sleep(1100 * time.Millisecond)
+	loginTestServer(t, server, "alice", "alice-pass")
+}
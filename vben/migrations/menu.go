@@ -0,0 +1,142 @@
+package migrations
+
+import (
+	"errors"
+	"github.com/duke-git/lancet/v2/slice"
+	"github.com/super-sunshines/echo-server-core/core"
+	_const "github.com/super-sunshines/echo-server-core/vben/const"
+	"gorm.io/gorm"
+	"strings"
+)
+
+// addSeedMenu 在系统管理目录下增加菜单 CodePrefix 开头的权限码挂在该菜单下并授权给管理员角色
+// 初始化时已经挂在系统管理目录下的权限码会移动到新菜单 菜单已经存在时跳过
+func addSeedMenu(tx *gorm.DB, item seedMenu, orderNum int64) error {
+	var catalogue sysMenuV0001
+	if err := tx.Where("path = ? AND pid = 0", systemPath).First(&catalogue).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	var count int64
+	if err := tx.Model(&sysMenuV0001{}).Where("path = ?", item.Path).Count(&count).Error; err != nil || count > 0 {
+		return err
+	}
+	menu, err := createMenu(tx, sysMenuV0001{
+		Pid:       catalogue.ID,
+		Type:      _const.MenuTypeMenu,
+		Name:      item.Name,
+		Path:      item.Path,
+		Component: item.Component,
+	}, sysMenuMetumV0001{Title: item.Title, Icon: item.Icon, OrderNum: orderNum})
+	if err != nil {
+		return err
+	}
+	codes := slice.Filter(slice.Unique(_const.GeneratePermissionCodes), func(_ int, code string) bool {
+		return strings.HasPrefix(code, item.CodePrefix+":")
+	})
+	var existing []sysMenuV0001
+	if err = tx.Where("type = ? AND api_code IN ?", _const.MenuTypeApi, codes).Find(&existing).Error; err != nil {
+		return err
+	}
+	menuIds := []int64{menu.ID}
+	if len(existing) > 0 {
+		existingIds := slice.Map(existing, func(_ int, item sysMenuV0001) int64 {
+			return item.ID
+		})
+		if err = tx.Model(&sysMenuV0001{}).Where("id IN ?", existingIds).Update("pid", menu.ID).Error; err != nil {
+			return err
+		}
+		menuIds = append(menuIds, existingIds...)
+		codes = slice.Difference(codes, slice.Map(existing, func(_ int, item sysMenuV0001) string {
+			return item.APICode
+		}))
+	}
+	ids, err := createApiMenus(tx, menu.ID, item.Title, codes)
+	if err != nil {
+		return err
+	}
+	return updateAdminMenus(tx, func(menuIdList []int64) []int64 {
+		return slice.Union(menuIdList, append(menuIds, ids...))
+	})
+}
+
+// removeSeedMenu 删除 addSeedMenu 创建的菜单和权限码
+func removeSeedMenu(tx *gorm.DB, item seedMenu) error {
+	tx = tx.Unscoped().Session(&gorm.Session{})
+	var menu sysMenuV0001
+	if err := tx.Where("path = ?", item.Path).First(&menu).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	var children []sysMenuV0001
+	if err := tx.Where("pid = ?", menu.ID).Find(&children).Error; err != nil {
+		return err
+	}
+	menuIds := append([]int64{menu.ID}, slice.Map(children, func(_ int, item sysMenuV0001) int64 {
+		return item.ID
+	})...)
+	if err := tx.Where("id IN ?", menuIds).Delete(&sysMenuV0001{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("id = ?", menu.MetaID).Delete(&sysMenuMetumV0001{}).Error; err != nil {
+		return err
+	}
+	return updateAdminMenus(tx, func(menuIdList []int64) []int64 {
+		return slice.Difference(menuIdList, menuIds)
+	})
+}
+
+// updateAdminMenus 修改管理员角色的菜单 管理员角色不存在时跳过
+func updateAdminMenus(tx *gorm.DB, update func(menuIdList []int64) []int64) error {
+	var role sysRoleV0001
+	if err := tx.Where("code = ?", AdminRoleCode).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	return tx.Model(&role).Update("menu_id_list", core.Array[int64](update(role.MenuIDList))).Error
+}
+
+// addSeedCodes 给已经存在的菜单增加权限码并授权给管理员角色 已经存在的权限码跳过
+func addSeedCodes(tx *gorm.DB, item seedMenu, codes ...string) error {
+	var menu sysMenuV0001
+	if err := tx.Where("path = ?", item.Path).First(&menu).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	var existing []string
+	if err := tx.Model(&sysMenuV0001{}).Where("type = ? AND api_code IN ?", _const.MenuTypeApi, codes).
+		Pluck("api_code", &existing).Error; err != nil {
+		return err
+	}
+	ids, err := createApiMenus(tx, menu.ID, item.Title, slice.Difference(codes, existing))
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+	return updateAdminMenus(tx, func(menuIdList []int64) []int64 {
+		return slice.Union(menuIdList, ids)
+	})
+}
+
+// removeSeedCodes 删除 addSeedCodes 创建的权限码
+func removeSeedCodes(tx *gorm.DB, codes ...string) error {
+	tx = tx.Unscoped().Session(&gorm.Session{})
+	var menuIds []int64
+	if err := tx.Model(&sysMenuV0001{}).Where("type = ? AND api_code IN ?", _const.MenuTypeApi, codes).
+		Pluck("id", &menuIds).Error; err != nil || len(menuIds) == 0 {
+		return err
+	}
+	if err := tx.Where("id IN ?", menuIds).Delete(&sysMenuV0001{}).Error; err != nil {
+		return err
+	}
+	return updateAdminMenus(tx, func(menuIdList []int64) []int64 {
+		return slice.Difference(menuIdList, menuIds)
+	})
+}
@@ -0,0 +1,216 @@
+package migrations
+
+import (
+	"fmt"
+	"github.com/super-sunshines/echo-server-core/core"
+	"gorm.io/gorm"
+)
+
+// Migrations vben 模块的全部数据库迁移 新的迁移追加在后面 已经发布的迁移不要修改
+// 迁移使用 schema_<版本>.go 中当时的表结构快照 不使用 model 修改 model 之后需要增加新的迁移和快照
+var Migrations = []core.Migration{
+	{
+		Version:     "20250101000001",
+		Description: "创建系统表",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(systemTables()...)
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(systemTables()...)
+		},
+	},
+	{
+		Version:     "20250101000002",
+		Description: "初始化管理员、管理员角色与基础菜单",
+		Up:          seedUp,
+		Down:        seedDown,
+	},
+	{
+		Version:     "20250101000003",
+		Description: "角色与字典增加乐观锁版本号",
+		Up: func(tx *gorm.DB) error {
+			return addColumns(tx, "Version", &sysRoleV0003{}, &sysDictV0003{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropColumns(tx, "Version", &sysRoleV0003{}, &sysDictV0003{})
+		},
+	},
+	{
+		Version:     "20250101000004",
+		Description: "回收站菜单与权限码",
+		Up: func(tx *gorm.DB) error {
+			return addSeedMenu(tx, recycleMenu, int64(len(seedMenus)+1))
+		},
+		Down: func(tx *gorm.DB) error {
+			return removeSeedMenu(tx, recycleMenu)
+		},
+	},
+	{
+		Version:     "20250101000005",
+		Description: "用户强制下线权限码",
+		Up: func(tx *gorm.DB) error {
+			return addSeedCodes(tx, seedMenus[0], "SYS::USER::KICK")
+		},
+		Down: func(tx *gorm.DB) error {
+			return removeSeedCodes(tx, "SYS::USER::KICK")
+		},
+	},
+	{
+		Version:     "20250101000006",
+		Description: "在线用户菜单与权限码",
+		Up: func(tx *gorm.DB) error {
+			return addSeedMenu(tx, onlineMenu, int64(len(seedMenus)+2))
+		},
+		Down: func(tx *gorm.DB) error {
+			return removeSeedMenu(tx, onlineMenu)
+		},
+	},
+	{
+		Version:     "20250101000007",
+		Description: "用户两步验证表",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&sysUserTotpV0007{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&sysUserTotpV0007{})
+		},
+	},
+	{
+		Version:     "20250101000008",
+		Description: "用户历史密码表",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&sysUserPasswordHistoryV0008{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&sysUserPasswordHistoryV0008{})
+		},
+	},
+	{
+		Version:     "20250101000009",
+		Description: "用户三方账号解绑与合并权限码",
+		Up: func(tx *gorm.DB) error {
+			return addSeedCodes(tx, seedMenus[0], "SYS::USER::UNBIND", "SYS::USER::MERGE")
+		},
+		Down: func(tx *gorm.DB) error {
+			return removeSeedCodes(tx, "SYS::USER::UNBIND", "SYS::USER::MERGE")
+		},
+	},
+	{
+		Version:     "20250101000010",
+		Description: "企业微信通讯录同步 部门对应表与权限码",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&sysDepartmentThirdBindV0010{}); err != nil {
+				return err
+			}
+			return addSeedCodes(tx, seedMenus[3], "SYS::DEPART::SYNC")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := removeSeedCodes(tx, "SYS::DEPART::SYNC"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable(&sysDepartmentThirdBindV0010{})
+		},
+	},
+	{
+		Version:     "20250101000011",
+		Description: "乐观锁版本号从 1 开始",
+		Up: func(tx *gorm.DB) error {
+			// 0 表示前端没有传版本号 已有的数据从 1 开始
+			for _, item := range []any{&sysRoleV0011{}, &sysDictV0011{}} {
+				if err := tx.Model(item).Where("version < ?", 1).UpdateColumn("version", 1).Error; err != nil {
+					return err
+				}
+				if err := tx.Migrator().AlterColumn(item, "Version"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			// 版本号只会增加 不需要回退
+			return nil
+		},
+	},
+	{
+		Version:     "20250101000012",
+		Description: "三方账号绑定唯一索引",
+		Up: func(tx *gorm.DB) error {
+			bind := &sysUserThirdBindV0012{}
+			// 解绑改为物理删除 已经软删除的绑定不再保留 否则会占用唯一索引
+			if err := tx.Unscoped().Where("delete_time IS NOT NULL").Delete(bind).Error; err != nil {
+				return err
+			}
+			var duplicates []struct {
+				LoginType string
+				Openid    string
+			}
+			err := tx.Model(bind).Select("login_type", "openid").Group("login_type, openid").Having("COUNT(*) > 1").Find(&duplicates).Error
+			if err != nil {
+				return err
+			}
+			if len(duplicates) > 0 {
+				return fmt.Errorf("sys_user_third_bind 中有 %d 个三方账号绑定了多个用户 请先处理 例如 %s:%s", len(duplicates), duplicates[0].LoginType, duplicates[0].Openid)
+			}
+			// 缩短字段长度 mysql 的索引长度限制是 3072 字节
+			for _, field := range []string{"LoginType", "Openid"} {
+				if err = tx.Migrator().AlterColumn(bind, field); err != nil {
+					return err
+				}
+			}
+			if tx.Migrator().HasIndex(bind, "uk_sys_user_third_bind_openid") {
+				return nil
+			}
+			return tx.Migrator().CreateIndex(bind, "uk_sys_user_third_bind_openid")
+		},
+		Down: func(tx *gorm.DB) error {
+			bind := &sysUserThirdBindV0012{}
+			if !tx.Migrator().HasIndex(bind, "uk_sys_user_third_bind_openid") {
+				return nil
+			}
+			return tx.Migrator().DropIndex(bind, "uk_sys_user_third_bind_openid")
+		},
+	},
+}
+
+// systemTables 20250101000001 创建的系统表
+func systemTables() []any {
+	return []any{
+		&sysDepartmentV0001{},
+		&sysDictV0001{},
+		&sysDictChildV0001{},
+		&sysLogLoginV0001{},
+		&sysLogOperateV0001{},
+		&sysMenuV0001{},
+		&sysMenuMetumV0001{},
+		&sysRoleV0001{},
+		&sysUserV0001{},
+		&sysUserDepartmentV0001{},
+		&sysUserThirdBindV0001{},
+	}
+}
+
+// addColumns 给多个表增加模型上的字段 字段已经存在时跳过
+func addColumns(tx *gorm.DB, field string, models ...any) error {
+	for _, item := range models {
+		if tx.Migrator().HasColumn(item, field) {
+			continue
+		}
+		if err := tx.Migrator().AddColumn(item, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropColumns 删除多个表上的字段 字段不存在时跳过
+func dropColumns(tx *gorm.DB, field string, models ...any) error {
+	for _, item := range models {
+		if !tx.Migrator().HasColumn(item, field) {
+			continue
+		}
+		if err := tx.Migrator().DropColumn(item, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package migrations
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/super-sunshines/echo-server-core/core"
+	"github.com/super-sunshines/echo-server-core/vben/gorm/model"
+	"github.com/super-sunshines/echo-server-core/vben/vo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openMemoryDB 每个测试使用单独的内存库 同一个库的连接共享数据
+func openMemoryDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	return db
+}
+
+func TestMigrationsOnSqlite(t *testing.T) {
+	db := openMemoryDB(t)
+	migrator := core.NewMigrator(db, Migrations...)
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	status, err := migrator.Status()
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	for _, item := range status {
+		if !item.Applied {
+			t.Fatalf("migration %s not applied", item.Version)
+		}
+	}
+	// 再次执行时没有需要执行的迁移
+	if err = migrator.Up(); err != nil {
+		t.Fatalf("up again: %v", err)
+	}
+
+	// 全部回滚之后可以重新执行
+	if err = migrator.Down(len(Migrations)); err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	if db.Migrator().HasTable(&model.SysUser{}) {
+		t.Fatal("sys_user still exists after down")
+	}
+	if err = migrator.Up(); err != nil {
+		t.Fatalf("up after down: %v", err)
+	}
+}
+
+// 迁移之后的表结构包含 model 的全部字段和索引 修改 model 之后没有增加迁移时失败
+func TestMigrationsMatchModels(t *testing.T) {
+	db := openMemoryDB(t)
+	if err := core.NewMigrator(db, Migrations...).Up(); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	models := []any{
+		&model.SysDepartment{}, &model.SysDepartmentThirdBind{}, &model.SysDict{}, &model.SysDictChild{},
+		&model.SysLogLogin{}, &model.SysLogOperate{}, &model.SysMenu{}, &model.SysMenuMetum{}, &model.SysRole{},
+		&model.SysUser{}, &model.SysUserDepartment{}, &model.SysUserPasswordHistory{}, &model.SysUserThirdBind{},
+		&model.SysUserTotp{},
+	}
+	for _, item := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(item); err != nil {
+			t.Fatalf("parse %T: %v", item, err)
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && !db.Migrator().HasColumn(item, field.DBName) {
+				t.Errorf("%s.%s has no migration", stmt.Schema.Table, field.DBName)
+			}
+		}
+		for _, index := range stmt.Schema.ParseIndexes() {
+			if !db.Migrator().HasIndex(item, index.Name) {
+				t.Errorf("%s index %s has no migration", stmt.Schema.Table, index.Name)
+			}
+		}
+	}
+}
+
+func TestPreGormOnSqlite(t *testing.T) {
+	db := openMemoryDB(t)
+	if err := core.NewMigrator(db, Migrations...).Up(); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	users := core.NewService[model.SysUser, vo.SysUserVo]()
+	err, admin := users.SetDB(db).FindOne(func(db *gorm.DB) *gorm.DB {
+		return db.Where("username = ?", "admin")
+	})
+	if err != nil {
+		t.Fatalf("find admin: %v", err)
+	}
+	if !core.ComparePasswords(admin.Password, "admin123!") || len(admin.RoleCodeList) == 0 {
+		t.Fatalf("unexpected seeded admin: %+v", admin)
+	}
+
+	// 数组字段按 JSON 保存 分页查询和条件查询
+	roles := core.NewService[model.SysRole, vo.SysRoleVo]()
+	err, role := roles.SetDB(db).InsertOne(model.SysRole{Code: "tester", Name: "测试", MenuIDList: core.Array[int64]{1, 2}})
+	if err != nil {
+		t.Fatalf("insert role: %v", err)
+	}
+	err, page := roles.SetDB(db).FindListByPage(core.PageParam{Page: 1, PageSize: 10}, func(db *gorm.DB) *gorm.DB {
+		return db.Where("code like ?", "test%")
+	})
+	if err != nil || page.Total != 1 || len(page.Items[0].MenuIDList) != 2 {
+		t.Fatalf("page roles: %v %+v", err, page)
+	}
+
+	// 乐观锁 旧版本号的修改返回冲突
+	role.Name = "测试角色"
+	if err, _ = roles.SetDB(db).SaveByPrimaryKey(role.ID, role); err != nil {
+		t.Fatalf("save role: %v", err)
+	}
+	role.Name = "旧数据"
+	err, _ = roles.SetDB(db).SaveByPrimaryKey(role.ID, role)
+	var codeErr *core.CodeError
+	if !errors.As(err, &codeErr) || codeErr.GetErrCode() != core.CURD_VERSION_CONFLICT_ERROR {
+		t.Fatalf("stale save: want version conflict, got %v", err)
+	}
+
+	// 软删除之后查询不到 Unscoped 可以查到
+	if err, _ = roles.SetDB(db).DeleteByPrimaryKeys([]int64{role.ID}); err != nil {
+		t.Fatalf("delete role: %v", err)
+	}
+	if roles.SetDB(db).Exist(func(db *gorm.DB) *gorm.DB { return db.Where("id = ?", role.ID) }) {
+		t.Fatal("deleted role is still visible")
+	}
+	if roles.SetDB(db).Unscoped().Count(func(db *gorm.DB) *gorm.DB { return db.Where("id = ?", role.ID) }) != 1 {
+		t.Fatal("deleted role is missing from unscoped query")
+	}
+}
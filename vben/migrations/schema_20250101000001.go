@@ -0,0 +1,231 @@
+package migrations
+
+import (
+	"github.com/super-sunshines/echo-server-core/core"
+	"gorm.io/gorm"
+)
+
+// 20250101000001 创建系统表时的表结构 已经发布的快照不要修改
+// 之后的表结构变化放在新的迁移中 并使用那个版本的快照 不受 model 后续修改的影响
+
+type sysDepartmentV0001 struct {
+	ID           int64          `gorm:"column:id;primaryKey;autoIncrement:true;comment:主键"`
+	Pid          int64          `gorm:"column:pid;comment:父ID"`
+	Name         string         `gorm:"column:name;type:varchar(255);comment:部门名称"`
+	OrderNum     int64          `gorm:"column:order_num;comment:排序"`
+	Description  string         `gorm:"column:description;type:varchar(500);comment:权限描述"`
+	EnableStatus int64          `gorm:"column:enable_status;comment:部门状态"`
+	CreateDept   int64          `gorm:"column:create_dept;comment:创建部门"`
+	CreateBy     int64          `gorm:"column:create_by;comment:创建者"`
+	CreateTime   core.Time      `gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+	UpdateBy     int64          `gorm:"column:update_by;comment:更新者"`
+	UpdateTime   core.Time      `gorm:"column:update_time;autoUpdateTime;comment:更新时间"`
+	DeleteTime   gorm.DeletedAt `gorm:"column:delete_time;comment:删除时间"`
+}
+
+func (*sysDepartmentV0001) TableName() string { return "sys_department" }
+
+type sysDictV0001 struct {
+	ID           int64          `gorm:"column:id;primaryKey;autoIncrement:true"`
+	Module       int64          `gorm:"column:module;comment:所属模块"`
+	Code         string         `gorm:"column:code;type:varchar(255);comment:字符串代码"`
+	Regular      string         `gorm:"column:regular;type:varchar(255);comment:正则字符串"`
+	Name         string         `gorm:"column:name;type:varchar(255);comment:字典名称"`
+	ValueType    int64          `gorm:"column:value_type;comment:字典值类型"`
+	Describe     string         `gorm:"column:describe;type:varchar(255);comment:描述"`
+	EnableStatus int64          `gorm:"column:enable_status;comment:启用状态"`
+	CreateDept   int64          `gorm:"column:create_dept;comment:创建部门"`
+	CreateBy     int64          `gorm:"column:create_by;comment:创建者"`
+	CreateTime   core.Time      `gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+	UpdateBy     int64          `gorm:"column:update_by;comment:更新者"`
+	UpdateTime   core.Time      `gorm:"column:update_time;autoUpdateTime;comment:更新时间"`
+	DeleteTime   gorm.DeletedAt `gorm:"column:delete_time;comment:删除时间"`
+}
+
+func (*sysDictV0001) TableName() string { return "sys_dict" }
+
+type sysDictChildV0001 struct {
+	ID         int64          `gorm:"column:id;primaryKey;autoIncrement:true"`
+	DictCode   string         `gorm:"column:dict_code;type:varchar(255);comment:字典代码"`
+	Type       int64          `gorm:"column:type;comment:值类型"`
+	Value      string         `gorm:"column:value;type:varchar(255);comment:值"`
+	Label      string         `gorm:"column:label;type:varchar(255);comment:标签"`
+	Style      string         `gorm:"column:style;type:varchar(255);comment:样式"`
+	Describe   string         `gorm:"column:describe;type:varchar(255);comment:描述"`
+	OrderNum   int64          `gorm:"column:order_num;comment:排序字符串"`
+	ItemClass  string         `gorm:"column:item_class;type:varchar(255);comment:样式标签页"`
+	CreateDept int64          `gorm:"column:create_dept;comment:创建部门"`
+	CreateBy   int64          `gorm:"column:create_by;comment:创建者"`
+	CreateTime core.Time      `gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+	UpdateBy   int64          `gorm:"column:update_by;comment:更新者"`
+	UpdateTime core.Time      `gorm:"column:update_time;autoUpdateTime;comment:更新时间"`
+	DeleteTime gorm.DeletedAt `gorm:"column:delete_time;comment:删除时间"`
+}
+
+func (*sysDictChildV0001) TableName() string { return "sys_dict_child" }
+
+type sysLogLoginV0001 struct {
+	ID              int64     `gorm:"column:id;primaryKey;autoIncrement:true;comment:主键"`
+	LoginType       int64     `gorm:"column:login_type;comment:登录方式"`
+	RequestMethod   string    `gorm:"column:request_method;type:varchar(255);comment:请求方法"`
+	UserAgent       string    `gorm:"column:user_agent;type:varchar(500);comment:信息"`
+	OperateName     string    `gorm:"column:operate_name;type:varchar(255);comment:操作人员"`
+	Status          int64     `gorm:"column:status;comment:登录状态"`
+	Browser         string    `gorm:"column:browser;type:varchar(255);comment:浏览器类型"`
+	Os              string    `gorm:"column:os;type:varchar(255);comment:操作系统"`
+	OperateIP       string    `gorm:"column:operate_ip;type:varchar(255);comment:请求IP"`
+	OperateLocation string    `gorm:"column:operate_location;type:varchar(255);comment:请求地点"`
+	Msg             string    `gorm:"column:msg;type:text;comment:错误信息"`
+	OperateTime     core.Time `gorm:"column:operate_time;comment:操作时间"`
+}
+
+func (*sysLogLoginV0001) TableName() string { return "sys_log_login" }
+
+type sysLogOperateV0001 struct {
+	ID              int64     `gorm:"column:id;primaryKey;autoIncrement:true;comment:主键"`
+	Title           string    `gorm:"column:title;type:varchar(255);comment:标题"`
+	BusinessType    int64     `gorm:"column:business_type;comment:业务类型"`
+	CallFunc        string    `gorm:"column:call_func;type:varchar(255);comment:执行的方法"`
+	RequestMethod   string    `gorm:"column:request_method;type:varchar(255);comment:请求方法"`
+	OperateUserID   int64     `gorm:"column:operate_user_id;comment:操作者ID"`
+	OperateType     int64     `gorm:"column:operate_type;comment:操作类型"`
+	OperateName     string    `gorm:"column:operate_name;type:varchar(255);comment:操作人员"`
+	OperateDepart   string    `gorm:"column:operate_depart;type:varchar(255);comment:部门名称"`
+	OperateURL      string    `gorm:"column:operate_url;type:varchar(255);comment:请求地址"`
+	OperateIP       string    `gorm:"column:operate_ip;type:varchar(255);comment:请求IP"`
+	OperateLocation string    `gorm:"column:operate_location;type:varchar(255);comment:请求地点"`
+	OperateParam    string    `gorm:"column:operate_param;type:text;comment:请求参数"`
+	RequestJSONBody string    `gorm:"column:request_json_body;type:text;comment:请求体"`
+	JSONResult      string    `gorm:"column:json_result;type:text;comment:返回响应"`
+	ErrorMsg        string    `gorm:"column:error_msg;type:text;comment:错误信息"`
+	Status          int64     `gorm:"column:status;comment:操作状态"`
+	OperateTime     core.Time `gorm:"column:operate_time;comment:操作时间"`
+	CostTime        int64     `gorm:"column:cost_time;comment:消耗时间"`
+}
+
+func (*sysLogOperateV0001) TableName() string { return "sys_log_operate" }
+
+type sysMenuV0001 struct {
+	ID             int64          `gorm:"column:id;primaryKey;autoIncrement:true;comment:id"`
+	Pid            int64          `gorm:"column:pid;comment:父目录"`
+	Type           int64          `gorm:"column:type;comment:目录类型 0:目录 1:接口"`
+	APICode        string         `gorm:"column:api_code;type:varchar(255);comment:接口代码"`
+	APIDescription string         `gorm:"column:api_description;type:varchar(255);comment:接口描述"`
+	MetaID         int64          `gorm:"column:meta_id;comment:metaID"`
+	Name           string         `gorm:"column:name;type:varchar(255);comment:路由名称"`
+	Path           string         `gorm:"column:path;type:varchar(255);comment:访问路径"`
+	Component      string         `gorm:"column:component;type:varchar(255);comment:组件地址"`
+	CreateDept     int64          `gorm:"column:create_dept;comment:创建部门"`
+	CreateBy       int64          `gorm:"column:create_by;comment:创建者"`
+	CreateTime     core.Time      `gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+	UpdateBy       int64          `gorm:"column:update_by;comment:更新者"`
+	UpdateTime     core.Time      `gorm:"column:update_time;autoUpdateTime;comment:更新时间"`
+	DeleteTime     gorm.DeletedAt `gorm:"column:delete_time;comment:删除时间"`
+}
+
+func (*sysMenuV0001) TableName() string { return "sys_menu" }
+
+type sysMenuMetumV0001 struct {
+	ID                 int64              `gorm:"column:id;primaryKey;autoIncrement:true;comment:id"`
+	Title              string             `gorm:"column:title;type:varchar(255);comment:路由名称"`
+	Icon               string             `gorm:"column:icon;type:varchar(255);comment:访问路径"`
+	OrderNum           int64              `gorm:"column:order_num;comment:排序"`
+	ActiveIcon         string             `gorm:"column:active_icon;type:varchar(255);comment:激活时的Icon"`
+	HideInMenu         core.IntBool       `gorm:"column:hide_in_menu;comment:隐藏菜单"`
+	HideInTab          core.IntBool       `gorm:"column:hide_in_tab;comment:标签页隐藏"`
+	HideInBreadcrumb   core.IntBool       `gorm:"column:hide_in_breadcrumb;comment:面包屑中隐藏"`
+	HideChildrenInMenu core.IntBool       `gorm:"column:hide_children_in_menu;comment:子菜单隐藏"`
+	Authority          core.Array[string] `gorm:"column:authority;comment:权限代码数组"`
+	ActivePath         string             `gorm:"column:active_path;type:varchar(255);comment:激活的菜单"`
+	AffixTab           core.IntBool       `gorm:"column:affix_tab;comment:固定标签"`
+	AffixTabOrder      int64              `gorm:"column:affix_tab_order;comment:固定标签排序"`
+	IframeSrc          string             `gorm:"column:iframe_src;type:varchar(500);comment:内嵌iframe地址"`
+	IgnoreAccess       core.IntBool       `gorm:"column:ignore_access;comment:忽略权限"`
+	Link               string             `gorm:"column:link;type:varchar(255);comment:跳转打开地址"`
+	OpenInNewWindow    core.IntBool       `gorm:"column:open_in_new_window;comment:在新窗口打开"`
+	NoBasicLayout      core.IntBool       `gorm:"column:no_basic_layout;comment:基础布局"`
+	CreateDept         int64              `gorm:"column:create_dept;comment:创建部门"`
+	CreateBy           int64              `gorm:"column:create_by;comment:创建者"`
+	CreateTime         core.Time          `gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+	UpdateBy           int64              `gorm:"column:update_by;comment:更新者"`
+	UpdateTime         core.Time          `gorm:"column:update_time;autoUpdateTime;comment:更新时间"`
+	DeleteTime         gorm.DeletedAt     `gorm:"column:delete_time;comment:删除时间"`
+}
+
+func (*sysMenuMetumV0001) TableName() string { return "sys_menu_meta" }
+
+type sysRoleV0001 struct {
+	ID             int64             `gorm:"column:id;primaryKey;autoIncrement:true;comment:主键"`
+	Code           string            `gorm:"column:code;type:varchar(255);comment:权限代码"`
+	Name           string            `gorm:"column:name;type:varchar(255);comment:角色名称"`
+	Description    string            `gorm:"column:description;type:varchar(500);comment:权限描述"`
+	MenuIDList     core.Array[int64] `gorm:"column:menu_id_list;comment:目录列表"`
+	HomePath       string            `gorm:"column:home_path;type:varchar(255);comment:主页目录"`
+	QueryStrategy  int64             `gorm:"column:query_strategy;default:1;comment:查询策略"`
+	UpdateStrategy int64             `gorm:"column:update_strategy;default:1;comment:更新策略"`
+	EnableStatus   int64             `gorm:"column:enable_status;comment:启用状态"`
+	CreateDept     int64             `gorm:"column:create_dept;comment:创建部门"`
+	CreateBy       int64             `gorm:"column:create_by;comment:创建者"`
+	CreateTime     core.Time         `gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+	UpdateBy       int64             `gorm:"column:update_by;comment:更新者"`
+	UpdateTime     core.Time         `gorm:"column:update_time;autoUpdateTime;comment:更新时间"`
+	DeleteTime     gorm.DeletedAt    `gorm:"column:delete_time;comment:删除时间"`
+}
+
+func (*sysRoleV0001) TableName() string { return "sys_role" }
+
+type sysUserV0001 struct {
+	ID                 int64              `gorm:"column:id;primaryKey;autoIncrement:true;comment:主键"`
+	Username           string             `gorm:"column:username;type:varchar(255);comment:用户名"`
+	Password           string             `gorm:"column:password;type:varchar(255);comment:密码"`
+	NickName           string             `gorm:"column:nick_name;type:varchar(255);comment:昵称"`
+	RealName           string             `gorm:"column:real_name;type:varchar(255);comment:真实姓名"`
+	RoleCodeList       core.Array[string] `gorm:"column:role_code_list;comment:角色CODE列表"`
+	Email              string             `gorm:"column:email;type:varchar(255);comment:邮箱地址"`
+	Avatar             string             `gorm:"column:avatar;type:varchar(255);comment:头像"`
+	LoginFailCount     int64              `gorm:"column:login_fail_count;comment:登录失败次数"`
+	Phone              string             `gorm:"column:phone;type:varchar(11);comment:手机号"`
+	EnableStatus       int64              `gorm:"column:enable_status;comment:状态"`
+	LastOnline         int64              `gorm:"column:last_online;comment:上次在线时间"`
+	DepartmentID       int64              `gorm:"column:department_id;comment:部门ID"`
+	NeedChangePassword core.IntBool       `gorm:"column:need_change_password;comment:需要修改密码"`
+	CreateDept         int64              `gorm:"column:create_dept;comment:创建部门"`
+	CreateBy           int64              `gorm:"column:create_by;comment:创建者"`
+	CreateTime         core.Time          `gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+	UpdateBy           int64              `gorm:"column:update_by;comment:更新者"`
+	UpdateTime         core.Time          `gorm:"column:update_time;autoUpdateTime;comment:更新时间"`
+	DeleteTime         gorm.DeletedAt     `gorm:"column:delete_time;comment:删除时间"`
+}
+
+func (*sysUserV0001) TableName() string { return "sys_user" }
+
+type sysUserDepartmentV0001 struct {
+	ID           int64          `gorm:"column:id;primaryKey;autoIncrement:true;comment:主键"`
+	UserID       int64          `gorm:"column:user_id;comment:用户ID"`
+	DepartmentID string         `gorm:"column:department_id;type:varchar(500);comment:部门ID"`
+	Role         string         `gorm:"column:role;type:varchar(500);comment:部门中角色(未使用)"`
+	CreateDept   string         `gorm:"column:create_dept;type:varchar(255);comment:创建部门"`
+	CreateBy     string         `gorm:"column:create_by;type:varchar(255);comment:创建者"`
+	CreateTime   core.Time      `gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+	UpdateBy     string         `gorm:"column:update_by;type:varchar(255);comment:更新者"`
+	UpdateTime   core.Time      `gorm:"column:update_time;autoUpdateTime;comment:更新时间"`
+	DeleteTime   gorm.DeletedAt `gorm:"column:delete_time;comment:删除时间"`
+}
+
+func (*sysUserDepartmentV0001) TableName() string { return "sys_user_department" }
+
+type sysUserThirdBindV0001 struct {
+	ID          int64          `gorm:"column:id;primaryKey;autoIncrement:true;comment:主键"`
+	UserID      int64          `gorm:"column:user_id;comment:用户ID"`
+	LoginType   string         `gorm:"column:login_type;type:varchar(500);comment:三方登录类型"`
+	Openid      string         `gorm:"column:openid;type:varchar(500);comment:三方唯一标识"`
+	AccessToken string         `gorm:"column:access_token;type:varchar(255);comment:三方Token"`
+	CreateDept  int64          `gorm:"column:create_dept;comment:创建部门"`
+	CreateBy    int64          `gorm:"column:create_by;comment:创建者"`
+	CreateTime  core.Time      `gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+	UpdateBy    int64          `gorm:"column:update_by;comment:更新者"`
+	UpdateTime  core.Time      `gorm:"column:update_time;autoUpdateTime;comment:更新时间"`
+	DeleteTime  gorm.DeletedAt `gorm:"column:delete_time;comment:删除时间"`
+}
+
+func (*sysUserThirdBindV0001) TableName() string { return "sys_user_third_bind" }
@@ -0,0 +1,15 @@
+package migrations
+
+// 20250101000003 角色与字典增加的乐观锁版本号 只包含这次增加的字段
+
+type sysRoleV0003 struct {
+	Version int64 `gorm:"column:version;not null;default:0;comment:版本号"`
+}
+
+func (*sysRoleV0003) TableName() string { return "sys_role" }
+
+type sysDictV0003 struct {
+	Version int64 `gorm:"column:version;not null;default:0;comment:版本号"`
+}
+
+func (*sysDictV0003) TableName() string { return "sys_dict" }
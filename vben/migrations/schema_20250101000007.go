@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"github.com/super-sunshines/echo-server-core/core"
+	"gorm.io/gorm"
+)
+
+// 20250101000007 用户两步验证表
+
+type sysUserTotpV0007 struct {
+	ID            int64              `gorm:"column:id;primaryKey;autoIncrement:true;comment:主键"`
+	UserID        int64              `gorm:"column:user_id;comment:用户ID"`
+	Secret        string             `gorm:"column:secret;type:varchar(64);comment:TOTP密钥"`
+	Enabled       core.IntBool       `gorm:"column:enabled;comment:是否已启用"`
+	RecoveryCodes core.Array[string] `gorm:"column:recovery_codes;comment:恢复码摘要"`
+	LastCounter   int64              `gorm:"column:last_counter;comment:最后使用的时间步"`
+	CreateDept    int64              `gorm:"column:create_dept;comment:创建部门"`
+	CreateBy      int64              `gorm:"column:create_by;comment:创建者"`
+	CreateTime    core.Time          `gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+	UpdateBy      int64              `gorm:"column:update_by;comment:更新者"`
+	UpdateTime    core.Time          `gorm:"column:update_time;autoUpdateTime;comment:更新时间"`
+	DeleteTime    gorm.DeletedAt     `gorm:"column:delete_time;comment:删除时间"`
+}
+
+func (*sysUserTotpV0007) TableName() string { return "sys_user_totp" }
@@ -0,0 +1,17 @@
+package migrations
+
+import (
+	"github.com/super-sunshines/echo-server-core/core"
+)
+
+// 20250101000008 用户历史密码表
+
+type sysUserPasswordHistoryV0008 struct {
+	ID         int64     `gorm:"column:id;primaryKey;autoIncrement:true;comment:主键"`
+	UserID     int64     `gorm:"column:user_id;comment:用户ID"`
+	Password   string    `gorm:"column:password;type:varchar(255);comment:加密后的密码"`
+	CreateBy   int64     `gorm:"column:create_by;comment:创建者"`
+	CreateTime core.Time `gorm:"column:create_time;autoCreateTime;comment:修改密码的时间"`
+}
+
+func (*sysUserPasswordHistoryV0008) TableName() string { return "sys_user_password_history" }
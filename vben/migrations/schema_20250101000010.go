@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"github.com/super-sunshines/echo-server-core/core"
+	"gorm.io/gorm"
+)
+
+// 20250101000010 企业微信通讯录同步的部门对应表
+
+type sysDepartmentThirdBindV0010 struct {
+	ID           int64          `gorm:"column:id;primaryKey;autoIncrement:true;comment:主键"`
+	DepartmentID int64          `gorm:"column:department_id;comment:部门ID"`
+	Platform     string         `gorm:"column:platform;type:varchar(500);comment:三方平台"`
+	ThirdID      string         `gorm:"column:third_id;type:varchar(500);comment:三方部门ID"`
+	CreateDept   int64          `gorm:"column:create_dept;comment:创建部门"`
+	CreateBy     int64          `gorm:"column:create_by;comment:创建者"`
+	CreateTime   core.Time      `gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+	UpdateBy     int64          `gorm:"column:update_by;comment:更新者"`
+	UpdateTime   core.Time      `gorm:"column:update_time;autoUpdateTime;comment:更新时间"`
+	DeleteTime   gorm.DeletedAt `gorm:"column:delete_time;comment:删除时间"`
+}
+
+func (*sysDepartmentThirdBindV0010) TableName() string { return "sys_department_third_bind" }
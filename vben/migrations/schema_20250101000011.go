@@ -0,0 +1,15 @@
+package migrations
+
+// 20250101000011 乐观锁版本号的默认值改为 1 只包含这次修改的字段
+
+type sysRoleV0011 struct {
+	Version int64 `gorm:"column:version;not null;default:1;comment:版本号"`
+}
+
+func (*sysRoleV0011) TableName() string { return "sys_role" }
+
+type sysDictV0011 struct {
+	Version int64 `gorm:"column:version;not null;default:1;comment:版本号"`
+}
+
+func (*sysDictV0011) TableName() string { return "sys_dict" }
@@ -0,0 +1,10 @@
+package migrations
+
+// 20250101000012 三方账号绑定缩短字段长度并增加唯一索引 只包含这次修改的字段
+
+type sysUserThirdBindV0012 struct {
+	LoginType string `gorm:"column:login_type;type:varchar(64);uniqueIndex:uk_sys_user_third_bind_openid,priority:1;comment:三方登录类型"`
+	Openid    string `gorm:"column:openid;type:varchar(255);uniqueIndex:uk_sys_user_third_bind_openid,priority:2;comment:三方唯一标识"`
+}
+
+func (*sysUserThirdBindV0012) TableName() string { return "sys_user_third_bind" }
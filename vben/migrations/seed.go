@@ -0,0 +1,211 @@
+package migrations
+
+import (
+	"github.com/duke-git/lancet/v2/slice"
+	"github.com/super-sunshines/echo-server-core/core"
+	_const "github.com/super-sunshines/echo-server-core/vben/const"
+	"gorm.io/gorm"
+	"strings"
+)
+
+const (
+	// AdminUsername 初始管理员账号 首次登录需要修改密码
+	AdminUsername = "admin"
+	// AdminPassword 初始管理员密码
+	AdminPassword = "admin123!"
+	// AdminRoleCode 管理员角色
+	AdminRoleCode = "SUPER_ADMIN"
+	// 数据权限 全部数据
+	allDataStrategy = 3
+	// 系统管理目录的路径
+	systemPath = "/system"
+)
+
+// seedMenu 初始化的菜单 CodePrefix 开头的权限码挂在该菜单下面
+type seedMenu struct {
+	Name       string
+	Title      string
+	Path       string
+	Component  string
+	Icon       string
+	CodePrefix string
+}
+
+var seedMenus = []seedMenu{
+	{Name: "SystemUser", Title: "用户管理", Path: "/system/user", Component: "/system/user/index", Icon: "mdi:account", CodePrefix: "SYS::USER"},
+	{Name: "SystemRole", Title: "角色管理", Path: "/system/role", Component: "/system/role/index", Icon: "mdi:account-group", CodePrefix: "SYS::ROLE"},
+	{Name: "SystemMenu", Title: "菜单管理", Path: "/system/menu", Component: "/system/menu/index", Icon: "mdi:menu", CodePrefix: "SYS::MENU"},
+	{Name: "SystemDept", Title: "部门管理", Path: "/system/dept", Component: "/system/dept/index", Icon: "mdi:sitemap", CodePrefix: "SYS::DEPART"},
+	{Name: "SystemDict", Title: "字典管理", Path: "/system/dict", Component: "/system/dict/index", Icon: "mdi:book-open-variant", CodePrefix: "SYS::DICT"},
+	{Name: "SystemLog", Title: "日志管理", Path: "/system/log", Component: "/system/log/index", Icon: "mdi:file-document", CodePrefix: "SYS::LOG"},
+}
+
+var recycleMenu = seedMenu{Name: "SystemRecycle", Title: "回收站", Path: "/system/recycle", Component: "/system/recycle/index", Icon: "mdi:delete-restore", CodePrefix: "SYS::RECYCLE"}
+
+var onlineMenu = seedMenu{Name: "SystemOnline", Title: "在线用户", Path: "/system/online", Component: "/system/online/index", Icon: "mdi:account-network", CodePrefix: "SYS::ONLINE"}
+
+// 权限码最后一段对应的描述
+var codeActionName = map[string]string{
+	"QUERY":   "查询",
+	"OPTIONS": "选项",
+	"UPDATE":  "修改",
+	"ADD":     "新增",
+	"DEL":     "删除",
+	"UNLOCK":  "解锁",
+	"LOCK":    "锁定",
+	"RESTORE": "恢复",
+	"PURGE":   "彻底删除",
+	"KICK":    "强制下线",
+	"UNBIND":  "解绑三方账号",
+	"MERGE":   "合并",
+	"SYNC":    "同步",
+}
+
+func seedUp(tx *gorm.DB) error {
+	department := sysDepartmentV0001{Pid: 0, Name: "总部", OrderNum: 1, EnableStatus: _const.CommonStateOk}
+	if err := tx.Create(&department).Error; err != nil {
+		return err
+	}
+	var menuIds []int64
+	catalogue, err := createMenu(tx, sysMenuV0001{
+		Type: _const.MenuTypeCatalogue,
+		Name: "System",
+		Path: systemPath,
+	}, sysMenuMetumV0001{Title: "系统管理", Icon: "mdi:cog", OrderNum: 9999})
+	if err != nil {
+		return err
+	}
+	menuIds = append(menuIds, catalogue.ID)
+	codes := slice.Unique(_const.GeneratePermissionCodes)
+	for i, item := range seedMenus {
+		menu, err := createMenu(tx, sysMenuV0001{
+			Pid:       catalogue.ID,
+			Type:      _const.MenuTypeMenu,
+			Name:      item.Name,
+			Path:      item.Path,
+			Component: item.Component,
+		}, sysMenuMetumV0001{Title: item.Title, Icon: item.Icon, OrderNum: int64(i + 1)})
+		if err != nil {
+			return err
+		}
+		menuIds = append(menuIds, menu.ID)
+		menuCodes := slice.Filter(codes, func(_ int, code string) bool {
+			return strings.HasPrefix(code, item.CodePrefix+":")
+		})
+		codes = slice.Difference(codes, menuCodes)
+		ids, err := createApiMenus(tx, menu.ID, item.Title, menuCodes)
+		if err != nil {
+			return err
+		}
+		menuIds = append(menuIds, ids...)
+	}
+	// 没有匹配到菜单的权限码挂在系统管理目录下
+	ids, err := createApiMenus(tx, catalogue.ID, "系统管理", codes)
+	if err != nil {
+		return err
+	}
+	menuIds = append(menuIds, ids...)
+
+	role := sysRoleV0001{
+		Code:           AdminRoleCode,
+		Name:           "超级管理员",
+		Description:    "系统初始化创建的管理员角色",
+		MenuIDList:     menuIds,
+		HomePath:       seedMenus[0].Path,
+		QueryStrategy:  allDataStrategy,
+		UpdateStrategy: allDataStrategy,
+		EnableStatus:   _const.CommonStateOk,
+	}
+	if err = tx.Create(&role).Error; err != nil {
+		return err
+	}
+	return tx.Create(&sysUserV0001{
+		Username:           AdminUsername,
+		Password:           core.HashPassword(AdminPassword),
+		NickName:           "超级管理员",
+		RealName:           "超级管理员",
+		RoleCodeList:       []string{AdminRoleCode},
+		EnableStatus:       _const.CommonStateOk,
+		DepartmentID:       department.ID,
+		NeedChangePassword: true,
+	}).Error
+}
+
+func seedDown(tx *gorm.DB) error {
+	tx = tx.Unscoped().Session(&gorm.Session{})
+	if err := tx.Where("username = ?", AdminUsername).Delete(&sysUserV0001{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("code = ?", AdminRoleCode).Delete(&sysRoleV0001{}).Error; err != nil {
+		return err
+	}
+	var catalogue sysMenuV0001
+	if err := tx.Where("path = ? AND pid = 0", systemPath).First(&catalogue).Error; err == nil {
+		var menus []sysMenuV0001
+		if err = tx.Where("id = ? OR pid = ?", catalogue.ID, catalogue.ID).Find(&menus).Error; err != nil {
+			return err
+		}
+		menuIds := slice.Map(menus, func(_ int, item sysMenuV0001) int64 {
+			return item.ID
+		})
+		metaIds := slice.Map(menus, func(_ int, item sysMenuV0001) int64 {
+			return item.MetaID
+		})
+		if err = tx.Where("id IN ? OR pid IN ?", menuIds, menuIds).Delete(&sysMenuV0001{}).Error; err != nil {
+			return err
+		}
+		if err = tx.Where("id IN ?", metaIds).Delete(&sysMenuMetumV0001{}).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Where("pid = 0 AND name = ?", "总部").Delete(&sysDepartmentV0001{}).Error
+}
+
+// createMenu 先创建 meta 再创建菜单
+func createMenu(tx *gorm.DB, menu sysMenuV0001, meta sysMenuMetumV0001) (sysMenuV0001, error) {
+	if err := tx.Create(&meta).Error; err != nil {
+		return menu, err
+	}
+	menu.MetaID = meta.ID
+	err := tx.Create(&menu).Error
+	return menu, err
+}
+
+// createApiMenus 创建接口类型的菜单 返回菜单ID
+func createApiMenus(tx *gorm.DB, pid int64, title string, codes []string) ([]int64, error) {
+	if len(codes) == 0 {
+		return nil, nil
+	}
+	menus := slice.Map(codes, func(_ int, code string) sysMenuV0001 {
+		return sysMenuV0001{
+			Pid:            pid,
+			Type:           _const.MenuTypeApi,
+			APICode:        code,
+			APIDescription: title + codeDescription(code),
+		}
+	})
+	if err := tx.Create(&menus).Error; err != nil {
+		return nil, err
+	}
+	return slice.Map(menus, func(_ int, item sysMenuV0001) int64 {
+		return item.ID
+	}), nil
+}
+
+// codeDescription 根据权限码最后一段生成描述 SYS::DICT::CHILD::QUERY -> 查询(CHILD)
+func codeDescription(code string) string {
+	parts := strings.FieldsFunc(code, func(r rune) bool {
+		return r == ':'
+	})
+	if len(parts) == 0 {
+		return code
+	}
+	action, ok := codeActionName[parts[len(parts)-1]]
+	if !ok {
+		return code
+	}
+	if len(parts) > 3 {
+		return action + "(" + strings.Join(parts[2:len(parts)-1], "::") + ")"
+	}
+	return action
+}
@@ -0,0 +1,115 @@
+package vben
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/super-sunshines/echo-server-core/core"
+	"github.com/super-sunshines/echo-server-core/core/coretest"
+	"github.com/super-sunshines/echo-server-core/vben/gorm/model"
+	"github.com/super-sunshines/echo-server-core/vben/migrations"
+)
+
+// 回收站按数据权限过滤 彻底删除时处理关联的数据
+func TestRecycleBinDataScope(t *testing.T) {
+	server, deps := buildTestServer(t, coretest.NewConfig(t))
+	db := deps.DB.WithContext(core.NewSkipGormGlobalHookContext())
+
+	// 管理员角色只能看到本部门及以下和自己创建的数据
+	if err := db.Model(&model.SysRole{}).Where("code = ?", migrations.AdminRoleCode).Update("query_strategy", 2).Error; err != nil {
+		t.Fatal(err)
+	}
+	var root model.SysDepartment
+	if err := db.Where("pid = 0").First(&root).Error; err != nil {
+		t.Fatal(err)
+	}
+	child := model.SysDepartment{Pid: root.ID, Name: "研发"}
+	other := model.SysDepartment{Name: "外部"}
+	if err := db.Create(&child).Error; err != nil {
+		t.Fatal(err)
+	}
+	alice := createTestUser(t, deps, model.SysUser{Username: "alice", Password: core.HashPassword("alice-pass"),
+		DepartmentID: root.ID, RoleCodeList: core.Array[string]{migrations.AdminRoleCode}})
+	other.CreateBy = alice.ID
+	if err := db.Create(&other).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	deleted := func(username string, createBy, createDept, departmentID int64) model.SysUser {
+		user := createTestUser(t, deps, model.SysUser{Username: username, DepartmentID: departmentID})
+		err := db.Model(&user).UpdateColumns(map[string]any{"create_by": createBy, "create_dept": createDept}).Error
+		if err == nil {
+			err = db.Delete(&user).Error
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		return user
+	}
+	inDepartment := deleted("in-department", 999, child.ID, 0)
+	mine := deleted("mine", alice.ID, other.ID, 0)
+	outside := deleted("outside", 999, other.ID, other.ID)
+	// 没有删除的数据不能出现在回收站
+	live := createTestUser(t, deps, model.SysUser{Username: "live"})
+	if err := db.Model(&live).UpdateColumn("create_by", alice.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&model.SysUserThirdBind{UserID: mine.ID, LoginType: "oidc", Openid: "mine"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	token := loginTestServer(t, server, "alice", "alice-pass")
+	resp := callTestServer(t, server, http.MethodGet, "/system/recycle/user/list?page=1&pageSize=10", token, nil)
+	var page struct {
+		Items []struct {
+			Username string `json:"username"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(resp.Data, &page); err != nil || resp.Code != core.OK {
+		t.Fatalf("recycle list: %v %+v", err, resp)
+	}
+	var usernames []string
+	for _, item := range page.Items {
+		usernames = append(usernames, item.Username)
+	}
+	sort.Strings(usernames)
+	if fmt.Sprint(usernames) != fmt.Sprint([]string{inDepartment.Username, mine.Username}) {
+		t.Fatalf("recycle list got %v", usernames)
+	}
+
+	purge := func(name string, id int64) testResponse {
+		return callTestServer(t, server, http.MethodDelete, fmt.Sprintf("/system/recycle/%s?ids=%d", name, id), token, nil)
+	}
+	// 看不到的数据不会被删除
+	if resp = purge("user", outside.ID); resp.Code != core.OK || string(resp.Data) != "0" {
+		t.Fatalf("purge outside: %+v", resp)
+	}
+	var count int64
+	db.Unscoped().Model(&model.SysUser{}).Where("id = ?", outside.ID).Count(&count)
+	if count != 1 {
+		t.Fatal("user outside the data scope was purged")
+	}
+	// 三方账号跟着用户一起删除
+	if resp = purge("user", mine.ID); resp.Code != core.OK || string(resp.Data) != "1" {
+		t.Fatalf("purge mine: %+v", resp)
+	}
+	db.Model(&model.SysUserThirdBind{}).Where("user_id = ?", mine.ID).Count(&count)
+	if count != 0 {
+		t.Fatal("third bind of the purged user still exists")
+	}
+
+	// 还有用户的部门不能彻底删除
+	if err := db.Delete(&other).Error; err != nil {
+		t.Fatal(err)
+	}
+	if resp = purge("department", other.ID); resp.Code == core.OK {
+		t.Fatalf("purged a department that still has users: %+v", resp)
+	}
+	db.Unscoped().Model(&model.SysDepartment{}).Where("id = ?", other.ID).Count(&count)
+	if count != 1 {
+		t.Fatal("department with users was purged")
+	}
+}
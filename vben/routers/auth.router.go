@@ -1,7 +1,7 @@
 package routers
 
 import (
-	"fmt"
+	"errors"
 	"github.com/labstack/echo/v4"
 	"github.com/super-sunshines/echo-server-core/core"
 	"github.com/super-sunshines/echo-server-core/vben/bo"
@@ -10,47 +10,75 @@ import (
 	"github.com/super-sunshines/echo-server-core/vben/helper"
 	"github.com/super-sunshines/echo-server-core/vben/services"
 	"github.com/super-sunshines/echo-server-core/vben/vo"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"strconv"
 	"time"
 )
 
 var AuthRouterGroup = core.NewRouterGroup("", NewAuthRouter, func(rg *echo.Group, group *core.RouterGroup) error {
 	return group.Reg(func(m *AuthRouter) {
-		rg.POST("/auth/login", m.login, core.IgnorePermission())
+		rg.POST("/auth/login", m.login, core.RateLimit(core.RateLimitOption{
+			Name: "auth:login", Limit: 10, Window: time.Minute,
+			Keys: []core.RateLimitKey{core.RateLimitByIP(), core.RateLimitByBody("username")},
+		}), core.IgnorePermission())
+		rg.POST("/auth/login/2fa", m.loginTwoFactor, core.RateLimit(core.RateLimitOption{Name: "auth:login:2fa", Limit: 10, Window: time.Minute}), core.IgnorePermission())
+		rg.GET("/auth/captcha", m.captcha, core.RateLimit(core.RateLimitOption{Name: "auth:captcha", Limit: 30, Window: time.Minute}), core.IgnorePermission())
 		rg.GET("/auth/codes", m.codes)
 		rg.GET("/auth/check", m.checkToken, core.IgnorePermission())
 		rg.POST("/password/change", m.passwordReset, core.Log("用户重置密码"), core.IgnorePermission())
 		rg.GET("/auth/password/reset/check/:code", m.passWordResetCheck, core.IgnorePermission())
-		rg.POST("/auth/password/reset", m.passWordReset, core.IgnorePermission())
-		rg.POST("/auth/refresh", m.refreshToken, core.IgnorePermission())
+		rg.POST("/auth/password/reset", m.passWordReset, core.RateLimit(core.RateLimitOption{Name: "auth:password:reset", Limit: 5, Window: time.Minute}), core.IgnorePermission())
+		rg.POST("/auth/password/forgot", m.forgotPassword, core.RateLimit(core.RateLimitOption{
+			Name: "auth:password:forgot", Limit: 5, Window: time.Minute,
+			Keys: []core.RateLimitKey{core.RateLimitByIP(), core.RateLimitByBody("username")},
+		}), core.IgnorePermission())
+		rg.POST("/auth/password/forgot/reset", m.forgotPasswordReset, core.RateLimit(core.RateLimitOption{Name: "auth:password:forgot:reset", Limit: 10, Window: time.Minute}), core.IgnorePermission())
+		rg.POST("/auth/refresh", m.refreshToken, core.RateLimit(core.RateLimitOption{Name: "auth:refresh", Limit: 30, Window: time.Minute}), core.IgnorePermission())
 		rg.GET("/auth/logout", m.logout, core.IgnorePermission())
 		rg.GET("/menu/all", m.menu, core.IgnorePermission())
 		rg.GET("/user/info", m.loginUserInfo, core.IgnorePermission())
 		rg.PUT("/user/info", m.updateInfo, core.IgnorePermission())
+		rg.GET("/auth/2fa", m.twoFactorStatus, core.IgnorePermission())
+		rg.POST("/auth/2fa/enroll", m.twoFactorEnroll, core.IgnorePermission())
+		rg.POST("/auth/2fa/verify", m.twoFactorVerify, core.IgnorePermission())
+		rg.POST("/auth/2fa/disable", m.twoFactorDisable, core.Log("关闭两步验证"), core.IgnorePermission())
 	})
 })
 
+// 找回密码验证码的用途
+const forgotPasswordScene = "forgot_password"
+
 type AuthRouter struct {
 	services.SysUserService
-	menuService         core.PreGorm[model.SysMenu, any]
-	userService         core.PreGorm[model.SysUser, any]
-	departmentService   services.SysDepartmentService
-	loginLogService     services.SysLoginInfoService
-	changePasswordCache *core.RedisCache[int64]
+	menuService          core.PreGorm[model.SysMenu, any]
+	userService          core.PreGorm[model.SysUser, any]
+	departmentService    services.SysDepartmentService
+	loginLogService      services.SysLoginInfoService
+	totpService          services.SysUserTotpService
+	passwordService      services.SysUserPasswordService
+	loginProviderService services.SysLoginProviderService
+	loginFlow            loginFlow
 }
 
 func NewAuthRouter() *AuthRouter {
 	return &AuthRouter{
-		SysUserService:      services.NewSysUserService(),
-		userService:         core.NewService[model.SysUser, any](),
-		menuService:         core.NewService[model.SysMenu, any](),
-		loginLogService:     services.NewSysLoginInfoService(),
-		changePasswordCache: core.GetRedisCache[int64]("user:change:password"),
-		departmentService:   services.NewDepartmentService(),
+		SysUserService:       services.NewSysUserService(),
+		userService:          core.NewService[model.SysUser, any](),
+		menuService:          core.NewService[model.SysMenu, any](),
+		loginLogService:      services.NewSysLoginInfoService(),
+		totpService:          services.NewSysUserTotpService(),
+		passwordService:      services.NewSysUserPasswordService(),
+		loginProviderService: services.NewSysLoginProviderService(),
+		loginFlow:            newLoginFlow(),
+		departmentService:    services.NewDepartmentService(),
 	}
 }
 
 // @Summary	用户登录
+// @Description	开启了两步验证的账号返回 needTwoFactor 和 challengeToken 需要再调用 /auth/login/2fa 完成登录
+// @Description	需要验证码时返回错误码 100200 前端调用 /auth/captcha 获取验证码后带上 captchaId 和 captchaCode 重新登录
+// @Description	provider 指定登录方式 默认 local 本地账号 配置了 Ldap.Url 时可以使用 Ldap
 // @Tags		[系统]授权模块
 // @Success	200	{object}	core.ResponseSuccess{data=vo.LoginVo}
 // @Router		/auth/login [post]
@@ -58,57 +86,101 @@ func NewAuthRouter() *AuthRouter {
 func (r AuthRouter) login(ec echo.Context) (err error) {
 	context := core.GetContext[bo.LoginBo](ec)
 	platform := context.GetAppPlatformCode()
-	maxLoginFailCount := core.GetConfig().Jwt.MaxLoginFailCount
 	loginInfo, err := context.GetBodyAndValid()
 	if err != nil {
 		return context.Fail(err)
 	}
-
-	err, a := r.userService.WithContext(context).SkipGlobalHook().FindOne(func(db *gorm.DB) *gorm.DB {
-		return db.Where("username = ?", loginInfo.Username)
-	})
-	if a.EnableStatus == _const.CommonStateBanned || a.LoginFailCount >= maxLoginFailCount {
-		r.loginLogService.AddLog(ec, loginInfo.Username, _const.LoginTypePassword, 2, "账户已锁定，请联系管理员解锁！")
-		return context.Fail(core.NewFrontShowErrMsg("账户已锁定，请联系管理员解锁！"))
-	}
+	provider, err := r.loginProviderService.Get(ec, loginInfo.Provider)
 	if err != nil {
-		r.loginLogService.AddLog(ec, loginInfo.Username, _const.LoginTypePassword, 2, "用户名或者密码错误！")
-		return context.Fail(core.NewFrontShowErrMsg("用户名或者密码错误！"))
+		return context.Fail(err)
 	}
-	if core.ComparePasswords(a.Password, loginInfo.Password) {
-		// 重置登录失败次数
-		r.userService.WithContext(ec).SkipGlobalHook().Where("id = ?", a.ID).UpdateColumns(map[string]any{
-			"login_fail_count": 0,
-			"last_online":      core.GetNowTimeUnixMilli(),
-		})
-		r.loginLogService.AddLog(ec, loginInfo.Username, _const.LoginTypePassword, 1, "登录成功")
-
-		token, err := helper.GenJwtByUserInfo(platform, a)
-		if err != nil {
-			return err
+	loginType := core.BooleanTo(provider.Name() == services.LoginProviderLdap, _const.LoginTypeLdap, _const.LoginTypePassword)
+	// 同一个 IP 或者用户名失败次数过多时需要验证码
+	captchaKeys := []string{"ip:" + ec.RealIP(), "user:" + loginInfo.Username}
+	if core.GetContextCaptchaManager(ec).Required(captchaKeys...) {
+		if err = core.GetContextCaptchaManager(ec).Verify(loginInfo.CaptchaId, loginInfo.CaptchaCode); err != nil {
+			r.loginLogService.AddLog(ec, loginInfo.Username, loginType, 2, "验证码错误！")
+			return context.Fail(err)
 		}
-		loginVo := vo.LoginVo{
-			AccessToken:        token,
-			NeedChangePassword: bool(a.NeedChangePassword),
-		}
-		if a.NeedChangePassword {
-			str := core.GetRandomStr(12)
-			loginVo.ChangePasswordCode = str
-			r.changePasswordCache.Set(context, "sys:user:change:password:"+str, a.ID, 5*time.Minute)
+	}
+
+	identity, err := provider.Authenticate(ec, services.LoginCredential{Username: loginInfo.Username, Password: loginInfo.Password})
+	if err != nil {
+		core.GetContextCaptchaManager(ec).Fail(captchaKeys...)
+		var loginErr services.LoginError
+		if !errors.As(err, &loginErr) {
+			zap.L().Error("登录失败", zap.String("provider", provider.Name()), zap.Error(err))
+			r.loginLogService.AddLog(ec, loginInfo.Username, loginType, 2, "登录服务异常！")
+			return context.Fail(core.NewFrontShowErrMsg("登录服务异常，请稍后重试！"))
 		}
-		return context.Success(loginVo)
-	} else {
-		r.userService.WithContext(ec).SkipGlobalHook().Where("id = ?", a.ID).UpdateColumns(map[string]any{
-			"login_fail_count": gorm.Expr("login_fail_count + 1"),
-		})
-		if a.LoginFailCount+1 >= maxLoginFailCount {
-			r.userService.WithContext(ec).SkipGlobalHook().Where("id = ?", a.ID).UpdateColumns(map[string]any{
-				"enable_status": _const.CommonStateBanned,
-			})
+		if loginErr.UserID != 0 {
+			if err, a := r.userService.WithContext(context).SkipGlobalHook().FindOneByPrimaryKey(loginErr.UserID); err == nil {
+				return r.loginFlow.fail(ec, a, loginType, loginErr.Message)
+			}
 		}
-		r.loginLogService.AddLog(ec, loginInfo.Username, _const.LoginTypePassword, 2, "用户名或者密码错误！"+fmt.Sprintf("尝试第%d次", a.LoginFailCount+1))
-		return context.Fail(core.NewFrontShowErrMsg("用户名或者密码错误！" + fmt.Sprintf("第%d次", a.LoginFailCount+1) + fmt.Sprintf("共%d次", maxLoginFailCount)))
+		r.loginLogService.AddLog(ec, loginInfo.Username, loginType, 2, loginErr.Message)
+		return context.Fail(core.NewFrontShowErrMsg(loginErr.Message))
+	}
+	a, created, err := r.loginProviderService.Resolve(ec, identity)
+	if err != nil {
+		r.loginLogService.AddLog(ec, loginInfo.Username, loginType, 2, err.Error())
+		return context.Fail(err)
+	}
+	core.GetContextCaptchaManager(ec).Reset(captchaKeys...)
+	return r.loginFlow.complete(ec, platform, provider.Name(), loginType, loginInfo.Username, a, created)
+}
+
+// @Summary	获取图片验证码
+// @Tags		[系统]授权模块
+// @Success	200	{object}	core.ResponseSuccess{data=core.Captcha}
+// @Router		/auth/captcha [get]
+func (r AuthRouter) captcha(ec echo.Context) error {
+	context := core.GetContext[any](ec)
+	captcha, err := core.GetContextCaptchaManager(ec).Generate()
+	if err != nil {
+		return context.Fail(err)
+	}
+	return context.Success(captcha)
+}
+
+// @Summary	两步验证登录
+// @Description	密码登录返回 needTwoFactor 时使用 challengeToken 和认证器上的验证码完成登录 也可以使用恢复码
+// @Tags		[系统]授权模块
+// @Success	200	{object}	core.ResponseSuccess{data=vo.LoginVo}
+// @Router		/auth/login/2fa [post]
+// @Param		twoFactorLoginBo	body	bo.TwoFactorLoginBo	true	"验证参数"
+func (r AuthRouter) loginTwoFactor(ec echo.Context) (err error) {
+	context := core.GetContext[bo.TwoFactorLoginBo](ec)
+	param, err := context.GetBodyAndValid()
+	if err != nil {
+		return context.Fail(err)
+	}
+	have, challenge := r.loginFlow.twoFactorCache(ec).XCodeGet(param.ChallengeToken)
+	if !have {
+		r.loginLogService.AddLog(ec, "", _const.LoginTypeTwoFactor, 2, "两步验证已过期")
+		return context.Fail(core.NewFrontShowErrMsg("验证已过期，请重新登录！"))
+	}
+	err, a := r.userService.WithContext(context).SkipGlobalHook().FindOneByPrimaryKey(challenge.UID)
+	if err != nil {
+		r.loginFlow.twoFactorCache(ec).XCodeDel(param.ChallengeToken)
+		r.loginLogService.AddLog(ec, "", _const.LoginTypeTwoFactor, 2, "账户不存在")
+		return context.Fail(core.NewFrontShowErrMsg("验证已过期，请重新登录！"))
+	}
+	if msg := r.loginFlow.lockService.LockedMessage(ec, a); msg != "" {
+		r.loginFlow.twoFactorCache(ec).XCodeDel(param.ChallengeToken)
+		r.loginLogService.AddLog(ec, a.Username, _const.LoginTypeTwoFactor, 2, msg)
+		return context.Fail(core.NewFrontShowErrMsg(msg))
+	}
+	if err = r.totpService.Check(ec, a.ID, param.Code); err != nil {
+		return r.loginFlow.fail(ec, a, _const.LoginTypeTwoFactor, "验证码错误！")
+	}
+	// 验证令牌只能使用一次
+	if !r.loginFlow.twoFactorCache(ec).XCodeDel(param.ChallengeToken) {
+		r.loginLogService.AddLog(ec, a.Username, _const.LoginTypeTwoFactor, 2, "两步验证已过期")
+		return context.Fail(core.NewFrontShowErrMsg("验证已过期，请重新登录！"))
 	}
+	r.loginLogService.AddLog(ec, a.Username, _const.LoginTypeTwoFactor, 1, "两步验证通过，登录成功")
+	return r.loginFlow.success(ec, challenge.Platform, challenge.Provider, a)
 }
 
 // @Summary	检测token
@@ -118,17 +190,35 @@ func (r AuthRouter) login(ec echo.Context) (err error) {
 func (r AuthRouter) checkToken(ec echo.Context) (err error) {
 	context := core.GetContext[bo.LoginBo](ec)
 	uid, err := context.GetLoginUserUid()
-	return context.Success(core.GetTokenManager().ValidToken(uid, context.GetAppPlatformCode(), context.GetUserToken()))
+	return context.Success(core.GetContextTokenManager(ec).ValidToken(uid, context.GetAppPlatformCode(), context.GetUserToken()))
 }
 
-// @Summary	检测token
+// @Summary	刷新token
+// @Description	使用刷新令牌换取新的访问令牌和刷新令牌 旧的刷新令牌立即失效 重复使用会撤销该登录
 // @Tags		[系统]授权模块
-// @Success	200	{object}	core.ResponseSuccess{data=bool}
-// @Router		/auth/check [get]
+// @Success	200	{object}	core.ResponseSuccess{data=core.TokenPair}
+// @Router		/auth/refresh [post]
+// @Param		refreshTokenBo	body	bo.RefreshTokenBo	true	"刷新令牌"
 func (r AuthRouter) refreshToken(ec echo.Context) (err error) {
-	context := core.GetContext[bo.LoginBo](ec)
-	uid, err := context.GetLoginUserUid()
-	return context.Success(core.GetTokenManager().ValidToken(uid, context.GetAppPlatformCode(), context.GetUserToken()))
+	context := core.GetContext[bo.RefreshTokenBo](ec)
+	param, err := context.GetBodyAndValid()
+	if err != nil {
+		return context.Fail(err)
+	}
+	pair, err := core.GetContextTokenManager(ec).RefreshToken(param.RefreshToken, func(uid int64, platform string) (core.ClaimsAdditions, error) {
+		err, user := r.userService.WithContext(ec).SkipGlobalHook().FindOneByPrimaryKey(uid)
+		if err != nil {
+			return core.ClaimsAdditions{}, core.NewErrCode(core.TOKEN_REFRESH_INVALID_ERROR)
+		}
+		if user.EnableStatus == _const.CommonStateBanned {
+			return core.ClaimsAdditions{}, core.NewErrCodeMsg(core.USER_STARTUS_ERROR, "账号已被禁用！")
+		}
+		return helper.UserClaims(platform, user), nil
+	})
+	if err != nil {
+		return context.Fail(err)
+	}
+	return context.Success(pair)
 }
 
 // @Summary	登出
@@ -139,8 +229,15 @@ func (r AuthRouter) refreshToken(ec echo.Context) (err error) {
 func (r AuthRouter) logout(ec echo.Context) (err error) {
 	context := core.GetContext[any](ec)
 	param := context.QueryParam("accessToken")
-	jwt, _ := core.GetTokenManager().ParseJwt(param)
-	core.GetTokenManager().RemoveToken(jwt.UID, context.GetAppPlatformCode())
+	jwt, err := core.GetContextTokenManager(ec).ParseJwt(param)
+	if err != nil {
+		return context.Success(true)
+	}
+	core.GetContextTokenManager(ec).RevokeToken(param)
+	// 只退出当前会话 同一平台其他设备的登录不受影响
+	if !core.GetContextTokenManager(ec).RemoveSession(jwt.SessionId) {
+		core.GetContextTokenManager(ec).RemoveToken(jwt.UID, context.GetAppPlatformCode())
+	}
 	return context.Success(true)
 }
 
@@ -154,7 +251,7 @@ func (r AuthRouter) menu(c echo.Context) error {
 	if err != nil || user.RoleCodes == nil {
 		return context.Fail(err)
 	}
-	menuIds := core.PermissionMange.GetRoleMenuIdList(user.RoleCodes...)
+	menuIds := core.GetContextPermissionMange(c).GetRoleMenuIdList(user.RoleCodes...)
 	service := core.NewService[vo.SysMenuWithMeta, vo.SysMenuWithMeta]()
 	err, metas := service.WithContext(c).SkipGlobalHook().FindList(func(db *gorm.DB) *gorm.DB {
 		return db.Where("id in (?)", menuIds).Where("type in ?", _const.MenuTreeType).Preload("Meta")
@@ -180,7 +277,7 @@ func (r AuthRouter) loginUserInfo(ec echo.Context) (err error) {
 	err, loginUserInfo := r.userService.WithContext(ec).SkipGlobalHook().FindOneByPrimaryKey(user.UID)
 	var HomePath string = "/"
 	if len(user.RoleCodes) > 0 {
-		HomePath = core.PermissionMange.GetRoleHomePath(user.RoleCodes[0])
+		HomePath = core.GetContextPermissionMange(ec).GetRoleHomePath(user.RoleCodes[0])
 	}
 	department := r.departmentService.GetUserDepartment(context)
 	return context.Success(vo.LoginUserInfoVo{
@@ -227,7 +324,7 @@ func (r AuthRouter) passWordResetCheck(c echo.Context) error {
 	if code == "" {
 		return context.Fail(core.NewFrontShowErrMsg("参数错误"))
 	}
-	exists := r.changePasswordCache.Exists(context, "sys:user:change:password:"+code)
+	exists := r.loginFlow.changePasswordCache(c).Exists(context, "sys:user:change:password:"+code)
 	result, err := exists.Result()
 	if err != nil {
 		return err
@@ -247,7 +344,7 @@ func (r AuthRouter) passWordReset(c echo.Context) error {
 		return err
 	}
 	var redisKey = "sys:user:change:password:" + body.Code
-	exists := r.changePasswordCache.Exists(context, redisKey)
+	exists := r.loginFlow.changePasswordCache(c).Exists(context, redisKey)
 	result, err := exists.Result()
 	if err != nil {
 		return err
@@ -256,7 +353,7 @@ func (r AuthRouter) passWordReset(c echo.Context) error {
 		return context.Fail(core.NewFrontShowErrMsg("校验码失效"))
 	}
 
-	get := r.changePasswordCache.Get(context, redisKey)
+	get := r.loginFlow.changePasswordCache(c).Get(context, redisKey)
 	uid, err := get.Int64()
 	if err != nil {
 		return err
@@ -270,13 +367,96 @@ func (r AuthRouter) passWordReset(c echo.Context) error {
 	if !core.ComparePasswords(user.Password, body.OldPassword) {
 		return context.Fail(core.NewFrontShowErrMsg("旧密码错误"))
 	}
+	if err = r.passwordService.Check(c, user, body.NewPassword); err != nil {
+		return context.Fail(err)
+	}
 
+	hashed := core.HashPassword(body.NewPassword)
 	tx := r.userService.WithContext(context).Where("id = ?", uid).Updates(map[string]any{
-		"password":             core.HashPassword(body.NewPassword),
+		"password":             hashed,
 		"need_change_password": core.IntBoolFalse,
 	})
+	if tx.Error == nil {
+		r.passwordService.Record(c, uid, hashed)
+	}
+
+	r.loginFlow.changePasswordCache(c).Del(context, redisKey)
+	return context.Success(tx.RowsAffected > 0)
+}
+
+// @Summary	忘记密码 发送验证码
+// @Description	验证码发送到账号绑定的邮箱或者手机号 账号不存在、没有绑定或者发送失败时同样返回成功 避免暴露账号信息
+// @Tags		[系统]授权模块
+// @Success	200	{object}	core.ResponseSuccess{data=bool}
+// @Router		/auth/password/forgot [post]
+// @Param		bo	body	bo.ForgotPasswordBo	true	"账号和接收方式"
+func (r AuthRouter) forgotPassword(c echo.Context) error {
+	context := core.GetContext[bo.ForgotPasswordBo](c)
+	body, err := context.GetBodyAndValid()
+	if err != nil {
+		return context.Fail(err)
+	}
+	err, user := r.userService.WithContext(context).SkipGlobalHook().FindOne(func(db *gorm.DB) *gorm.DB {
+		return db.Where("username = ?", body.Username)
+	})
+	if err != nil || user.EnableStatus == _const.CommonStateBanned {
+		return context.Success(true)
+	}
+	message := core.NotifyMessage{
+		Channel:  body.Channel,
+		To:       core.BooleanTo(body.Channel == core.NotifyChannelEmail, user.Email, user.Phone),
+		Subject:  "找回密码",
+		Content:  "您正在找回密码，验证码为 {code}，{expire} 分钟内有效。如果不是本人操作，请忽略。",
+		Template: forgotPasswordScene,
+	}
+	if message.To == "" {
+		return context.Success(true)
+	}
+	if err = core.GetContextVerifyCodeManager(c).Send(c.Request().Context(), forgotPasswordScene, strconv.FormatInt(user.ID, 10), message); err != nil {
+		// 发送频繁或者发送失败只记录日志 返回错误会暴露账号是否存在
+		zap.L().Warn("找回密码验证码发送失败", zap.Int64("uid", user.ID), zap.Error(err))
+	}
+	return context.Success(true)
+}
 
-	r.changePasswordCache.Del(context, redisKey)
+// @Summary	忘记密码 使用验证码重置密码
+// @Description	重置成功后账号在所有设备上的登录失效 被锁定或者封禁的账号需要管理员解锁
+// @Tags		[系统]授权模块
+// @Success	200	{object}	core.ResponseSuccess{data=bool}
+// @Router		/auth/password/forgot/reset [post]
+// @Param		bo	body	bo.ForgotPasswordResetBo	true	"验证码和新密码"
+func (r AuthRouter) forgotPasswordReset(c echo.Context) error {
+	context := core.GetContext[bo.ForgotPasswordResetBo](c)
+	body, err := context.GetBodyAndValid()
+	if err != nil {
+		return context.Fail(err)
+	}
+	// 密码策略在查找账号之前校验 账号存不存在返回相同的错误 也不消耗验证码 历史密码要等验证通过之后才能比较
+	if err = core.GetContextValidator(c).CheckPassword(body.NewPassword, body.Username); err != nil {
+		return context.Fail(err)
+	}
+	err, user := r.userService.WithContext(context).SkipGlobalHook().FindOne(func(db *gorm.DB) *gorm.DB {
+		return db.Where("username = ?", body.Username)
+	})
+	if err != nil || user.EnableStatus == _const.CommonStateBanned {
+		return context.Fail(core.NewErrCode(core.VERIFY_CODE_EXPIRED_ERROR))
+	}
+	if err = core.GetContextVerifyCodeManager(c).Verify(forgotPasswordScene, strconv.FormatInt(user.ID, 10), body.Code); err != nil {
+		return context.Fail(err)
+	}
+	if err = r.passwordService.Check(c, user, body.NewPassword); err != nil {
+		return context.Fail(err)
+	}
+	hashed := core.HashPassword(body.NewPassword)
+	tx := r.userService.WithContext(context).SkipGlobalHook().Where("id = ?", user.ID).UpdateColumns(map[string]any{
+		"password":             hashed,
+		"need_change_password": core.IntBoolFalse,
+	})
+	if tx.Error != nil {
+		return context.Fail(tx.Error)
+	}
+	r.passwordService.Record(c, user.ID, hashed)
+	core.GetContextTokenManager(c).RemoveTokenByUid(user.ID)
 	return context.Success(tx.RowsAffected > 0)
 }
 
@@ -305,11 +485,102 @@ func (r AuthRouter) passwordReset(c echo.Context) error {
 		return err
 	}
 	if core.ComparePasswords(sysUser.Password, body.OldPassword) {
+		if err = r.passwordService.Check(c, sysUser, body.NewPassword); err != nil {
+			return context.Fail(err)
+		}
+		hashed := core.HashPassword(body.NewPassword)
 		tx := r.userService.WithContext(c).Where("id = ?", user.UID).Updates(map[string]any{
-			"password": core.HashPassword(body.NewPassword),
+			"password":             hashed,
+			"need_change_password": core.IntBoolFalse,
 		})
+		if tx.Error == nil {
+			r.passwordService.Record(c, user.UID, hashed)
+		}
 		return context.Success(tx.RowsAffected > 0)
 	} else {
 		return core.NewFrontShowErrMsg("旧密码错误！")
 	}
 }
+
+// @Summary	两步验证状态
+// @Tags		[系统]授权模块
+// @Success	200	{object}	core.ResponseSuccess{data=vo.TotpStatusVo}
+// @Router		/auth/2fa [get]
+func (r AuthRouter) twoFactorStatus(c echo.Context) error {
+	context := core.GetContext[any](c)
+	uid, err := context.GetLoginUserUid()
+	if err != nil {
+		return context.Fail(err)
+	}
+	enabled := r.totpService.IsEnabled(c, uid)
+	return context.Success(vo.TotpStatusVo{
+		Enabled:           enabled,
+		RecoveryCodeCount: core.BooleanTo(enabled, r.totpService.RecoveryCodeCount(c, uid), 0),
+	})
+}
+
+// @Summary	获取两步验证密钥
+// @Description	返回密钥和 otpauth:// 链接 使用认证器 App 扫码后调用 /auth/2fa/verify 启用
+// @Tags		[系统]授权模块
+// @Success	200	{object}	core.ResponseSuccess{data=vo.TotpEnrollVo}
+// @Router		/auth/2fa/enroll [post]
+func (r AuthRouter) twoFactorEnroll(c echo.Context) error {
+	context := core.GetContext[any](c)
+	uid, err := context.GetLoginUserUid()
+	if err != nil {
+		return context.Fail(err)
+	}
+	err, user := r.userService.WithContext(c).SkipGlobalHook().FindOneByPrimaryKey(uid)
+	if err != nil {
+		return context.Fail(err)
+	}
+	secret, uri, err := r.totpService.Enroll(c, user)
+	if err != nil {
+		return context.Fail(err)
+	}
+	return context.Success(vo.TotpEnrollVo{Secret: secret, Uri: uri})
+}
+
+// @Summary	启用两步验证
+// @Description	校验认证器上的验证码 通过后启用并返回恢复码 恢复码只返回这一次
+// @Tags		[系统]授权模块
+// @Success	200	{object}	core.ResponseSuccess{data=[]string}
+// @Router		/auth/2fa/verify [post]
+// @Param		bo	body	bo.TotpCodeBo	true	"验证码"
+func (r AuthRouter) twoFactorVerify(c echo.Context) error {
+	context := core.GetContext[bo.TotpCodeBo](c)
+	body, err := context.GetBodyAndValid()
+	if err != nil {
+		return context.Fail(err)
+	}
+	uid, err := context.GetLoginUserUid()
+	if err != nil {
+		return context.Fail(err)
+	}
+	codes, err := r.totpService.Verify(c, uid, body.Code)
+	if err != nil {
+		return context.Fail(err)
+	}
+	return context.Success(codes)
+}
+
+// @Summary	关闭两步验证
+// @Tags		[系统]授权模块
+// @Success	200	{object}	core.ResponseSuccess{data=bool}
+// @Router		/auth/2fa/disable [post]
+// @Param		bo	body	bo.TotpCodeBo	true	"验证码或者恢复码"
+func (r AuthRouter) twoFactorDisable(c echo.Context) error {
+	context := core.GetContext[bo.TotpCodeBo](c)
+	body, err := context.GetBodyAndValid()
+	if err != nil {
+		return context.Fail(err)
+	}
+	uid, err := context.GetLoginUserUid()
+	if err != nil {
+		return context.Fail(err)
+	}
+	if err = r.totpService.Disable(c, uid, body.Code); err != nil {
+		return context.Fail(err)
+	}
+	return context.Success(true)
+}
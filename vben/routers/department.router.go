@@ -7,7 +7,6 @@ import (
 	"github.com/super-sunshines/echo-server-core/vben/gorm/model"
 	"github.com/super-sunshines/echo-server-core/vben/services"
 	"github.com/super-sunshines/echo-server-core/vben/vo"
-	"gorm.io/gorm"
 )
 
 var SysDepartmentRouterGroup = core.NewRouterGroup("/system/department", NewSysDepartmentRouter, func(rg *echo.Group, group *core.RouterGroup) error {
@@ -23,7 +22,7 @@ var SysDepartmentRouterGroup = core.NewRouterGroup("/system/department", NewSysD
 
 type SysDepartmentRouter struct {
 	SysDepartmentService core.PreGorm[model.SysDepartment, vo.SysDepartmentVo]
-	clearCache           func()
+	clearCache           func(c echo.Context)
 }
 
 func NewSysDepartmentRouter() *SysDepartmentRouter {
@@ -46,9 +45,7 @@ func (receiver SysDepartmentRouter) SysDepartmentList(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	err, x := receiver.SysDepartmentService.WithContext(c).SkipGlobalHook().FindVoListByPage(pageBo.PageParam, func(db *gorm.DB) *gorm.DB {
-		return db
-	})
+	err, x := receiver.SysDepartmentService.WithContext(c).SkipGlobalHook().FindVoListByPage(pageBo.PageParam, core.Filter(pageBo), core.Sort(pageBo.OrderParam))
 	if err != nil {
 		return err
 	}
@@ -94,7 +91,7 @@ func (receiver SysDepartmentRouter) SysDepartmentUpdate(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	receiver.clearCache()
+	receiver.clearCache(c)
 	return context.Success(x)
 }
 
@@ -119,7 +116,7 @@ func (receiver SysDepartmentRouter) SysDepartmentAdd(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	receiver.clearCache()
+	receiver.clearCache(c)
 	return context.Success(core.CopyFrom[vo.SysDepartmentVo](meta))
 }
 
@@ -141,7 +138,7 @@ func (receiver SysDepartmentRouter) SysDepartmentDelete(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	receiver.clearCache()
+	receiver.clearCache(c)
 	return context.Success(row)
 }
 
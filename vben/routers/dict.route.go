@@ -54,12 +54,7 @@ func (receiver SysDictRouter) SysDictList(c echo.Context) error {
 		return err
 	}
 	err, x := receiver.SysDictService.WithContext(c).SkipGlobalHook().
-		FindVoListByPage(pageBo.PageParam, func(db *gorm.DB) *gorm.DB {
-			core.BooleanFun(pageBo.Module != 0, func() {
-				db.Where("module = ?", pageBo.Module)
-			})
-			return db
-		})
+		FindVoListByPage(pageBo.PageParam, core.Filter(pageBo), core.Sort(pageBo.OrderParam))
 	if err != nil {
 		return err
 	}
@@ -268,32 +263,32 @@ func (receiver SysDictRouter) SysDictChildUpdate(c echo.Context) error {
 		return err
 	}
 	modelList := core.CopyListFrom[model.SysDictChild](updateBo)
-	receiver.SysDictRedisCache.XHDel(code)
 	newCodes := slice.Map(modelList, func(index int, item model.SysDictChild) int64 {
 		return item.ID
 	})
-
-	_, _ = receiver.SysDictChildService.WithContext(c).DeleteBy(func(db *gorm.DB) *gorm.DB {
-		return db.Where("id NOT IN (?)", slice.Unique(newCodes)).Where("dict_code = ?", code)
-	})
-
-	slice.ForEach(modelList, func(index int, item model.SysDictChild) {
-		if item.ID == 0 {
+	err = core.Transaction(c, func(tx core.TxScope) error {
+		err, _ := receiver.SysDictChildService.WithContext(tx).DeleteBy(func(db *gorm.DB) *gorm.DB {
+			return db.Where("id NOT IN (?)", slice.Unique(newCodes)).Where("dict_code = ?", code)
+		})
+		if err != nil {
+			return err
+		}
+		for _, item := range modelList {
 			item.DictCode = code
-			err, _ := receiver.SysDictChildService.WithContext(c).InsertOne(item)
-			if err != nil {
-				c.Error(err)
-				return
+			if item.ID == 0 {
+				err, _ = receiver.SysDictChildService.WithContext(tx).InsertOne(item)
+			} else {
+				err, _ = receiver.SysDictChildService.WithContext(tx).UpdateByPrimaryKey(item.ID, item)
 			}
-		} else {
-			item.DictCode = code
-			err, _ := receiver.SysDictChildService.WithContext(c).UpdateByPrimaryKey(item.ID, item)
 			if err != nil {
-				c.Error(err)
-				return
+				return err
 			}
-
 		}
+		return nil
 	})
+	if err != nil {
+		return err
+	}
+	receiver.SysDictRedisCache.XHDel(code)
 	return context.Success(true)
 }
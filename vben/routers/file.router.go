@@ -14,8 +14,8 @@ import (
 )
 
 var SysFileRouterGroup = core.NewRouterGroup("", NewSysFileRouter, func(rg *echo.Group, group *core.RouterGroup) error {
-	return group.Reg(func(m *SysFileRouter) {
-		server := core.GetConfig().Server
+	return group.Reg(func(m *SysFileRouter, config *core.Config) {
+		server := config.Server
 		if server.BaseStaticFolder == "" {
 			server.BaseStaticFolder = "./static/"
 		}
@@ -76,7 +76,7 @@ func (r SysFileRouter) buildTargetPath(fullFileName, originalFilename, baseFolde
 // @Param 		file	formData	file	true	"文件"
 // @Param 		fullName	formData	string	true	"文件名"
 func (r SysFileRouter) upload(c echo.Context) error {
-	server := core.GetConfig().Server
+	server := core.GetContextConfig(c).Server
 	context := core.GetAnyContext(c)
 	file, err := c.FormFile("file")
 	fullFileName := c.FormValue("fullName")
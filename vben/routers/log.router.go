@@ -31,7 +31,8 @@ func NewLogRouter() *LogRouter {
 
 // @Summary	操作日志列表
 // @Tags		[系统]日志模块
-// @Success	200	{object}	core.ResponseSuccess{data=core.PageResultList[model.SysLogOperate]}
+// @Description	默认按页码分页 返回 core.PageResultList mode=cursor 时使用游标分页 返回 core.CursorResultList 没有 total 和 lastPage 使用 nextCursor/prevCursor 翻页
+// @Success	200	{object}	core.ResponseSuccess{data=core.PageOrCursorResultList[model.SysLogOperate]}
 // @Router		/system/log/list [GET]
 // @Param		bo	query	bo.SysLogOperatePageBo	true	"请求参数"
 func (r LogRouter) operateLog(ec echo.Context) (err error) {
@@ -40,8 +41,16 @@ func (r LogRouter) operateLog(ec echo.Context) (err error) {
 	if err != nil {
 		return err
 	}
+	if queryParam.IsCursor() {
+		err, list := r.operateLogService.WithContext(ec).SkipGlobalHook().
+			FindVoListByCursor(queryParam.ToCursorParam(), core.CursorSort{Column: "operate_time", Desc: true})
+		if err != nil {
+			return err
+		}
+		return context.Success(list)
+	}
 	err, list := r.operateLogService.WithContext(ec).SkipGlobalHook().
-		FindVoListByPage(queryParam.PageParam, func(db *gorm.DB) *gorm.DB {
+		FindVoListByPage(queryParam.ToPageParam(), func(db *gorm.DB) *gorm.DB {
 			return db.Order("operate_time desc")
 		})
 	if err != nil {
@@ -52,7 +61,8 @@ func (r LogRouter) operateLog(ec echo.Context) (err error) {
 
 // @Summary	登录日志列表
 // @Tags		[系统]日志模块
-// @Success	200	{object}	core.ResponseSuccess{data=core.PageResultList[model.SysLogLogin]}
+// @Description	默认按页码分页 返回 core.PageResultList mode=cursor 时使用游标分页 返回 core.CursorResultList 没有 total 和 lastPage 使用 nextCursor/prevCursor 翻页
+// @Success	200	{object}	core.ResponseSuccess{data=core.PageOrCursorResultList[model.SysLogLogin]}
 // @Router		/system/log/login/list [GET]
 // @Param		bo	query	bo.SysLogLoginPageBo	true	"请求参数"
 func (r LogRouter) loginLog(ec echo.Context) (err error) {
@@ -61,8 +71,16 @@ func (r LogRouter) loginLog(ec echo.Context) (err error) {
 	if err != nil {
 		return err
 	}
+	if queryParam.IsCursor() {
+		err, list := r.loginLogService.WithContext(ec).SkipGlobalHook().
+			FindVoListByCursor(queryParam.ToCursorParam(), core.CursorSort{Column: "operate_time", Desc: true})
+		if err != nil {
+			return err
+		}
+		return context.Success(list)
+	}
 	err, list := r.loginLogService.WithContext(ec).SkipGlobalHook().
-		FindVoListByPage(queryParam.PageParam, func(db *gorm.DB) *gorm.DB {
+		FindVoListByPage(queryParam.ToPageParam(), func(db *gorm.DB) *gorm.DB {
 			return db.Order("operate_time desc")
 		})
 	if err != nil {
@@ -0,0 +1,123 @@
+package routers
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/super-sunshines/echo-server-core/core"
+	_const "github.com/super-sunshines/echo-server-core/vben/const"
+	"github.com/super-sunshines/echo-server-core/vben/gorm/model"
+	"github.com/super-sunshines/echo-server-core/vben/helper"
+	"github.com/super-sunshines/echo-server-core/vben/services"
+	"github.com/super-sunshines/echo-server-core/vben/vo"
+	"gorm.io/gorm"
+	"time"
+)
+
+// 两步验证令牌的有效期
+const twoFactorChallengeExpire = 5 * time.Minute
+
+// twoFactorChallenge 密码验证通过之后等待两步验证的登录
+type twoFactorChallenge struct {
+	UID      int64  `json:"uid"`
+	Platform string `json:"platform"`
+	Provider string `json:"provider"`
+}
+
+// loginFlow 登录方式认证通过并且转换成本地用户之后的公共流程 所有登录方式共用
+// 检查锁定状态 开启了两步验证时先返回验证令牌 最后重置失败次数并签发令牌
+type loginFlow struct {
+	userService     core.PreGorm[model.SysUser, any]
+	loginLogService services.SysLoginInfoService
+	totpService     services.SysUserTotpService
+	passwordService services.SysUserPasswordService
+	lockService     services.SysUserLockService
+}
+
+func newLoginFlow() loginFlow {
+	return loginFlow{
+		userService:     core.NewService[model.SysUser, any](),
+		loginLogService: services.NewSysLoginInfoService(),
+		totpService:     services.NewSysUserTotpService(),
+		passwordService: services.NewSysUserPasswordService(),
+		lockService:     services.NewSysUserLockService(),
+	}
+}
+
+// changePasswordCache 修改密码的临时凭证 存在处理当前请求的 Server 的 Redis 中
+func (f loginFlow) changePasswordCache(ec echo.Context) *core.RedisCache[int64] {
+	return core.GetContextRedisCache[int64](ec, "user:change:password")
+}
+
+// twoFactorCache 等待两步验证的登录
+func (f loginFlow) twoFactorCache(ec echo.Context) *core.RedisCache[twoFactorChallenge] {
+	return core.GetContextRedisCache[twoFactorChallenge](ec, "sys:auth:2fa:challenge:")
+}
+
+// complete 登录方式认证通过之后调用 username 用于登录日志 created 表示本次登录自动创建了用户
+func (f loginFlow) complete(ec echo.Context, platform string, provider string, loginType _const.LoginType, username string, a model.SysUser, created bool) error {
+	context := core.GetAnyContext(ec)
+	username = core.BooleanTo(username == "", a.Username, username)
+	if msg := f.lockService.LockedMessage(ec, a); msg != "" {
+		// 三方账号第一次登录时创建的用户需要管理员开通
+		msg = core.BooleanTo(created, "请通知管理员为您开通账号,识别码:"+a.Username, msg)
+		f.loginLogService.AddLog(ec, username, loginType, 2, msg)
+		return context.Fail(core.NewFrontShowErrMsg(msg))
+	}
+	if f.totpService.IsEnabled(ec, a.ID) {
+		// 登录失败次数在两步验证通过之后才重置 避免只知道密码就能一直尝试验证码
+		challengeToken := core.GetUUID()
+		f.twoFactorCache(ec).XSetCodeEX(challengeToken, twoFactorChallenge{UID: a.ID, Platform: platform, Provider: provider}, twoFactorChallengeExpire)
+		f.loginLogService.AddLog(ec, username, loginType, 1, "认证通过，等待两步验证")
+		return context.Success(vo.LoginVo{NeedTwoFactor: true, ChallengeToken: challengeToken})
+	}
+	f.loginLogService.AddLog(ec, username, loginType, 1, "登录成功")
+	return f.success(ec, platform, provider, a)
+}
+
+// success 重置登录失败次数并签发令牌 provider 是登录方式 只有本地账号检查密码有效期
+func (f loginFlow) success(ec echo.Context, platform string, provider string, a model.SysUser) error {
+	context := core.GetAnyContext(ec)
+	columns := map[string]any{
+		"login_fail_count": 0,
+		"last_online":      core.GetNowTimeUnixMilli(),
+	}
+	local := provider == "" || provider == services.LoginProviderLocal
+	if local && !bool(a.NeedChangePassword) && f.passwordService.Expired(ec, a) {
+		// 密码超过有效期 修改之前每次登录都要求修改密码
+		a.NeedChangePassword = true
+		columns["need_change_password"] = core.IntBoolTrue
+	}
+	f.userService.WithContext(context).SkipGlobalHook().Where("id = ?", a.ID).UpdateColumns(columns)
+	pair, err := helper.GenTokenPairByUserInfo(ec, platform, a)
+	if err != nil {
+		return err
+	}
+	loginVo := vo.LoginVo{
+		AccessToken:        pair.AccessToken,
+		RefreshToken:       pair.RefreshToken,
+		NeedChangePassword: bool(a.NeedChangePassword),
+	}
+	if a.NeedChangePassword {
+		str := core.GetRandomStr(12)
+		loginVo.ChangePasswordCode = str
+		f.changePasswordCache(ec).Set(context, "sys:user:change:password:"+str, a.ID, 5*time.Minute)
+	}
+	return context.Success(loginVo)
+}
+
+// fail 密码或者验证码错误 累计登录失败次数 达到上限后临时锁定账户 并重新开始计数
+func (f loginFlow) fail(ec echo.Context, a model.SysUser, loginType _const.LoginType, msg string) error {
+	context := core.GetAnyContext(ec)
+	maxLoginFailCount := core.GetContextConfig(ec).Jwt.MaxLoginFailCount
+	f.loginLogService.AddLog(ec, a.Username, loginType, 2, msg+fmt.Sprintf("尝试第%d次", a.LoginFailCount+1))
+	if a.LoginFailCount+1 >= maxLoginFailCount {
+		f.userService.WithContext(context).SkipGlobalHook().Where("id = ?", a.ID).UpdateColumns(map[string]any{
+			"login_fail_count": 0,
+		})
+		return context.Fail(core.NewFrontShowErrMsg(f.lockService.Lock(ec, a.ID)))
+	}
+	f.userService.WithContext(context).SkipGlobalHook().Where("id = ?", a.ID).UpdateColumns(map[string]any{
+		"login_fail_count": gorm.Expr("login_fail_count + 1"),
+	})
+	return context.Fail(core.NewFrontShowErrMsg(msg + fmt.Sprintf("第%d次", a.LoginFailCount+1) + fmt.Sprintf("共%d次", maxLoginFailCount)))
+}
@@ -122,14 +122,17 @@ func (r MenuRouter) updateMenu(c echo.Context) error {
 	}
 	fromMenu := core.CopyFrom[model.SysMenu](userMenuBo)
 	fromMenu.MetaID = userMenuBo.Meta.ID
-	err, _ = r.MenuService.WithContext(c).SkipGlobalHook().
-		SaveByPrimaryKey(id, fromMenu)
-	if err != nil {
+	err = core.Transaction(c, func(tx core.TxScope) error {
+		err, _ := r.MenuService.WithContext(tx).SkipGlobalHook().
+			SaveByPrimaryKey(id, fromMenu)
+		if err != nil {
+			return err
+		}
+		menuMeta := core.CopyFrom[model.SysMenuMetum](userMenuBo.Meta)
+		err, _ = r.MenuMetaService.WithContext(tx).SkipGlobalHook().
+			SaveByPrimaryKey(userMenuBo.Meta.ID, menuMeta)
 		return err
-	}
-	menuMeta := core.CopyFrom[model.SysMenuMetum](userMenuBo.Meta)
-	err, _ = r.MenuMetaService.WithContext(c).SkipGlobalHook().
-		SaveByPrimaryKey(userMenuBo.Meta.ID, menuMeta)
+	})
 	if err != nil {
 		return err
 	}
@@ -147,13 +150,16 @@ func (r MenuRouter) addMenu(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	err, meta := r.MenuMetaService.WithContext(c).InsertOne(core.CopyFrom[model.SysMenuMetum](userMenuBo.Meta))
-	if err != nil {
+	err = core.Transaction(c, func(tx core.TxScope) error {
+		err, meta := r.MenuMetaService.WithContext(tx).InsertOne(core.CopyFrom[model.SysMenuMetum](userMenuBo.Meta))
+		if err != nil {
+			return err
+		}
+		menu := core.CopyFrom[model.SysMenu](userMenuBo)
+		menu.MetaID = meta.ID
+		err, _ = r.MenuService.WithContext(tx).InsertOne(menu)
 		return err
-	}
-	menu := core.CopyFrom[model.SysMenu](userMenuBo)
-	menu.MetaID = meta.ID
-	err, _ = r.MenuService.WithContext(c).InsertOne(menu)
+	})
 	if err != nil {
 		return err
 	}
@@ -171,12 +177,18 @@ func (r MenuRouter) deleteMenu(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	r.MenuService.WithContext(c).GetModelDb().Where("pid in (?)", ids).Update("pid", 0)
-	err, meta := r.MenuService.WithContext(c).DeleteByPrimaryKeys(ids)
+	var count int64
+	err = core.Transaction(c, func(tx core.TxScope) error {
+		if err := r.MenuService.WithContext(tx).GetModelDb().Where("pid in (?)", ids).Update("pid", 0).Error; err != nil {
+			return err
+		}
+		err, count = r.MenuService.WithContext(tx).DeleteByPrimaryKeys(ids)
+		return err
+	})
 	if err != nil {
 		return err
 	}
-	return context.Success(meta)
+	return context.Success(count)
 }
 
 // @Summary	简单系统菜单
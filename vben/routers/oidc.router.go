@@ -0,0 +1,86 @@
+package routers
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/super-sunshines/echo-server-core/core"
+	_const "github.com/super-sunshines/echo-server-core/vben/const"
+	"github.com/super-sunshines/echo-server-core/vben/services"
+	"go.uber.org/zap"
+	"time"
+)
+
+var (
+	OidcRouterGroup = core.NewRouterGroup("/oidc", NewOidcAuthRouter, func(rg *echo.Group, group *core.RouterGroup) error {
+		return group.Reg(func(m *OidcAuthRouter) {
+			rg.GET("/:provider/auth-url", m.authUrl, core.RateLimit(core.RateLimitOption{Name: "oidc:auth-url", Limit: 30, Window: time.Minute}), core.IgnorePermission())
+			rg.GET("/:provider/login", m.login, core.RateLimit(core.RateLimitOption{Name: "oidc:login", Limit: 10, Window: time.Minute}), core.IgnorePermission())
+		})
+	})
+)
+
+type OidcAuthRouter struct {
+	loginProviderService services.SysLoginProviderService
+	loginLogService      services.SysLoginInfoService
+	loginFlow            loginFlow
+}
+
+func NewOidcAuthRouter() *OidcAuthRouter {
+	return &OidcAuthRouter{
+		loginProviderService: services.NewSysLoginProviderService(),
+		loginLogService:      services.NewSysLoginInfoService(),
+		loginFlow:            newLoginFlow(),
+	}
+}
+
+// @Summary	获取OIDC授权链接
+// @Description	前端跳转到返回的地址 身份提供方登录之后带着 code 和 state 回到配置的 RedirectUrl
+// @Tags		[系统]三方授权
+// @Success	200	{object}	core.ResponseSuccess{data=string}
+// @Router		/oidc/{provider}/auth-url [get]
+// @Param		provider	path	string	true	"登录方式名称"
+func (r OidcAuthRouter) authUrl(ec echo.Context) error {
+	context := core.GetContext[any](ec)
+	provider, err := r.loginProviderService.GetOidc(ec, context.Param("provider"))
+	if err != nil {
+		return context.Fail(err)
+	}
+	path, err := provider.AuthURL(ec)
+	if err != nil {
+		zap.L().Error("获取OIDC授权链接失败", zap.String("provider", provider.Name()), zap.Error(err))
+		return context.Fail(core.NewFrontShowErrMsg("获取授权链接失败!请联系管理员"))
+	}
+	return context.Success(path)
+}
+
+// @Summary	OIDC登录
+// @Description	和账号密码登录一样 开启了两步验证的账号返回 needTwoFactor 和 challengeToken
+// @Tags		[系统]三方授权
+// @Success	200	{object}	core.ResponseSuccess{data=vo.LoginVo}
+// @Router		/oidc/{provider}/login [get]
+// @Param		provider	path	string	true	"登录方式名称"
+// @Param		code	query	string	true	"授权码"
+// @Param		state	query	string	true	"获取授权链接时生成的state"
+func (r OidcAuthRouter) login(ec echo.Context) error {
+	context := core.GetContext[any](ec)
+	provider, err := r.loginProviderService.GetOidc(ec, context.Param("provider"))
+	if err != nil {
+		return context.Fail(err)
+	}
+	identity, err := provider.Authenticate(ec, services.LoginCredential{Code: context.QueryParam("code"), State: context.QueryParam("state")})
+	if err != nil {
+		var loginErr services.LoginError
+		if errors.As(err, &loginErr) {
+			err = core.NewFrontShowErrMsg(loginErr.Message)
+		}
+		r.loginLogService.AddLog(ec, "", _const.LoginTypeOidc, 2, provider.Name()+"验证失败")
+		return context.Fail(err)
+	}
+	username := identity.Profile.Username
+	useInfo, created, err := r.loginProviderService.Resolve(ec, identity)
+	if err != nil {
+		r.loginLogService.AddLog(ec, username, _const.LoginTypeOidc, 2, err.Error())
+		return context.Fail(err)
+	}
+	return r.loginFlow.complete(ec, context.GetAppPlatformCode(), provider.Name(), _const.LoginTypeOidc, username, useInfo, created)
+}
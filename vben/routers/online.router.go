@@ -0,0 +1,52 @@
+package routers
+
+import (
+	"github.com/duke-git/lancet/v2/slice"
+	"github.com/labstack/echo/v4"
+	"github.com/super-sunshines/echo-server-core/core"
+	"github.com/super-sunshines/echo-server-core/vben/bo"
+	"strings"
+)
+
+var SysOnlineRouterGroup = core.NewRouterGroup("/system/online", NewOnlineRouter, func(rg *echo.Group, group *core.RouterGroup) error {
+	return group.Reg(func(m *OnlineRouter) {
+		rg.GET("/list", m.list, core.HavePermission("SYS::ONLINE::QUERY"))
+		rg.DELETE("/:sessionId", m.kick, core.Log("强制会话下线"), core.HavePermission("SYS::ONLINE::KICK"))
+	})
+})
+
+type OnlineRouter struct {
+}
+
+func NewOnlineRouter() *OnlineRouter {
+	return &OnlineRouter{}
+}
+
+// @Summary	在线会话列表
+// @Description	按最后活跃时间倒序
+// @Tags		[系统]在线用户模块
+// @Success	200	{object}	core.ResponseSuccess{data=core.PageResultList[core.SessionInfo]}
+// @Router		/system/online/list [GET]
+// @Param		bo	query	bo.SysOnlinePageBo	true	"分页参数"
+func (r OnlineRouter) list(c echo.Context) error {
+	context := core.GetContext[bo.SysOnlinePageBo](c)
+	pageBo, err := context.GetQueryParamAndValid()
+	if err != nil {
+		return err
+	}
+	sessions := slice.Filter(core.GetContextTokenManager(c).Sessions(), func(_ int, item core.SessionInfo) bool {
+		return strings.Contains(item.Username, pageBo.Username) &&
+			(pageBo.Platform == "" || item.Platform == pageBo.Platform)
+	})
+	return context.Success(core.NewPageResultListFrom(pageBo.PageParam, sessions))
+}
+
+// @Summary	强制会话下线
+// @Tags		[系统]在线用户模块
+// @Success	200	{object}	core.ResponseSuccess{data=bool}
+// @Router		/system/online/:sessionId [DELETE]
+// @Param		sessionId	path	string	true	"会话ID"
+func (r OnlineRouter) kick(c echo.Context) error {
+	context := core.GetContext[any](c)
+	return context.Success(core.GetContextTokenManager(c).RemoveSession(context.GetPathParam("sessionId")))
+}
@@ -0,0 +1,186 @@
+package routers
+
+import (
+	"fmt"
+	"github.com/duke-git/lancet/v2/slice"
+	"github.com/labstack/echo/v4"
+	"github.com/super-sunshines/echo-server-core/core"
+	"github.com/super-sunshines/echo-server-core/vben/bo"
+	"github.com/super-sunshines/echo-server-core/vben/gorm/model"
+	"github.com/super-sunshines/echo-server-core/vben/services"
+	"github.com/super-sunshines/echo-server-core/vben/vo"
+	"gorm.io/gorm"
+)
+
+var SysRecycleRouterGroup = core.NewRouterGroup("/system/recycle", NewRecycleRouter, func(rg *echo.Group, group *core.RouterGroup) error {
+	return group.Reg(func(m *RecycleRouter) {
+		rg.GET("/types", m.types, core.HavePermission("SYS::RECYCLE::QUERY"))
+		rg.GET("/:name/list", m.list, core.HavePermission("SYS::RECYCLE::QUERY"))
+		rg.PUT("/:name/restore", m.restore, core.Log("回收站恢复"), core.HavePermission("SYS::RECYCLE::RESTORE"))
+		rg.DELETE("/:name", m.purge, core.Log("回收站彻底删除"), core.HavePermission("SYS::RECYCLE::PURGE"))
+	})
+})
+
+type RecycleRouter struct {
+}
+
+// NewRecycleRouter 注册 vben 模块的回收站 业务模块可以调用 core.RegisterRecycleBin 注册自己的数据
+func NewRecycleRouter() *RecycleRouter {
+	roleService := services.NewSysRoleService()
+	departmentService := services.NewDepartmentService()
+	core.RegisterRecycleBin("user", "用户", core.NewService[model.SysUser, vo.SysUserVo](), core.RecycleBinOption{
+		BeforePurge: purgeUserRelations,
+	})
+	core.RegisterRecycleBin("role", "角色", roleService.PreGorm, core.RecycleBinOption{
+		AfterChange: func(c echo.Context) {
+			roleService.RefreshCache(c)
+			_ = core.GetContextPermissionMange(c).Refresh()
+		},
+	})
+	core.RegisterRecycleBin("department", "部门", departmentService.PreGorm, core.RecycleBinOption{
+		BeforePurge: purgeDepartmentRelations,
+		AfterChange: func(c echo.Context) {
+			departmentService.ClearCache(c)
+		},
+	})
+	core.RegisterRecycleBin("dict", "字典", core.NewService[model.SysDict, vo.SysDictVo](), core.RecycleBinOption{
+		BeforePurge: purgeDictRelations,
+	})
+	core.RegisterRecycleBin("menu", "菜单", core.NewService[model.SysMenu, vo.SysMenuWithMetaVo](), core.RecycleBinOption{
+		BeforePurge: purgeMenuRelations,
+	})
+	return &RecycleRouter{}
+}
+
+// recycleDB 检查引用时包括其他人的数据和已经删除的数据 不使用数据权限
+func recycleDB(tx core.TxScope) *gorm.DB {
+	return tx.GetDB().WithContext(core.NewSkipGormGlobalHookContext()).Unscoped()
+}
+
+// purgeUserRelations 用户的三方账号、两步验证、历史密码和部门关系一起删除
+func purgeUserRelations(tx core.TxScope, ids []int64) error {
+	for _, item := range []any{&model.SysUserThirdBind{}, &model.SysUserTotp{}, &model.SysUserPasswordHistory{}, &model.SysUserDepartment{}} {
+		if err := recycleDB(tx).Where("user_id IN ?", ids).Delete(item).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purgeDepartmentRelations 还有用户或者子部门时不能彻底删除 三方部门的对应关系一起删除
+func purgeDepartmentRelations(tx core.TxScope, ids []int64) error {
+	var users, children int64
+	if err := recycleDB(tx).Model(&model.SysUser{}).Where("department_id IN ?", ids).Count(&users).Error; err != nil {
+		return err
+	}
+	if users > 0 {
+		return core.NewFrontShowErrMsg(fmt.Sprintf("还有%d个用户属于该部门，不能彻底删除！", users))
+	}
+	err := recycleDB(tx).Model(&model.SysDepartment{}).Where("pid IN ? AND id NOT IN ?", ids, ids).Count(&children).Error
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return core.NewFrontShowErrMsg("请先删除子部门！")
+	}
+	return recycleDB(tx).Where("department_id IN ?", ids).Delete(&model.SysDepartmentThirdBind{}).Error
+}
+
+// purgeDictRelations 字典项一起删除 已经有同名的新字典时保留字典项
+func purgeDictRelations(tx core.TxScope, ids []int64) error {
+	var codes, alive []string
+	if err := recycleDB(tx).Model(&model.SysDict{}).Where("id IN ?", ids).Pluck("code", &codes).Error; err != nil {
+		return err
+	}
+	err := recycleDB(tx).Model(&model.SysDict{}).Where("code IN ? AND delete_time IS NULL", codes).Pluck("code", &alive).Error
+	if err != nil {
+		return err
+	}
+	codes = slice.Difference(codes, alive)
+	if len(codes) == 0 {
+		return nil
+	}
+	return recycleDB(tx).Where("dict_code IN ?", codes).Delete(&model.SysDictChild{}).Error
+}
+
+// purgeMenuRelations 还有子菜单时不能彻底删除 菜单的 meta 一起删除
+func purgeMenuRelations(tx core.TxScope, ids []int64) error {
+	var children int64
+	err := recycleDB(tx).Model(&model.SysMenu{}).Where("pid IN ? AND id NOT IN ?", ids, ids).Count(&children).Error
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return core.NewFrontShowErrMsg("请先删除子菜单！")
+	}
+	var metaIds []int64
+	if err = recycleDB(tx).Model(&model.SysMenu{}).Where("id IN ?", ids).Pluck("meta_id", &metaIds).Error; err != nil {
+		return err
+	}
+	return recycleDB(tx).Where("id IN ?", metaIds).Delete(&model.SysMenuMetum{}).Error
+}
+
+// @Summary	回收站类型
+// @Tags		[系统]回收站模块
+// @Success	200	{object}	core.ResponseSuccess{data=[]core.RecycleBinItem}
+// @Router		/system/recycle/types [GET]
+func (r RecycleRouter) types(c echo.Context) error {
+	return core.GetContext[any](c).Success(core.RecycleBins())
+}
+
+// @Summary	回收站列表
+// @Tags		[系统]回收站模块
+// @Success	200	{object}	core.ResponseSuccess{data=core.PageResultList[any]}
+// @Router		/system/recycle/:name/list [GET]
+// @Param		name	path	string				true	"回收站类型"
+// @Param		bo		query	bo.SysRecyclePageBo	true	"分页参数"
+func (r RecycleRouter) list(c echo.Context) error {
+	context := core.GetContext[bo.SysRecyclePageBo](c)
+	pageBo, err := context.GetQueryParamAndValid()
+	if err != nil {
+		return err
+	}
+	err, x := core.RecycleBinList(c, context.GetPathParam("name"), pageBo.PageParam)
+	if err != nil {
+		return err
+	}
+	return context.Success(x)
+}
+
+// @Summary	回收站恢复
+// @Tags		[系统]回收站模块
+// @Success	200	{object}	core.ResponseSuccess{data=int}
+// @Router		/system/recycle/:name/restore [PUT]
+// @Param		name	path	string			true	"回收站类型"
+// @Param		bo		query	core.QueryIds	true	"主键"
+func (r RecycleRouter) restore(c echo.Context) error {
+	context := core.GetContext[any](c)
+	ids, err := context.QueryParamIds()
+	if err != nil {
+		return err
+	}
+	err, rows := core.RecycleBinRestore(c, context.GetPathParam("name"), ids)
+	if err != nil {
+		return err
+	}
+	return context.Success(rows)
+}
+
+// @Summary	回收站彻底删除
+// @Tags		[系统]回收站模块
+// @Success	200	{object}	core.ResponseSuccess{data=int}
+// @Router		/system/recycle/:name [DELETE]
+// @Param		name	path	string			true	"回收站类型"
+// @Param		bo		query	core.QueryIds	true	"主键"
+func (r RecycleRouter) purge(c echo.Context) error {
+	context := core.GetContext[any](c)
+	ids, err := context.QueryParamIds()
+	if err != nil {
+		return err
+	}
+	err, rows := core.RecycleBinPurge(c, context.GetPathParam("name"), ids)
+	if err != nil {
+		return err
+	}
+	return context.Success(rows)
+}
@@ -40,7 +40,7 @@ func (r RoleRouter) roleList(ec echo.Context) (err error) {
 		return err
 	}
 	err, roleList := r.roleService.WithContext(ec).SkipGlobalHook().
-		FindVoListByPage(queryParam.PageParam)
+		FindVoListByPage(queryParam.PageParam, core.Filter(queryParam), core.Sort(queryParam.OrderParam))
 	if err != nil {
 		return err
 	}
@@ -86,8 +86,8 @@ func (r RoleRouter) updateRole(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	r.roleService.RefreshCache()
-	_ = core.PermissionMange.Refresh()
+	r.roleService.RefreshCache(c)
+	_ = core.GetContextPermissionMange(c).Refresh()
 	return context.Success(true)
 }
 
@@ -107,8 +107,8 @@ func (r RoleRouter) addRole(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	r.roleService.RefreshCache()
-	_ = core.PermissionMange.Refresh()
+	r.roleService.RefreshCache(c)
+	_ = core.GetContextPermissionMange(c).Refresh()
 	return context.Success(meta)
 }
 
@@ -129,7 +129,7 @@ func (r RoleRouter) deleteRole(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	r.roleService.RefreshCache()
-	_ = core.PermissionMange.Refresh()
+	r.roleService.RefreshCache(c)
+	_ = core.GetContextPermissionMange(c).Refresh()
 	return context.Success(row)
 }
@@ -0,0 +1,135 @@
+package routers
+
+import (
+	"errors"
+	"github.com/duke-git/lancet/v2/slice"
+	"github.com/labstack/echo/v4"
+	"github.com/super-sunshines/echo-server-core/core"
+	"github.com/super-sunshines/echo-server-core/vben/bo"
+	"github.com/super-sunshines/echo-server-core/vben/gorm/model"
+	"github.com/super-sunshines/echo-server-core/vben/services"
+	"github.com/super-sunshines/echo-server-core/vben/vo"
+	"go.uber.org/zap"
+	"time"
+)
+
+var ThirdBindRouterGroup = core.NewRouterGroup("/user", NewThirdBindRouter, func(rg *echo.Group, group *core.RouterGroup) error {
+	return group.Reg(func(m *ThirdBindRouter) {
+		rg.GET("/binds", m.binds, core.IgnorePermission())
+		rg.POST("/bind", m.bind, core.RateLimit(core.RateLimitOption{Name: "user:bind", Limit: 10, Window: time.Minute}), core.Log("绑定三方账号"), core.IgnorePermission())
+		rg.POST("/unbind", m.unbind, core.RateLimit(core.RateLimitOption{Name: "user:unbind", Limit: 10, Window: time.Minute}), core.Log("解绑三方账号"), core.IgnorePermission())
+	})
+})
+
+type ThirdBindRouter struct {
+	userService          core.PreGorm[model.SysUser, any]
+	thirdBindService     services.SysThirdBindService
+	loginProviderService services.SysLoginProviderService
+}
+
+func NewThirdBindRouter() *ThirdBindRouter {
+	return &ThirdBindRouter{
+		userService:          core.NewService[model.SysUser, any](),
+		thirdBindService:     services.NewSysThirdBindService(),
+		loginProviderService: services.NewSysLoginProviderService(),
+	}
+}
+
+// @Summary	我绑定的三方账号
+// @Tags		[系统]三方授权
+// @Success	200	{object}	core.ResponseSuccess{data=[]vo.ThirdBindVo}
+// @Router		/user/binds [get]
+func (r ThirdBindRouter) binds(ec echo.Context) error {
+	context := core.GetContext[any](ec)
+	uid, err := context.GetLoginUserUid()
+	if err != nil {
+		return context.Fail(err)
+	}
+	binds, err := r.thirdBindService.ListByUid(ec, uid)
+	if err != nil {
+		return context.Fail(err)
+	}
+	return context.Success(core.CopyListFrom[vo.ThirdBindVo](binds))
+}
+
+// @Summary	绑定三方账号
+// @Description	provider 指定登录方式 授权码类的登录方式带上 code 和 state 账号密码类的带上 username 和 password
+// @Description	三方账号已经绑定其他用户或者当前用户已经绑定了同一平台的其他账号时拒绝
+// @Tags		[系统]三方授权
+// @Success	200	{object}	core.ResponseSuccess{data=bool}
+// @Router		/user/bind [post]
+// @Param		bo	body	bo.ThirdBindBo	true	"绑定参数"
+func (r ThirdBindRouter) bind(ec echo.Context) error {
+	context := core.GetContext[bo.ThirdBindBo](ec)
+	body, err := context.GetBodyAndValid()
+	if err != nil {
+		return context.Fail(err)
+	}
+	uid, err := context.GetLoginUserUid()
+	if err != nil {
+		return context.Fail(err)
+	}
+	provider, err := r.loginProviderService.Get(ec, body.Provider)
+	if err != nil {
+		return context.Fail(err)
+	}
+	identity, err := provider.Authenticate(ec, services.LoginCredential{
+		Username: body.Username,
+		Password: body.Password,
+		Code:     body.Code,
+		State:    body.State,
+	})
+	if err != nil {
+		var loginErr services.LoginError
+		if errors.As(err, &loginErr) {
+			return context.Fail(core.NewFrontShowErrMsg(loginErr.Message))
+		}
+		zap.L().Error("三方账号认证失败", zap.String("provider", provider.Name()), zap.Error(err))
+		return context.Fail(err)
+	}
+	if err = r.thirdBindService.Bind(ec, uid, identity); err != nil {
+		return context.Fail(err)
+	}
+	return context.Success(true)
+}
+
+// @Summary	解绑三方账号
+// @Description	解绑最后一个三方账号时需要验证登录密码 避免只能通过三方登录的用户解绑之后无法登录
+// @Tags		[系统]三方授权
+// @Success	200	{object}	core.ResponseSuccess{data=bool}
+// @Router		/user/unbind [post]
+// @Param		bo	body	bo.ThirdUnbindBo	true	"解绑参数"
+func (r ThirdBindRouter) unbind(ec echo.Context) error {
+	context := core.GetContext[bo.ThirdUnbindBo](ec)
+	body, err := context.GetBodyAndValid()
+	if err != nil {
+		return context.Fail(err)
+	}
+	uid, err := context.GetLoginUserUid()
+	if err != nil {
+		return context.Fail(err)
+	}
+	binds, err := r.thirdBindService.ListByUid(ec, uid)
+	if err != nil {
+		return context.Fail(err)
+	}
+	others := slice.Filter(binds, func(_ int, item model.SysUserThirdBind) bool {
+		return item.LoginType != body.Platform
+	})
+	if len(others) == 0 && len(binds) > 0 {
+		if body.Password == "" {
+			return context.Fail(core.NewFrontShowErrMsg("解绑最后一个三方账号需要验证登录密码！"))
+		}
+		err, user := r.userService.WithContext(ec).SkipGlobalHook().FindOneByPrimaryKey(uid)
+		if err != nil {
+			return context.Fail(err)
+		}
+		if !core.ComparePasswords(user.Password, body.Password) {
+			return context.Fail(core.NewFrontShowErrMsg("密码错误！"))
+		}
+	}
+	if err = r.thirdBindService.Unbind(ec, uid, body.Platform); err != nil {
+		return context.Fail(err)
+	}
+	return context.Success(true)
+}
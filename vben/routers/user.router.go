@@ -23,18 +23,29 @@ var SysUserRouterGroup = core.NewRouterGroup("/system/user", NewSysUserRouter, f
 		rg.DELETE("", m.SysUserDelete, core.HavePermission("SYS::USER::DEL"), core.Log("删除用户"))
 		rg.PUT("/unlock/:id", m.SysUserUnLock, core.HavePermission("SYS::USER::UNLOCK"), core.Log("解锁用户"))
 		rg.PUT("/lock/:id", m.SysUserLock, core.HavePermission("SYS::USER::LOCK"), core.Log("封禁用户"))
+		rg.PUT("/kick/:id", m.SysUserKick, core.HavePermission("SYS::USER::KICK"), core.Log("强制用户下线"))
+		rg.PUT("/kick/:id/:platform", m.SysUserKickPlatform, core.HavePermission("SYS::USER::KICK"), core.Log("强制用户平台下线"))
+		rg.GET("/:id/binds", m.SysUserBinds, core.HavePermission("SYS::USER::QUERY"), core.Log("查询用户三方账号"))
+		rg.DELETE("/:id/binds/:platform", m.SysUserUnbind, core.HavePermission("SYS::USER::UNBIND"), core.Log("解绑用户三方账号"))
+		rg.PUT("/merge", m.SysUserMerge, core.HavePermission("SYS::USER::MERGE"), core.Log("合并用户"))
 	})
 })
 
 type SysUserRouter struct {
-	SysUserService       core.PreGorm[model.SysUser, vo.SysUserVo]
-	SysDepartmentService services.SysDepartmentService
+	SysUserService         core.PreGorm[model.SysUser, vo.SysUserVo]
+	SysDepartmentService   services.SysDepartmentService
+	SysUserPasswordService services.SysUserPasswordService
+	SysThirdBindService    services.SysThirdBindService
+	SysUserLockService     services.SysUserLockService
 }
 
 func NewSysUserRouter() *SysUserRouter {
 	return &SysUserRouter{
-		SysUserService:       core.NewService[model.SysUser, vo.SysUserVo](),
-		SysDepartmentService: services.NewDepartmentService(),
+		SysUserService:         core.NewService[model.SysUser, vo.SysUserVo](),
+		SysDepartmentService:   services.NewDepartmentService(),
+		SysUserPasswordService: services.NewSysUserPasswordService(),
+		SysThirdBindService:    services.NewSysThirdBindService(),
+		SysUserLockService:     services.NewSysUserLockService(),
 	}
 }
 
@@ -93,11 +104,8 @@ func (receiver SysUserRouter) SysUserList(c echo.Context) error {
 				}
 				db.Where("department_id in (?)", children)
 			})
-			core.BooleanFun(pageBo.SearchKey != "", func() {
-				db.Where("real_name like ?", "%"+pageBo.SearchKey+"%").Or("nick_name like ?", "%"+pageBo.SearchKey+"%")
-			})
 			return db
-		})
+		}, core.Filter(pageBo), core.Sort(pageBo.OrderParam))
 	if err != nil {
 		return err
 	}
@@ -140,14 +148,14 @@ func (receiver SysUserRouter) SysUserUpdate(c echo.Context) error {
 		return err
 	}
 	from := core.CopyFrom[model.SysUser](updateBo)
-	core.BooleanFun(from.EnableStatus == _const.CommonStateBanned, func() {
-		core.GetTokenManager().RemoveTokenByUid(id)
-	})
 	err, x := receiver.SysUserService.WithContext(c).SkipGlobalHook().
 		SaveByPrimaryKey(id, from, "password")
 	if err != nil {
 		return err
 	}
+	core.BooleanFun(from.EnableStatus == _const.CommonStateBanned, func() {
+		core.GetContextTokenManager(c).RemoveTokenByUid(id)
+	})
 	return context.Success(x)
 }
 
@@ -172,6 +180,8 @@ func (receiver SysUserRouter) SysUserAdd(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	// 初始密码也记入历史 首次登录修改密码时不能继续使用
+	receiver.SysUserPasswordService.Record(c, meta.ID, meta.Password)
 	return context.Success(core.CopyFrom[vo.SysUserVo](meta))
 }
 
@@ -193,6 +203,9 @@ func (receiver SysUserRouter) SysUserDelete(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	for _, id := range ids {
+		core.GetContextTokenManager(c).RemoveTokenByUid(id)
+	}
 	return context.Success(row)
 }
 
@@ -212,6 +225,7 @@ func (receiver SysUserRouter) SysUserUnLock(c echo.Context) error {
 		"enable_status":    _const.CommonStateOk,
 		"login_fail_count": 0,
 	})
+	receiver.SysUserLockService.Unlock(c, id)
 	return context.Success(tx.RowsAffected > 0)
 }
 
@@ -234,6 +248,102 @@ func (receiver SysUserRouter) SysUserLock(c echo.Context) error {
 	if tx.RowsAffected == 0 {
 		return core.NewFrontShowErrMsg("封禁失败！")
 	}
+	core.GetContextTokenManager(c).RemoveTokenByUid(id)
+	return context.Success(true)
+}
+
+// SysUserKick
+//
+//	@Summary	强制用户下线
+//	@Description	用户在所有平台的登录都会失效 之前签发的 token 不能再使用
+//	@Tags		[系统]用户模块
+//	@Success	200	{object}	core.ResponseSuccess{data=bool}
+//	@Router		/system/user/kick/:id [put]
+//	@Param		id	path	int	true	"id"
+func (receiver SysUserRouter) SysUserKick(c echo.Context) error {
+	context := core.GetContext[any](c)
+	id, err := context.GetPathParamInt64("id")
+	if err != nil {
+		return err
+	}
+	return context.Success(core.GetContextTokenManager(c).RemoveTokenByUid(id))
+}
+
+// SysUserKickPlatform
+//
+//	@Summary	强制用户平台下线
+//	@Description	只踢出用户在指定平台的登录
+//	@Tags		[系统]用户模块
+//	@Success	200	{object}	core.ResponseSuccess{data=bool}
+//	@Router		/system/user/kick/:id/:platform [put]
+//	@Param		id			path	int		true	"id"
+//	@Param		platform	path	string	true	"平台"
+func (receiver SysUserRouter) SysUserKickPlatform(c echo.Context) error {
+	context := core.GetContext[any](c)
+	id, err := context.GetPathParamInt64("id")
+	if err != nil {
+		return err
+	}
+	return context.Success(core.GetContextTokenManager(c).RemoveToken(id, context.Param("platform")))
+}
+
+// SysUserBinds
+//
+//	@Summary	用户绑定的三方账号
+//	@Tags		[系统]用户模块
+//	@Success	200	{object}	core.ResponseSuccess{data=[]vo.ThirdBindVo}
+//	@Router		/system/user/:id/binds [get]
+//	@Param		id	path	int	true	"id"
+func (receiver SysUserRouter) SysUserBinds(c echo.Context) error {
+	context := core.GetContext[any](c)
+	id, err := context.GetPathParamInt64("id")
+	if err != nil {
+		return err
+	}
+	binds, err := receiver.SysThirdBindService.ListByUid(c, id)
+	if err != nil {
+		return err
+	}
+	return context.Success(core.CopyListFrom[vo.ThirdBindVo](binds))
+}
+
+// SysUserUnbind
+//
+//	@Summary	解绑用户的三方账号
+//	@Tags		[系统]用户模块
+//	@Success	200	{object}	core.ResponseSuccess{data=bool}
+//	@Router		/system/user/:id/binds/:platform [delete]
+//	@Param		id			path	int		true	"id"
+//	@Param		platform	path	string	true	"三方平台"
+func (receiver SysUserRouter) SysUserUnbind(c echo.Context) error {
+	context := core.GetContext[any](c)
+	id, err := context.GetPathParamInt64("id")
+	if err != nil {
+		return err
+	}
+	if err = receiver.SysThirdBindService.Unbind(c, id, context.Param("platform")); err != nil {
+		return context.Fail(err)
+	}
+	return context.Success(true)
+}
+
+// SysUserMerge
+//
+//	@Summary	合并用户
+//	@Description	把三方登录自动创建的重复用户合并到已有的用户 三方绑定转移到目标用户 重复的用户删除并强制下线
+//	@Tags		[系统]用户模块
+//	@Success	200	{object}	core.ResponseSuccess{data=bool}
+//	@Router		/system/user/merge [put]
+//	@Param		bo	body	bo.SysUserMergeBo	true	"合并参数"
+func (receiver SysUserRouter) SysUserMerge(c echo.Context) error {
+	context := core.GetContext[bo.SysUserMergeBo](c)
+	mergeBo, err := context.GetBodyAndValid()
+	if err != nil {
+		return err
+	}
+	if err = receiver.SysThirdBindService.Merge(c, mergeBo.SourceId, mergeBo.TargetId); err != nil {
+		return context.Fail(err)
+	}
 	return context.Success(true)
 }
 
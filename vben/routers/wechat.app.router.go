@@ -1,24 +1,14 @@
 package routers
 
 import (
-	"encoding/json"
-	"fmt"
-	"github.com/go-resty/resty/v2"
 	"github.com/labstack/echo/v4"
 	"github.com/super-sunshines/echo-server-core/core"
-	"github.com/super-sunshines/echo-server-core/vben/bo"
 	_const "github.com/super-sunshines/echo-server-core/vben/const"
-	"github.com/super-sunshines/echo-server-core/vben/gorm/model"
-	"github.com/super-sunshines/echo-server-core/vben/helper"
 	"github.com/super-sunshines/echo-server-core/vben/services"
-	"github.com/super-sunshines/echo-server-core/vben/vo"
-	"gorm.io/gorm"
-	"time"
 )
 
 var (
 	WechatAppRouterGroup = core.NewRouterGroup("/wechat-app", NewWechatAppAuthRouter, func(rg *echo.Group, group *core.RouterGroup) error {
-		services.NewTencentWorkWeChatService()
 		return group.Reg(func(m *WechatAppAuthRouter) {
 			rg.GET("/login", m.login, core.IgnorePermission(), core.Log("微信小程序授权登录"))
 			rg.GET("/review", m.review, core.IgnorePermission())
@@ -27,86 +17,40 @@ var (
 )
 
 type WechatAppAuthRouter struct {
-	userService              core.PreGorm[model.SysUser, any]
 	tencentWorkWeChatService *services.TencentWorkWeChatService
-	thirdBindService         services.SysThirdBindService
-	RequestClient            *resty.Client
+	loginProviderService     services.SysLoginProviderService
+	loginFlow                loginFlow
 }
 
-func NewWechatAppAuthRouter() *WechatAppAuthRouter {
+func NewWechatAppAuthRouter(config *core.Config) *WechatAppAuthRouter {
 	return &WechatAppAuthRouter{
-		userService:              core.NewService[model.SysUser, any](),
-		tencentWorkWeChatService: services.NewTencentWorkWeChatService(),
-		thirdBindService:         services.NewSysThirdBindService(),
-		RequestClient: resty.New().
-			SetRetryCount(3).
-			SetRetryWaitTime(5*time.Second).SetHeader("Content-Type", "application/json"),
+		tencentWorkWeChatService: services.NewTencentWorkWeChatService(config),
+		loginProviderService:     services.NewSysLoginProviderService(),
+		loginFlow:                newLoginFlow(),
 	}
 }
 
 // @Summary	微信小程序登录
 // @Tags		[系统]三方授权
-// @Success	200	{object}	core.ResponseSuccess{data=vo.OauthLoginVo}
+// @Description	开启了两步验证的账号返回 needTwoFactor 和 challengeToken
+// @Success	200	{object}	core.ResponseSuccess{data=vo.LoginVo}
 // @Router		/wechat-app/login [get]
 // @Param		code	query	string	true	"用户code"
 func (r WechatAppAuthRouter) login(ec echo.Context) (err error) {
 	context := core.GetContext[any](ec)
-	var Config = core.GetConfig().Tencent.WechatApp
-	jsCode := context.QueryParam("code")
-	resp, err := r.RequestClient.R().
-		SetQueryParams(
-			map[string]string{
-				"appid":      Config.AppId,
-				"secret":     Config.AppSecret,
-				"js_code":    jsCode,
-				"grant_type": "authorization_code",
-			}).
-		Get("https://api.weixin.qq.com/sns/jscode2session")
+	provider, err := r.loginProviderService.Get(ec, services.LoginProviderWeChatApp)
 	if err != nil {
-		return core.NewFrontShowErrMsg("授权登录失败！")
-	}
-	var result bo.WechatAppServerResp
-	if _ = json.Unmarshal(resp.Body(), &result); result.Errcode != 0 {
-		return core.NewFrontShowErrMsg(fmt.Sprintf("授权登录失败！%s", result.Errmsg))
-	}
-	var code = result.Openid
-	uid, exist := r.thirdBindService.ThirdPlatformUidToUid(_const.ThirdPlatformWeChatApp, code)
-	if !exist {
-		_, userInfo := r.userService.WithContext(context).SkipGlobalHook().InsertOne(model.SysUser{
-			Username:     code,
-			Password:     core.HashPassword(code),
-			NickName:     Config.DefaultNickName,
-			RealName:     Config.DefaultNickName,
-			Avatar:       Config.DefaultAvatar,
-			EnableStatus: _const.CommonStateOk,
-		})
-
-		_err, _ := r.thirdBindService.WithContext(ec).InsertOne(model.SysUserThirdBind{
-			UserID:    userInfo.ID,
-			LoginType: _const.ThirdPlatformWeChatApp,
-			Openid:    code,
-		})
-		if _err != nil {
-			return core.NewFrontShowErrMsg("注册失败!")
-		}
-	}
-	uid, _ = r.thirdBindService.ThirdPlatformUidToUid(_const.ThirdPlatformWeChatApp, code)
-	err, useInfo := r.userService.WithContext(context).SkipGlobalHook().FindOne(func(db *gorm.DB) *gorm.DB {
-		return db.Where("id = ?", uid)
-	})
-	if useInfo.EnableStatus == _const.CommonStateBanned {
-		return core.NewErrCodeMsg(core.USER_STARTUS_ERROR, "账号已被禁用！")
+		return err
 	}
+	identity, err := provider.Authenticate(ec, services.LoginCredential{Code: context.QueryParam("code")})
 	if err != nil {
 		return err
 	}
-	token, err := helper.GenJwtByUserInfo(context.GetAppPlatformCode(), useInfo)
+	useInfo, created, err := r.loginProviderService.Resolve(ec, identity)
 	if err != nil {
-		return err
+		return core.NewFrontShowErrMsg("注册失败!")
 	}
-	return context.Success(vo.OauthLoginVo{
-		AccessToken: token,
-	})
+	return r.loginFlow.complete(ec, context.GetAppPlatformCode(), provider.Name(), _const.LoginTypeWechatApp, "", useInfo, created)
 }
 
 // @Summary	微信小程序是否审核中
@@ -115,6 +59,6 @@ func (r WechatAppAuthRouter) login(ec echo.Context) (err error) {
 // @Router		/wechat-app/review [get]
 func (r WechatAppAuthRouter) review(c echo.Context) error {
 	context := core.GetContext[any](c)
-	config := core.GetConfig().Tencent.WechatApp
+	config := core.GetContextConfig(c).Tencent.WechatApp
 	return context.Success(config.Review)
 }
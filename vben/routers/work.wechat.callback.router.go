@@ -0,0 +1,84 @@
+package routers
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/super-sunshines/echo-server-core/core"
+	"github.com/super-sunshines/echo-server-core/vben/services"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+)
+
+// 回调消息的大小限制 通讯录和审批事件都很小
+const workWechatCallbackMaxBody = 1 << 20
+
+var WorkWechatCallbackRouterGroup = core.NewRouterGroup("/work-wechat", NewWorkWechatCallbackRouter, func(rg *echo.Group, group *core.RouterGroup) error {
+	return group.Reg(func(m *WorkWechatCallbackRouter) error {
+		if err := m.callbackService.Check(); err != nil {
+			return err
+		}
+		rg.GET("/callback", m.verify, core.IgnorePermission())
+		rg.POST("/callback", m.receive, core.IgnorePermission())
+		return nil
+	})
+})
+
+type WorkWechatCallbackRouter struct {
+	callbackService services.WorkWeChatCallbackService
+}
+
+func NewWorkWechatCallbackRouter(config *core.Config) *WorkWechatCallbackRouter {
+	return &WorkWechatCallbackRouter{
+		callbackService: services.NewWorkWeChatCallbackService(config),
+	}
+}
+
+// @Summary	企业微信回调地址验证
+// @Description	企业微信保存回调配置时调用 签名正确时返回解密之后的 echostr
+// @Tags		[系统]三方授权
+// @Success	200	{string}	string
+// @Router		/work-wechat/callback [get]
+// @Param		msg_signature	query	string	true	"签名"
+// @Param		timestamp		query	string	true	"时间戳"
+// @Param		nonce			query	string	true	"随机数"
+// @Param		echostr			query	string	true	"加密的字符串"
+func (r WorkWechatCallbackRouter) verify(ec echo.Context) error {
+	echoStr, err := r.callbackService.VerifyURL(ec.QueryParam("msg_signature"), ec.QueryParam("timestamp"), ec.QueryParam("nonce"), ec.QueryParam("echostr"))
+	if err != nil {
+		zap.L().Warn("企业微信回调地址验证失败", zap.Error(err))
+		return ec.NoContent(http.StatusBadRequest)
+	}
+	return ec.String(http.StatusOK, echoStr)
+}
+
+// @Summary	企业微信回调事件
+// @Description	成员、部门和审批事件解密之后发布到 eventCenter.TencentWorkWeChatEventBus
+// @Description	时间戳超过 5 分钟的请求返回 400 重复的 timestamp 和 nonce 返回 200 不再发布
+// @Tags		[系统]三方授权
+// @Success	200	{string}	string
+// @Router		/work-wechat/callback [post]
+// @Param		msg_signature	query	string	true	"签名"
+// @Param		timestamp		query	string	true	"时间戳"
+// @Param		nonce			query	string	true	"随机数"
+func (r WorkWechatCallbackRouter) receive(ec echo.Context) error {
+	body, err := io.ReadAll(http.MaxBytesReader(ec.Response(), ec.Request().Body, workWechatCallbackMaxBody))
+	if err != nil {
+		return ec.NoContent(http.StatusBadRequest)
+	}
+	message, err := r.callbackService.DecryptMessage(ec, ec.QueryParam("msg_signature"), ec.QueryParam("timestamp"), ec.QueryParam("nonce"), body)
+	if errors.Is(err, services.ErrWorkWeChatCallbackReplay) {
+		// 已经处理过的消息 返回收到 企业微信不会再重试
+		return ec.String(http.StatusOK, "")
+	}
+	if err != nil {
+		zap.L().Warn("企业微信回调消息校验失败", zap.Error(err))
+		return ec.NoContent(http.StatusBadRequest)
+	}
+	if err = r.callbackService.Dispatch(message); err != nil {
+		zap.L().Error("企业微信回调消息解析失败", zap.ByteString("message", message), zap.Error(err))
+		return ec.NoContent(http.StatusBadRequest)
+	}
+	// 不被动回复消息 返回空串表示已经收到
+	return ec.String(http.StatusOK, "")
+}
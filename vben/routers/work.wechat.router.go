@@ -4,109 +4,119 @@ import (
 	"fmt"
 	"github.com/labstack/echo/v4"
 	"github.com/super-sunshines/echo-server-core/core"
+	"github.com/super-sunshines/echo-server-core/vben/bo"
 	_const "github.com/super-sunshines/echo-server-core/vben/const"
 	eventCenter "github.com/super-sunshines/echo-server-core/vben/event"
-	"github.com/super-sunshines/echo-server-core/vben/gorm/model"
-	"github.com/super-sunshines/echo-server-core/vben/helper"
 	"github.com/super-sunshines/echo-server-core/vben/services"
 	"github.com/super-sunshines/echo-server-core/vben/vo"
 	"go.uber.org/zap"
-	"gorm.io/gorm"
 )
 
 var (
 	WorkWechatRouterGroup = core.NewRouterGroup("/work-wechat", NewQywxAuthRouter, func(rg *echo.Group, group *core.RouterGroup) error {
-		services.NewTencentWorkWeChatService()
 		return group.Reg(func(m *QywxAuthRouter) {
 			rg.GET("/login", m.login, core.IgnorePermission())
 			rg.GET("/bind", m.bind, core.IgnorePermission(), core.Log("绑定企业微信"))
 			rg.GET("/auth-url", m.authUrl, core.IgnorePermission())
 			rg.GET("/signature", m.getConfigSignature, core.IgnorePermission())
+			rg.POST("/sync", m.sync, core.HavePermission("SYS::DEPART::SYNC"), core.Log("同步企业微信通讯录"))
 		})
 	})
 )
 
 type QywxAuthRouter struct {
-	userService              core.PreGorm[model.SysUser, any]
 	tencentWorkWeChatService *services.TencentWorkWeChatService
+	loginProviderService     services.SysLoginProviderService
 	thirdBindService         services.SysThirdBindService
+	syncService              services.SysWorkWeChatSyncService
+	loginFlow                loginFlow
 }
 
-func NewQywxAuthRouter() *QywxAuthRouter {
+func NewQywxAuthRouter(config *core.Config) *QywxAuthRouter {
 	return &QywxAuthRouter{
-		userService:              core.NewService[model.SysUser, any](),
-		tencentWorkWeChatService: services.NewTencentWorkWeChatService(),
+		tencentWorkWeChatService: services.NewTencentWorkWeChatService(config),
+		loginProviderService:     services.NewSysLoginProviderService(),
 		thirdBindService:         services.NewSysThirdBindService(),
+		syncService:              services.NewSysWorkWeChatSyncService(),
+		loginFlow:                newLoginFlow(),
 	}
 }
 
 // @Summary	企业微信oauth2登录
+// @Description	开启了两步验证的账号返回 needTwoFactor 和 challengeToken
 // @Tags		[系统]三方授权
-// @Success	200	{object}	core.ResponseSuccess{data=vo.OauthLoginVo}
+// @Success	200	{object}	core.ResponseSuccess{data=vo.LoginVo}
 // @Router		/work-wechat/login [get]
 // @Param		code	query	string	true	"用户code"
 func (r QywxAuthRouter) login(ec echo.Context) (err error) {
 	context := core.GetContext[any](ec)
-	param := context.QueryParam("code")
-	workWechat := core.GetConfig().Tencent.WorkWechat
-	workWechatUserInfo, err := r.tencentWorkWeChatService.UserInfoByCode(param)
+	provider, err := r.loginProviderService.Get(ec, services.LoginProviderWorkWeChat)
 	if err != nil {
-		zap.L().Error("获取用户信息失败", zap.Error(err))
-		return context.Fail(core.NewFrontShowErrMsg("获取用户信息失败!请联系管理员"))
+		return context.Fail(err)
 	}
-	uid, exist := r.thirdBindService.ThirdPlatformUidToUid(_const.ThirdPlatformWorkWeChat, workWechatUserInfo.UserID)
-	if !exist {
-		err, userInfo := r.userService.WithContext(context).SkipGlobalHook().InsertOne(model.SysUser{
-			Username:     workWechatUserInfo.UserID,
-			Password:     core.HashPassword(workWechatUserInfo.UserID),
-			NickName:     workWechatUserInfo.Name,
-			RealName:     workWechatUserInfo.Name,
-			RoleCodeList: workWechat.DefaultRoles,
-			EnableStatus: int64(core.BooleanTo(workWechat.AutoRegister, _const.CommonStateOk, _const.CommonStateBanned)),
-		})
-
-		err, _ = r.thirdBindService.WithContext(ec).InsertOne(model.SysUserThirdBind{
-			UserID:    userInfo.ID,
-			LoginType: _const.ThirdPlatformWorkWeChat,
-			Openid:    workWechatUserInfo.UserID,
-		})
-		if err != nil {
-			return err
-		}
+	identity, err := provider.Authenticate(ec, services.LoginCredential{Code: context.QueryParam("code")})
+	if err != nil {
+		return context.Fail(err)
+	}
+	useInfo, created, err := r.loginProviderService.Resolve(ec, identity)
+	if err != nil {
+		zap.L().Error("获取用户失败", zap.Error(err))
+		return context.Fail(err)
+	}
+	if created {
 		eventCenter.TencentWorkWeChatEventBus.Publish(eventCenter.TencentWorkWeChatNewUserEventBusKey, eventCenter.TencentWorkWeChatNewUserEventBusData{
-			SysUid:           userInfo.ID,
-			WorkWechatName:   workWechatUserInfo.Name,
-			WorkWechatUserId: workWechatUserInfo.UserID,
+			SysUid:           useInfo.ID,
+			WorkWechatName:   identity.Profile.NickName,
+			WorkWechatUserId: identity.OpenID,
 		})
-		if !workWechat.AutoRegister {
-			return core.NewFrontShowErrMsg("请通知管理员为您开通账号,识别码:" + workWechatUserInfo.UserID)
-		}
 	}
+	return r.loginFlow.complete(ec, context.GetAppPlatformCode(), provider.Name(), _const.LoginTypeQywx, "", useInfo, created)
+}
 
-	uid, _ = r.thirdBindService.ThirdPlatformUidToUid(_const.ThirdPlatformWorkWeChat, workWechatUserInfo.UserID)
-	err, useInfo := r.userService.WithContext(context).SkipGlobalHook().FindOne(func(db *gorm.DB) *gorm.DB {
-		return db.Where("id = ?", uid)
-	})
+// @Summary	企业微信绑定
+// @Description	把企业微信账号绑定到当前登录的用户 企业微信账号已经绑定其他用户时拒绝
+// @Tags		[系统]三方授权
+// @Success	200	{object}	core.ResponseSuccess{data=bool}
+// @Router		/work-wechat/bind [get]
+// @Param		code	query	string	true	"用户code"
+func (r QywxAuthRouter) bind(ec echo.Context) (err error) {
+	context := core.GetContext[any](ec)
+	uid, err := context.GetLoginUserUid()
 	if err != nil {
-		zap.L().Error("获取")
-		return err
+		return context.Fail(err)
 	}
-	token, err := helper.GenJwtByUserInfo(context.GetAppPlatformCode(), useInfo)
+	provider, err := r.loginProviderService.Get(ec, services.LoginProviderWorkWeChat)
 	if err != nil {
-		return err
+		return context.Fail(err)
 	}
-	return context.Success(vo.OauthLoginVo{
-		AccessToken: token,
-	})
+	identity, err := provider.Authenticate(ec, services.LoginCredential{Code: context.QueryParam("code")})
+	if err != nil {
+		return context.Fail(err)
+	}
+	if err = r.thirdBindService.Bind(ec, uid, identity); err != nil {
+		return context.Fail(err)
+	}
+	return context.Success(true)
 }
 
-// @Summary	企业微信绑定
+// @Summary	同步企业微信通讯录
+// @Description	拉取企业微信的部门和成员同步到本地 dryRun 为 true 时只返回变化不写入
+// @Description	企业微信中禁用、离职或者不在同步范围内的成员会被禁用并强制下线
 // @Tags		[系统]三方授权
-// @Success	200	{object}	core.ResponseSuccess{data=string}
-// @Router		/work-wechat/bind [post]
-func (r QywxAuthRouter) bind(ec echo.Context) (err error) {
-	context := core.GetContext[any](ec)
-	return context.Fail(core.NewFrontShowErrMsg("暂未实现！"))
+// @Success	200	{object}	core.ResponseSuccess{data=vo.WorkWechatSyncVo}
+// @Router		/work-wechat/sync [post]
+// @Param		bo	body	bo.WorkWechatSyncBo	false	"同步参数"
+func (r QywxAuthRouter) sync(ec echo.Context) (err error) {
+	context := core.GetContext[bo.WorkWechatSyncBo](ec)
+	syncBo, err := context.GetBodyAndValid()
+	if err != nil {
+		return context.Fail(err)
+	}
+	result, err := r.syncService.Sync(ec, syncBo.DryRun)
+	if err != nil {
+		return context.Fail(err)
+	}
+	return context.Success(result)
 }
 
 // @Summary	获取授权链接
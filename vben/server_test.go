@@ -0,0 +1,132 @@
+package vben
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/super-sunshines/echo-server-core/core"
+	"github.com/super-sunshines/echo-server-core/core/coretest"
+	"github.com/super-sunshines/echo-server-core/vben/gorm/model"
+	"github.com/super-sunshines/echo-server-core/vben/hooks"
+)
+
+// newTestServer 使用 vben 的路由和迁移 Build 一个 Server 并创建一个本地账号
+func newTestServer(t *testing.T, cfg core.Config, username, password string) *httptest.Server {
+	t.Helper()
+	server, deps := buildTestServer(t, cfg)
+	createTestUser(t, deps, model.SysUser{Username: username, Password: core.HashPassword(password)})
+	return server
+}
+
+// buildTestServer 返回 Server 的依赖 用来直接准备测试数据
+func buildTestServer(t *testing.T, cfg core.Config) (*httptest.Server, *core.ServerDeps) {
+	t.Helper()
+	s := core.NewServerFromConfig(cfg, BaseRouters, core.ServerRunOption{
+		GormOptions:        core.InitGormOptions{GormGlobalHook: hooks.GlobalGormHook},
+		PermissionsOptions: hooks.RolePermissionHook,
+		Migrations:         Migrations,
+	})
+	e, deps, err := s.Build()
+	if err != nil {
+		t.Fatalf("build server: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+	return server, deps
+}
+
+// createTestUser 不经过全局钩子创建用户 没有设置昵称时使用账号
+func createTestUser(t *testing.T, deps *core.ServerDeps, user model.SysUser) model.SysUser {
+	t.Helper()
+	user.NickName = core.BooleanTo(user.NickName == "", user.Username, user.NickName)
+	user.EnableStatus = 1
+	if err := deps.DB.WithContext(core.NewSkipGormGlobalHookContext()).Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+type testResponse struct {
+	Code uint32          `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+}
+
+func callTestServer(t *testing.T, server *httptest.Server, method, path, token string, body any) testResponse {
+	t.Helper()
+	raw, _ := json.Marshal(body)
+	req, _ := http.NewRequest(method, server.URL+path, bytes.NewReader(raw))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set(core.Authorization, "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	var result testResponse
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("%s %s: decode response: %v", method, path, err)
+	}
+	return result
+}
+
+func loginTestServer(t *testing.T, server *httptest.Server, username, password string) string {
+	t.Helper()
+	resp := callTestServer(t, server, http.MethodPost, "/auth/login", "", map[string]string{"username": username, "password": password})
+	var login struct {
+		AccessToken string `json:"accessToken"`
+	}
+	_ = json.Unmarshal(resp.Data, &login)
+	if login.AccessToken == "" {
+		t.Fatalf("login %s: %+v", username, resp)
+	}
+	return login.AccessToken
+}
+
+// 两个 Server 的配置、密钥、Redis 和数据库互不影响
+func TestServersUseTheirOwnDeps(t *testing.T) {
+	cfgA := coretest.NewConfig(t)
+	cfgA.Jwt.JwtKey = "server-a"
+	cfgA.Password.MinLength = 6
+	cfgB := coretest.NewConfig(t)
+	cfgB.Jwt.JwtKey = "server-b"
+	cfgB.Password.MinLength = 12
+
+	serverA := newTestServer(t, cfgA, "alice", "alice-pass")
+	serverB := newTestServer(t, cfgB, "bob", "bob-password")
+
+	tokenA := loginTestServer(t, serverA, "alice", "alice-pass")
+	tokenB := loginTestServer(t, serverB, "bob", "bob-password")
+	// 账号只存在于创建它的 Server 的数据库中
+	if resp := callTestServer(t, serverB, http.MethodPost, "/auth/login", "", map[string]string{"username": "alice", "password": "alice-pass"}); resp.Code == core.OK {
+		t.Fatalf("server b accepted a user of server a: %+v", resp)
+	}
+
+	// 令牌只能在签发它的 Server 上使用
+	if resp := callTestServer(t, serverA, http.MethodGet, "/user/info", tokenA, nil); resp.Code != core.OK {
+		t.Fatalf("server a rejected its own token: %+v", resp)
+	}
+	if resp := callTestServer(t, serverB, http.MethodGet, "/user/info", tokenA, nil); resp.Code == core.OK {
+		t.Fatalf("server b accepted a token of server a: %+v", resp)
+	}
+	if resp := callTestServer(t, serverA, http.MethodGet, "/user/info", tokenB, nil); resp.Code == core.OK {
+		t.Fatalf("server a accepted a token of server b: %+v", resp)
+	}
+
+	// 密码策略使用各自的配置
+	change := func(server *httptest.Server, token, old string) testResponse {
+		return callTestServer(t, server, http.MethodPost, "/password/change", token, map[string]string{"oldPassword": old, "newPassword": "new-pass9"})
+	}
+	if resp := change(serverA, tokenA, "alice-pass"); resp.Code != core.OK {
+		t.Fatalf("server a rejected a password allowed by its policy: %+v", resp)
+	}
+	if resp := change(serverB, tokenB, "bob-password"); resp.Code == core.OK {
+		t.Fatalf("server b accepted a password shorter than its policy: %+v", resp)
+	}
+}
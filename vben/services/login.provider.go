@@ -0,0 +1,161 @@
+package services
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/super-sunshines/echo-server-core/core"
+	_const "github.com/super-sunshines/echo-server-core/vben/const"
+	"github.com/super-sunshines/echo-server-core/vben/gorm/model"
+	"gorm.io/gorm"
+	"sync"
+)
+
+// 登录方式的名称 三方登录方式和 SysUserThirdBind.LoginType 相同
+const (
+	LoginProviderLocal      = "local"
+	LoginProviderLdap       = _const.ThirdPlatformLdap
+	LoginProviderWorkWeChat = _const.ThirdPlatformWorkWeChat
+	LoginProviderWeChat     = _const.ThirdPlatformWeChat
+	LoginProviderWeChatApp  = _const.ThirdPlatformWeChatApp
+)
+
+// LoginCredential 登录凭证 账号密码类的登录方式使用 Username 和 Password 授权码类的使用 Code
+// OIDC 回调时还需要带上发起登录时的 State
+type LoginCredential struct {
+	Username string
+	Password string
+	Code     string
+	State    string
+}
+
+// LoginIdentity 登录方式认证通过之后得到的身份 由 SysLoginProviderService.Resolve 转换成本地用户
+type LoginIdentity struct {
+	UserID       int64         // 已经确定的本地用户 例如本地账号 不为 0 时忽略其它字段
+	Platform     string        // 三方平台 对应 SysUserThirdBind.LoginType
+	OpenID       string        // 三方唯一标识 对应 SysUserThirdBind.Openid
+	Profile      model.SysUser // 自动创建用户时使用的信息 Sync 时也用它更新已有的用户
+	RoleCodes    []string      // 三方映射出来的角色 为 nil 时不修改已有用户的角色
+	AutoRegister bool          // 第一次登录时自动创建可用的用户 否则创建禁用的用户等待管理员开通
+	Sync         bool          // 每次登录时用三方的信息更新已绑定的用户
+}
+
+// LoginError 凭证错误 Message 可以直接展示给用户 UserID 不为 0 时累计该用户的登录失败次数
+type LoginError struct {
+	UserID  int64
+	Message string
+}
+
+func (e LoginError) Error() string {
+	return e.Message
+}
+
+// LoginProvider 登录方式 自定义的登录方式通过 RegisterLoginProvider 注册
+type LoginProvider interface {
+	// Name 登录方式的名称 对应登录参数中的 provider
+	Name() string
+	// Authenticate 校验凭证 凭证错误时返回 LoginError
+	Authenticate(c echo.Context, credential LoginCredential) (LoginIdentity, error)
+}
+
+var customLoginProviders []LoginProvider
+
+// RegisterLoginProvider 注册自定义的登录方式 需要在服务启动之前调用 同名时覆盖内置的登录方式
+func RegisterLoginProvider(provider LoginProvider) {
+	customLoginProviders = append(customLoginProviders, provider)
+}
+
+// loginProviders 每个 Server 的配置对应的登录方式 第一次使用时按配置创建
+var loginProviders sync.Map
+
+type SysLoginProviderService struct {
+	userService      core.PreGorm[model.SysUser, any]
+	thirdBindService SysThirdBindService
+}
+
+func NewSysLoginProviderService() SysLoginProviderService {
+	return SysLoginProviderService{
+		userService:      core.NewService[model.SysUser, any](),
+		thirdBindService: NewSysThirdBindService(),
+	}
+}
+
+// providers 处理当前请求的 Server 配置的登录方式 LDAP 和 OIDC 按 Server 的配置创建
+func (s SysLoginProviderService) providers(c echo.Context) map[string]LoginProvider {
+	config := core.GetContextConfig(c)
+	if providers, ok := loginProviders.Load(config); ok {
+		return providers.(map[string]LoginProvider)
+	}
+	providers := map[string]LoginProvider{}
+	builtin := []LoginProvider{NewLocalLoginProvider(), NewWorkWeChatLoginProvider(), NewWeChatLoginProvider(), NewWeChatAppLoginProvider()}
+	if config.Ldap.Url != "" {
+		builtin = append(builtin, NewLdapLoginProvider(config.Ldap))
+	}
+	for _, oidcConfig := range config.Oidc {
+		builtin = append(builtin, NewOidcLoginProvider(oidcConfig))
+	}
+	for _, provider := range append(builtin, customLoginProviders...) {
+		providers[provider.Name()] = provider
+	}
+	actual, _ := loginProviders.LoadOrStore(config, providers)
+	return actual.(map[string]LoginProvider)
+}
+
+// Get 按名称获取登录方式 为空时使用本地账号
+func (s SysLoginProviderService) Get(c echo.Context, name string) (LoginProvider, error) {
+	if name == "" {
+		name = LoginProviderLocal
+	}
+	provider, ok := s.providers(c)[name]
+	if !ok {
+		return nil, core.NewFrontShowErrMsg("不支持的登录方式！")
+	}
+	return provider, nil
+}
+
+// GetOidc 按名称获取 OIDC 登录方式 其他登录方式不能通过 OIDC 的接口登录
+func (s SysLoginProviderService) GetOidc(c echo.Context, name string) (*OidcLoginProvider, error) {
+	provider, ok := s.providers(c)[name].(*OidcLoginProvider)
+	if !ok {
+		return nil, core.NewFrontShowErrMsg("不支持的登录方式！")
+	}
+	return provider, nil
+}
+
+// Resolve 把登录方式认证通过的身份转换成本地用户 三方身份交给 SysThirdBindService.FindOrProvision
+func (s SysLoginProviderService) Resolve(c echo.Context, identity LoginIdentity) (user model.SysUser, created bool, err error) {
+	if identity.UserID != 0 {
+		err, user = s.userService.WithContext(c).SkipGlobalHook().FindOneByPrimaryKey(identity.UserID)
+		return user, false, err
+	}
+	return s.thirdBindService.FindOrProvision(c, identity)
+}
+
+// LocalLoginProvider 本地账号密码登录
+type LocalLoginProvider struct {
+	userService core.PreGorm[model.SysUser, any]
+	lockService SysUserLockService
+}
+
+func NewLocalLoginProvider() LocalLoginProvider {
+	return LocalLoginProvider{userService: core.NewService[model.SysUser, any](), lockService: NewSysUserLockService()}
+}
+
+func (p LocalLoginProvider) Name() string {
+	return LoginProviderLocal
+}
+
+// Authenticate 先检查锁定状态再比较密码 锁定之后不再透露密码是否正确
+func (p LocalLoginProvider) Authenticate(c echo.Context, credential LoginCredential) (LoginIdentity, error) {
+	err, a := p.userService.WithContext(c).SkipGlobalHook().FindOne(func(db *gorm.DB) *gorm.DB {
+		return db.Where("username = ?", credential.Username)
+	})
+	if err != nil {
+		return LoginIdentity{}, LoginError{Message: "用户名或者密码错误！"}
+	}
+	if msg := p.lockService.LockedMessage(c, a); msg != "" {
+		return LoginIdentity{}, LoginError{Message: msg}
+	}
+	if !core.ComparePasswords(a.Password, credential.Password) {
+		return LoginIdentity{}, LoginError{UserID: a.ID, Message: "用户名或者密码错误！"}
+	}
+	return LoginIdentity{UserID: a.ID}, nil
+}
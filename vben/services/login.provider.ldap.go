@@ -0,0 +1,240 @@
+package services
+
+import (
+	"crypto/tls"
+	"encoding/hex"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/labstack/echo/v4"
+	"github.com/super-sunshines/echo-server-core/core"
+	"github.com/super-sunshines/echo-server-core/vben/gorm/model"
+	"go.uber.org/zap"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	defaultLdapTimeout     int64 = 10
+	defaultLdapUserFilter        = "(uid={username})"
+	defaultLdapGroupFilter       = "(member={dn})"
+)
+
+// LdapLoginProvider LDAP 和 Active Directory 登录
+// 先用服务账号按 UserFilter 搜索用户 再用用户的 DN 和密码绑定校验密码
+type LdapLoginProvider struct {
+	config core.LdapConfig
+}
+
+func NewLdapLoginProvider(config core.LdapConfig) LdapLoginProvider {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultLdapTimeout
+	}
+	if config.UserFilter == "" {
+		config.UserFilter = defaultLdapUserFilter
+	}
+	if config.GroupFilter == "" {
+		config.GroupFilter = defaultLdapGroupFilter
+	}
+	attributes := &config.Attributes
+	attributes.Username = core.BooleanTo(attributes.Username == "", "uid", attributes.Username)
+	attributes.NickName = core.BooleanTo(attributes.NickName == "", "displayName", attributes.NickName)
+	attributes.RealName = core.BooleanTo(attributes.RealName == "", "cn", attributes.RealName)
+	attributes.Email = core.BooleanTo(attributes.Email == "", "mail", attributes.Email)
+	attributes.Phone = core.BooleanTo(attributes.Phone == "", "mobile", attributes.Phone)
+	return LdapLoginProvider{config: config}
+}
+
+func (p LdapLoginProvider) Name() string {
+	return LoginProviderLdap
+}
+
+// Authenticate 校验账号密码 组按 GroupRoles 映射成角色 每次登录都同步用户信息
+func (p LdapLoginProvider) Authenticate(_ echo.Context, credential LoginCredential) (LoginIdentity, error) {
+	// 空密码在很多服务器上会被当成匿名绑定直接成功
+	if credential.Username == "" || credential.Password == "" {
+		return LoginIdentity{}, LoginError{Message: "用户名或者密码错误！"}
+	}
+	conn, err := p.dial()
+	if err != nil {
+		zap.L().Error("连接LDAP失败", zap.Error(err))
+		return LoginIdentity{}, err
+	}
+	defer conn.Close()
+	if err = p.serviceBind(conn); err != nil {
+		zap.L().Error("LDAP服务账号绑定失败", zap.Error(err))
+		return LoginIdentity{}, err
+	}
+	entry, err := p.searchUser(conn, credential.Username)
+	if err != nil {
+		return LoginIdentity{}, err
+	}
+	// 用服务账号查询组 用户绑定之后可能没有查询组的权限
+	groups, err := p.searchGroups(conn, entry, credential.Username)
+	if err != nil {
+		zap.L().Error("查询LDAP用户组失败", zap.String("dn", entry.DN), zap.Error(err))
+		return LoginIdentity{}, err
+	}
+	if err = conn.Bind(entry.DN, credential.Password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return LoginIdentity{}, LoginError{Message: "用户名或者密码错误！"}
+		}
+		return LoginIdentity{}, err
+	}
+	attributes := p.config.Attributes
+	username := core.BooleanTo(entry.GetAttributeValue(attributes.Username) == "", credential.Username, entry.GetAttributeValue(attributes.Username))
+	profile := model.SysUser{
+		Username:     username,
+		NickName:     entry.GetAttributeValue(attributes.NickName),
+		RealName:     entry.GetAttributeValue(attributes.RealName),
+		Email:        entry.GetAttributeValue(attributes.Email),
+		RoleCodeList: p.config.DefaultRoles,
+	}
+	profile.NickName = core.BooleanTo(profile.NickName == "", profile.RealName, profile.NickName)
+	// 手机号字段只有 11 位 格式不同的号码不同步
+	if phone := entry.GetAttributeValue(attributes.Phone); len(phone) <= 11 {
+		profile.Phone = phone
+	}
+	return LoginIdentity{
+		Platform:     LoginProviderLdap,
+		OpenID:       p.entryID(entry),
+		Profile:      profile,
+		RoleCodes:    p.mapRoles(groups),
+		AutoRegister: p.config.AutoRegister,
+		Sync:         true,
+	}, nil
+}
+
+func (p LdapLoginProvider) dial() (*ldap.Conn, error) {
+	timeout := time.Duration(p.config.Timeout) * time.Second
+	tlsConfig := &tls.Config{InsecureSkipVerify: p.config.InsecureSkipVerify}
+	if u, err := url.Parse(p.config.Url); err == nil {
+		tlsConfig.ServerName = u.Hostname()
+	}
+	conn, err := ldap.DialURL(p.config.Url, ldap.DialWithDialer(&net.Dialer{Timeout: timeout}), ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(timeout)
+	if p.config.StartTLS {
+		if err = conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// serviceBind 使用服务账号绑定 没有配置时匿名查询
+func (p LdapLoginProvider) serviceBind(conn *ldap.Conn) error {
+	if p.config.BindDN == "" {
+		return nil
+	}
+	return conn.Bind(p.config.BindDN, p.config.BindPassword)
+}
+
+// searchUser 按登录账号搜索用户 找不到或者找到多个都视为账号错误
+func (p LdapLoginProvider) searchUser(conn *ldap.Conn, username string) (*ldap.Entry, error) {
+	attributes := p.config.Attributes
+	names := []string{"memberOf", attributes.Username, attributes.NickName, attributes.RealName, attributes.Email, attributes.Phone}
+	if attributes.ID != "" {
+		names = append(names, attributes.ID)
+	}
+	filter := strings.ReplaceAll(p.config.UserFilter, "{username}", ldap.EscapeFilter(username))
+	result, err := conn.Search(ldap.NewSearchRequest(
+		p.config.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, int(p.config.Timeout), false,
+		filter, names, nil,
+	))
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		zap.L().Error("搜索LDAP用户失败", zap.String("filter", filter), zap.Error(err))
+		return nil, err
+	}
+	if result == nil || len(result.Entries) != 1 {
+		if result != nil && len(result.Entries) > 1 {
+			zap.L().Warn("LDAP账号匹配到多个用户", zap.String("filter", filter))
+		}
+		return nil, LoginError{Message: "用户名或者密码错误！"}
+	}
+	return result.Entries[0], nil
+}
+
+// searchGroups 用户所属的组 DN 包括 memberOf 属性和 GroupBaseDN 下按 GroupFilter 搜索到的组
+func (p LdapLoginProvider) searchGroups(conn *ldap.Conn, entry *ldap.Entry, username string) ([]string, error) {
+	groups := entry.GetAttributeValues("memberOf")
+	if p.config.GroupBaseDN == "" {
+		return groups, nil
+	}
+	filter := strings.NewReplacer("{dn}", ldap.EscapeFilter(entry.DN), "{username}", ldap.EscapeFilter(username)).Replace(p.config.GroupFilter)
+	result, err := conn.Search(ldap.NewSearchRequest(
+		p.config.GroupBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, int(p.config.Timeout), false,
+		filter, []string{"cn"}, nil,
+	))
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range result.Entries {
+		groups = append(groups, group.DN)
+	}
+	return groups, nil
+}
+
+// mapRoles 按 GroupRoles 把组映射成角色 没有配置 GroupRoles 时返回 nil 不修改已有用户的角色
+func (p LdapLoginProvider) mapRoles(groups []string) []string {
+	if len(p.config.GroupRoles) == 0 {
+		return nil
+	}
+	names := map[string]bool{}
+	for _, group := range groups {
+		names[strings.ToLower(group)] = true
+		if cn := ldapCommonName(group); cn != "" {
+			names[strings.ToLower(cn)] = true
+		}
+	}
+	roles := make([]string, 0)
+	seen := map[string]bool{}
+	for _, mapping := range p.config.GroupRoles {
+		if !names[strings.ToLower(mapping.Group)] {
+			continue
+		}
+		for _, role := range mapping.Roles {
+			if !seen[role] {
+				seen[role] = true
+				roles = append(roles, role)
+			}
+		}
+	}
+	if len(roles) == 0 {
+		roles = append(roles, p.config.DefaultRoles...)
+	}
+	return roles
+}
+
+// entryID 用户在 LDAP 中的唯一标识 二进制属性 例如 AD 的 objectGUID 使用十六进制
+func (p LdapLoginProvider) entryID(entry *ldap.Entry) string {
+	if p.config.Attributes.ID == "" {
+		return entry.DN
+	}
+	raw := entry.GetRawAttributeValue(p.config.Attributes.ID)
+	if len(raw) == 0 {
+		return entry.DN
+	}
+	if utf8.Valid(raw) {
+		return string(raw)
+	}
+	return hex.EncodeToString(raw)
+}
+
+// ldapCommonName DN 第一段的 cn 例如 cn=admins,ou=groups,dc=example,dc=com 返回 admins
+func ldapCommonName(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 {
+		return ""
+	}
+	for _, attribute := range parsed.RDNs[0].Attributes {
+		if strings.EqualFold(attribute.Type, "cn") {
+			return attribute.Value
+		}
+	}
+	return ""
+}
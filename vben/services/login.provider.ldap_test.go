@@ -0,0 +1,206 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/super-sunshines/echo-server-core/core"
+)
+
+// ldapTestServer 只实现 Bind、Search 和 Unbind 的 LDAP 服务器
+// 和很多服务器一样 空密码的绑定按未认证绑定处理 直接成功
+type ldapTestServer struct {
+	listener  net.Listener
+	passwords map[string]string        // DN 对应的密码
+	entries   map[string][]*ldap.Entry // 过滤器对应的搜索结果
+
+	mu    sync.Mutex
+	binds []string // 按顺序记录绑定请求 DN:密码
+}
+
+func newLdapTestServer(t *testing.T) *ldapTestServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &ldapTestServer{listener: listener, passwords: map[string]string{}, entries: map[string][]*ldap.Entry{}}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *ldapTestServer) url() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+func (s *ldapTestServer) bindLog() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.binds...)
+}
+
+func (s *ldapTestServer) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		id := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			dn, password := op.Children[1].Data.String(), op.Children[2].Data.String()
+			s.mu.Lock()
+			s.binds = append(s.binds, dn+":"+password)
+			s.mu.Unlock()
+			code := uint16(ldap.LDAPResultSuccess)
+			if expected, ok := s.passwords[dn]; password != "" && (!ok || expected != password) {
+				code = ldap.LDAPResultInvalidCredentials
+			}
+			_, _ = conn.Write(ldapTestResult(id, ldap.ApplicationBindResponse, code).Bytes())
+		case ldap.ApplicationSearchRequest:
+			filter, _ := ldap.DecompileFilter(op.Children[6])
+			for _, entry := range s.entries[filter] {
+				_, _ = conn.Write(ldapTestEntry(id, entry).Bytes())
+			}
+			_, _ = conn.Write(ldapTestResult(id, ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess).Bytes())
+		default:
+			return
+		}
+	}
+}
+
+func ldapTestMessage(id int64, op *ber.Packet) *ber.Packet {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, ""))
+	packet.AppendChild(op)
+	return packet
+}
+
+func ldapTestResult(id int64, tag ber.Tag, code uint16) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "")
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), ""))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+	return ldapTestMessage(id, op)
+}
+
+func ldapTestEntry(id int64, entry *ldap.Entry) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "")
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.DN, ""))
+	attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+	for _, attribute := range entry.Attributes {
+		item := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+		item.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, attribute.Name, ""))
+		values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "")
+		for _, value := range attribute.Values {
+			values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, ""))
+		}
+		item.AppendChild(values)
+		attributes.AppendChild(item)
+	}
+	op.AppendChild(attributes)
+	return ldapTestMessage(id, op)
+}
+
+const (
+	ldapTestServiceDN = "cn=service,dc=example,dc=com"
+	ldapTestAliceDN   = "uid=alice,ou=people,dc=example,dc=com"
+	ldapTestBobDN     = "uid=bob,ou=people,dc=example,dc=com"
+)
+
+func newLdapTestProvider(t *testing.T) (LdapLoginProvider, *ldapTestServer) {
+	server := newLdapTestServer(t)
+	server.passwords[ldapTestServiceDN] = "service-pass"
+	server.passwords[ldapTestAliceDN] = "alice-pass"
+	server.passwords[ldapTestBobDN] = "bob-pass"
+	server.entries["(uid=alice)"] = []*ldap.Entry{ldap.NewEntry(ldapTestAliceDN, map[string][]string{
+		"uid": {"alice"}, "cn": {"Alice Liddell"}, "mail": {"alice@example.com"}, "mobile": {"13800000000"},
+		"memberOf": {"cn=Admins,ou=groups,dc=example,dc=com"},
+	})}
+	server.entries["(uid=bob)"] = []*ldap.Entry{ldap.NewEntry(ldapTestBobDN, map[string][]string{"uid": {"bob"}})}
+	// 两个分支下有同名的账号
+	server.entries["(uid=carol)"] = []*ldap.Entry{
+		ldap.NewEntry("uid=carol,ou=people,dc=example,dc=com", map[string][]string{"uid": {"carol"}}),
+		ldap.NewEntry("uid=carol,ou=partners,dc=example,dc=com", map[string][]string{"uid": {"carol"}}),
+	}
+	server.entries["(member="+ldapTestAliceDN+")"] = []*ldap.Entry{ldap.NewEntry("cn=ops,ou=groups,dc=example,dc=com", nil)}
+
+	return NewLdapLoginProvider(core.LdapConfig{
+		Url:          server.url(),
+		BindDN:       ldapTestServiceDN,
+		BindPassword: "service-pass",
+		BaseDN:       "dc=example,dc=com",
+		GroupBaseDN:  "ou=groups,dc=example,dc=com",
+		GroupRoles: []core.LdapGroupRole{
+			{Group: "admins", Roles: []string{"admin"}},
+			{Group: "cn=ops,ou=groups,dc=example,dc=com", Roles: []string{"ops", "admin"}},
+		},
+		DefaultRoles: []string{"user"},
+	}), server
+}
+
+func TestLdapLoginProviderAuthenticate(t *testing.T) {
+	provider, server := newLdapTestProvider(t)
+
+	identity, err := provider.Authenticate(nil, LoginCredential{Username: "alice", Password: "alice-pass"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.OpenID != ldapTestAliceDN || identity.Profile.Username != "alice" || identity.Profile.NickName != "Alice Liddell" ||
+		identity.Profile.Email != "alice@example.com" || identity.Profile.Phone != "13800000000" {
+		t.Fatalf("identity: %+v", identity)
+	}
+	// memberOf 按 cn 匹配 组搜索的结果按 DN 匹配 重复的角色只保留一个
+	if fmt.Sprint(identity.RoleCodes) != "[admin ops]" {
+		t.Fatalf("alice roles: %v", identity.RoleCodes)
+	}
+	// 先用服务账号搜索 再用用户的 DN 校验密码
+	if binds := server.bindLog(); fmt.Sprint(binds) != fmt.Sprint([]string{ldapTestServiceDN + ":service-pass", ldapTestAliceDN + ":alice-pass"}) {
+		t.Fatalf("binds: %v", binds)
+	}
+
+	// 没有匹配到任何组时使用 DefaultRoles
+	identity, err = provider.Authenticate(nil, LoginCredential{Username: "bob", Password: "bob-pass"})
+	if err != nil || fmt.Sprint(identity.RoleCodes) != "[user]" {
+		t.Fatalf("bob: %v %v", err, identity.RoleCodes)
+	}
+}
+
+func TestLdapLoginProviderRejects(t *testing.T) {
+	provider, server := newLdapTestProvider(t)
+	for name, credential := range map[string]LoginCredential{
+		"wrong password": {Username: "alice", Password: "wrong"},
+		// 服务器接受空密码的绑定 不能把它当成密码正确
+		"empty password": {Username: "alice", Password: ""},
+		"no such user":   {Username: "nobody", Password: "alice-pass"},
+		"two users":      {Username: "carol", Password: "alice-pass"},
+	} {
+		_, err := provider.Authenticate(nil, credential)
+		var loginErr LoginError
+		if !errors.As(err, &loginErr) {
+			t.Errorf("%s: want LoginError, got %v", name, err)
+		}
+	}
+	// 只有密码错误的账号用用户的 DN 绑定过
+	for _, bind := range server.bindLog() {
+		if bind != ldapTestServiceDN+":service-pass" && bind != ldapTestAliceDN+":wrong" {
+			t.Errorf("unexpected bind %q", bind)
+		}
+	}
+}
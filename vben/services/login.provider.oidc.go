@@ -0,0 +1,120 @@
+package services
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/super-sunshines/echo-server-core/core"
+	"github.com/super-sunshines/echo-server-core/vben/gorm/model"
+	"strings"
+)
+
+// OidcLoginProvider OAuth2/OIDC 登录 先通过 AuthURL 跳转到身份提供方 回调之后用 code 和 state 登录
+// 用户以 sub 作为三方唯一标识 每次登录都用声明中的信息更新用户
+type OidcLoginProvider struct {
+	config core.OidcConfig
+	client *core.OidcClient
+}
+
+func NewOidcLoginProvider(config core.OidcConfig) *OidcLoginProvider {
+	claims := &config.Claims
+	claims.Username = core.BooleanTo(claims.Username == "", "preferred_username", claims.Username)
+	claims.NickName = core.BooleanTo(claims.NickName == "", "name", claims.NickName)
+	claims.RealName = core.BooleanTo(claims.RealName == "", "name", claims.RealName)
+	claims.Email = core.BooleanTo(claims.Email == "", "email", claims.Email)
+	claims.Phone = core.BooleanTo(claims.Phone == "", "phone_number", claims.Phone)
+	claims.Avatar = core.BooleanTo(claims.Avatar == "", "picture", claims.Avatar)
+	return &OidcLoginProvider{config: config, client: core.NewOidcClient(config)}
+}
+
+func (p *OidcLoginProvider) Name() string {
+	return p.config.Name
+}
+
+// AuthURL 跳转到身份提供方的授权地址
+func (p *OidcLoginProvider) AuthURL(c echo.Context) (string, error) {
+	return p.client.AuthURL(c.Request().Context())
+}
+
+func (p *OidcLoginProvider) Authenticate(c echo.Context, credential LoginCredential) (LoginIdentity, error) {
+	if credential.Code == "" {
+		return LoginIdentity{}, LoginError{Message: "授权码不能为空！"}
+	}
+	claims, err := p.client.Exchange(c.Request().Context(), credential.Code, credential.State)
+	if err != nil {
+		return LoginIdentity{}, err
+	}
+	mapping := p.config.Claims
+	sub := oidcClaimString(claims, "sub")
+	profile := model.SysUser{
+		Username:     oidcClaimString(claims, mapping.Username),
+		NickName:     oidcClaimString(claims, mapping.NickName),
+		RealName:     oidcClaimString(claims, mapping.RealName),
+		Email:        oidcClaimString(claims, mapping.Email),
+		Avatar:       oidcClaimString(claims, mapping.Avatar),
+		RoleCodeList: p.config.DefaultRoles,
+	}
+	profile.Username = core.BooleanTo(profile.Username == "", sub, profile.Username)
+	// 手机号字段只有 11 位 格式不同的号码不同步
+	if phone := oidcClaimString(claims, mapping.Phone); len(phone) <= 11 {
+		profile.Phone = phone
+	}
+	return LoginIdentity{
+		Platform:     p.config.Name,
+		OpenID:       sub,
+		Profile:      profile,
+		RoleCodes:    p.mapRoles(claims),
+		AutoRegister: p.config.AutoRegister,
+		Sync:         true,
+	}, nil
+}
+
+// mapRoles 按 ClaimRoles 把声明中的值映射成角色 没有配置时返回 nil 不修改已有用户的角色
+func (p *OidcLoginProvider) mapRoles(claims map[string]any) []string {
+	if p.config.Claims.Roles == "" || len(p.config.ClaimRoles) == 0 {
+		return nil
+	}
+	values := map[string]bool{}
+	switch value := oidcClaim(claims, p.config.Claims.Roles).(type) {
+	case string:
+		values[value] = true
+	case []any:
+		for _, item := range value {
+			values[fmt.Sprint(item)] = true
+		}
+	}
+	roles := make([]string, 0)
+	seen := map[string]bool{}
+	for _, mapping := range p.config.ClaimRoles {
+		if !values[mapping.Value] {
+			continue
+		}
+		for _, role := range mapping.Roles {
+			if !seen[role] {
+				seen[role] = true
+				roles = append(roles, role)
+			}
+		}
+	}
+	if len(roles) == 0 {
+		roles = append(roles, p.config.DefaultRoles...)
+	}
+	return roles
+}
+
+// oidcClaim 按名称取声明 名称中的 . 表示嵌套 例如 Keycloak 的 realm_access.roles
+func oidcClaim(claims map[string]any, name string) any {
+	var value any = claims
+	for _, key := range strings.Split(name, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+	return value
+}
+
+func oidcClaimString(claims map[string]any, name string) string {
+	value, _ := oidcClaim(claims, name).(string)
+	return value
+}
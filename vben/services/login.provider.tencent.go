@@ -0,0 +1,145 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/super-sunshines/echo-server-core/core"
+	"github.com/super-sunshines/echo-server-core/vben/bo"
+	"github.com/super-sunshines/echo-server-core/vben/gorm/model"
+	"go.uber.org/zap"
+	"time"
+)
+
+// WorkWeChatLoginProvider 企业微信 oauth2 登录 Code 是企业微信回调带上的 code
+type WorkWeChatLoginProvider struct{}
+
+func NewWorkWeChatLoginProvider() WorkWeChatLoginProvider {
+	return WorkWeChatLoginProvider{}
+}
+
+func (p WorkWeChatLoginProvider) Name() string {
+	return LoginProviderWorkWeChat
+}
+
+func (p WorkWeChatLoginProvider) Authenticate(c echo.Context, credential LoginCredential) (LoginIdentity, error) {
+	if credential.Code == "" {
+		return LoginIdentity{}, LoginError{Message: "授权码不能为空！"}
+	}
+	// 没有使用企业微信登录时不初始化 避免启动后台刷新协程
+	userInfo, err := NewTencentWorkWeChatService(core.GetContextConfig(c)).UserInfoByCode(credential.Code)
+	if err != nil {
+		zap.L().Error("获取用户信息失败", zap.Error(err))
+		return LoginIdentity{}, core.NewFrontShowErrMsg("获取用户信息失败!请联系管理员")
+	}
+	config := core.GetContextConfig(c).Tencent.WorkWechat
+	return LoginIdentity{
+		Platform: LoginProviderWorkWeChat,
+		OpenID:   userInfo.UserID,
+		Profile: model.SysUser{
+			Username:     userInfo.UserID,
+			NickName:     userInfo.Name,
+			RealName:     userInfo.Name,
+			RoleCodeList: config.DefaultRoles,
+		},
+		AutoRegister: config.AutoRegister,
+	}, nil
+}
+
+// WeChatLoginProvider 微信网页授权 Code 是微信回调带上的 code
+// 只能拿到 openid 不会自动创建用户 用于已登录的用户绑定微信
+type WeChatLoginProvider struct {
+	requestClient *resty.Client
+}
+
+func NewWeChatLoginProvider() WeChatLoginProvider {
+	return WeChatLoginProvider{
+		requestClient: resty.New().
+			SetRetryCount(3).
+			SetRetryWaitTime(5*time.Second).SetHeader("Content-Type", "application/json"),
+	}
+}
+
+func (p WeChatLoginProvider) Name() string {
+	return LoginProviderWeChat
+}
+
+func (p WeChatLoginProvider) Authenticate(c echo.Context, credential LoginCredential) (LoginIdentity, error) {
+	if credential.Code == "" {
+		return LoginIdentity{}, LoginError{Message: "授权码不能为空！"}
+	}
+	config := core.GetContextConfig(c).Tencent.Wechat
+	resp, err := p.requestClient.R().
+		SetQueryParams(
+			map[string]string{
+				"appid":      config.AppId,
+				"secret":     config.AppSecret,
+				"code":       credential.Code,
+				"grant_type": "authorization_code",
+			}).
+		Get("https://api.weixin.qq.com/sns/oauth2/access_token")
+	if err != nil {
+		return LoginIdentity{}, core.NewFrontShowErrMsg("授权登录失败！")
+	}
+	var result bo.WechatOauth2Resp
+	if _ = json.Unmarshal(resp.Body(), &result); result.Errcode != 0 || result.Openid == "" {
+		return LoginIdentity{}, core.NewFrontShowErrMsg(fmt.Sprintf("授权登录失败！%s", result.Errmsg))
+	}
+	return LoginIdentity{
+		Platform: LoginProviderWeChat,
+		OpenID:   result.Openid,
+		Profile:  model.SysUser{Username: result.Openid},
+	}, nil
+}
+
+// WeChatAppLoginProvider 微信小程序登录 Code 是 wx.login 拿到的 code
+type WeChatAppLoginProvider struct {
+	requestClient *resty.Client
+}
+
+func NewWeChatAppLoginProvider() WeChatAppLoginProvider {
+	return WeChatAppLoginProvider{
+		requestClient: resty.New().
+			SetRetryCount(3).
+			SetRetryWaitTime(5*time.Second).SetHeader("Content-Type", "application/json"),
+	}
+}
+
+func (p WeChatAppLoginProvider) Name() string {
+	return LoginProviderWeChatApp
+}
+
+func (p WeChatAppLoginProvider) Authenticate(c echo.Context, credential LoginCredential) (LoginIdentity, error) {
+	if credential.Code == "" {
+		return LoginIdentity{}, LoginError{Message: "授权码不能为空！"}
+	}
+	config := core.GetContextConfig(c).Tencent.WechatApp
+	resp, err := p.requestClient.R().
+		SetQueryParams(
+			map[string]string{
+				"appid":      config.AppId,
+				"secret":     config.AppSecret,
+				"js_code":    credential.Code,
+				"grant_type": "authorization_code",
+			}).
+		Get("https://api.weixin.qq.com/sns/jscode2session")
+	if err != nil {
+		return LoginIdentity{}, core.NewFrontShowErrMsg("授权登录失败！")
+	}
+	var result bo.WechatAppServerResp
+	if _ = json.Unmarshal(resp.Body(), &result); result.Errcode != 0 || result.Openid == "" {
+		return LoginIdentity{}, core.NewFrontShowErrMsg(fmt.Sprintf("授权登录失败！%s", result.Errmsg))
+	}
+	return LoginIdentity{
+		Platform: LoginProviderWeChatApp,
+		OpenID:   result.Openid,
+		Profile: model.SysUser{
+			Username: result.Openid,
+			NickName: config.DefaultNickName,
+			RealName: config.DefaultNickName,
+			Avatar:   config.DefaultAvatar,
+		},
+		AutoRegister: true,
+	}, nil
+}
@@ -10,28 +10,31 @@ import (
 
 type SysDepartmentService struct {
 	core.PreGorm[model.SysDepartment, vo.SysDepartmentVo]
-	userService     core.PreGorm[model.SysUser, vo.SysUserVo]
-	departmentCache *core.RedisCache[[]model.SysDepartment]
+	userService core.PreGorm[model.SysUser, vo.SysUserVo]
 }
 
 func NewDepartmentService() SysDepartmentService {
 	return SysDepartmentService{
 		core.NewService[model.SysDepartment, vo.SysDepartmentVo](),
 		core.NewService[model.SysUser, vo.SysUserVo](),
-		core.GetRedisCache[[]model.SysDepartment]("sys-department-cache"),
 	}
 }
 
-func (r SysDepartmentService) ClearCache() {
-	r.departmentCache.XDel()
+// departmentCache 处理当前请求的 Server 的部门缓存
+func (r SysDepartmentService) departmentCache(c echo.Context) *core.RedisCache[[]model.SysDepartment] {
+	return core.GetContextRedisCache[[]model.SysDepartment](c, "sys-department-cache")
+}
+
+func (r SysDepartmentService) ClearCache(c echo.Context) {
+	r.departmentCache(c).XDel()
 }
 func (r SysDepartmentService) GetAllDepartment(c echo.Context) []model.SysDepartment {
 	departments := make([]model.SysDepartment, 0)
-	if have, value := r.departmentCache.XGet(); have {
+	if have, value := r.departmentCache(c).XGet(); have {
 		departments = value
 	} else {
 		_, departments = r.WithContext(c).SkipGlobalHook().FindList()
-		r.departmentCache.XSet(departments)
+		r.departmentCache(c).XSet(departments)
 	}
 	return departments
 }
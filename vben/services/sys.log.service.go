@@ -37,7 +37,7 @@ func (r SysLogService) AddLog(c echo.Context, log model.SysLogOperate) {
 		from.OperateLocation, _ = core.IPParse(c.RealIP())
 		from.OperateParam = c.QueryParams().Encode()
 		from.OperateTime = core.NewTime(time.Now())
-		_, _ = r.PreGorm.SetDB(core.GetGormDB()).SkipGlobalHook().InsertOne(from)
+		_, _ = r.PreGorm.SetDB(core.GetAnyContext(c).GetDB()).SkipGlobalHook().InsertOne(from)
 
 	}()
 }
@@ -63,7 +63,7 @@ func (r SysLogService) AddLogSimple(c echo.Context, title string, content string
 		from.OperateLocation, _ = core.IPParse(c.RealIP())
 		from.OperateParam = c.QueryParams().Encode()
 		from.OperateTime = core.NewTime(time.Now())
-		_, _ = r.PreGorm.SetDB(core.GetGormDB()).SkipGlobalHook().InsertOne(from)
+		_, _ = r.PreGorm.SetDB(core.GetAnyContext(c).GetDB()).SkipGlobalHook().InsertOne(from)
 
 	}()
 }
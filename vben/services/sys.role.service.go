@@ -9,29 +9,34 @@ import (
 
 type SysRoleService struct {
 	core.PreGorm[model.SysRole, vo.SysRoleVo]
-	roleCache *core.RedisCache[model.SysRole]
 }
 
 func NewSysRoleService() SysRoleService {
 	return SysRoleService{
-		PreGorm:   core.NewService[model.SysRole, vo.SysRoleVo](),
-		roleCache: core.GetRedisCache[model.SysRole]("sys-role-permission-cache"),
+		PreGorm: core.NewService[model.SysRole, vo.SysRoleVo](),
 	}
 }
-func (r SysRoleService) RefreshCache() {
-	r.roleCache.XDel()
+
+// roleCache 处理当前请求的 Server 的角色缓存
+func (r SysRoleService) roleCache(c echo.Context) *core.RedisCache[model.SysRole] {
+	return core.GetContextRedisCache[model.SysRole](c, "sys-role-permission-cache")
+}
+
+func (r SysRoleService) RefreshCache(c echo.Context) {
+	r.roleCache(c).XDel()
 }
 
 func (r SysRoleService) GetAllRole(c echo.Context) map[string]model.SysRole {
-	all := r.roleCache.XHGetAll()
+	roleCache := r.roleCache(c)
+	all := roleCache.XHGetAll()
 	if len(all) == 0 {
 		var roles []model.SysRole
 		r.WithContext(c).SkipGlobalHook().Find(&roles)
 		for _, role := range roles {
-			r.roleCache.XHSet(role.Code, role)
+			roleCache.XHSet(role.Code, role)
 		}
 	}
-	return r.roleCache.XHGetAll()
+	return roleCache.XHGetAll()
 }
 
 func (r SysRoleService) GetRoleConfigByCodes(c echo.Context, codes ...string) []model.SysRole {
@@ -1,19 +1,25 @@
 package services
 
 import (
+	"errors"
+	"fmt"
 	"github.com/duke-git/lancet/v2/slice"
+	"github.com/labstack/echo/v4"
 	"github.com/super-sunshines/echo-server-core/core"
+	_const "github.com/super-sunshines/echo-server-core/vben/const"
 	"github.com/super-sunshines/echo-server-core/vben/gorm/model"
 	"gorm.io/gorm"
 )
 
 type SysThirdBindService struct {
 	core.PreGorm[model.SysUserThirdBind, model.SysUserThirdBind]
+	userService core.PreGorm[model.SysUser, any]
 }
 
 func NewSysThirdBindService() SysThirdBindService {
 	return SysThirdBindService{
-		PreGorm: core.NewService[model.SysUserThirdBind, model.SysUserThirdBind](),
+		PreGorm:     core.NewService[model.SysUserThirdBind, model.SysUserThirdBind](),
+		userService: core.NewService[model.SysUser, any](),
 	}
 }
 
@@ -37,3 +43,179 @@ func (s SysThirdBindService) ThirdPlatformUidToUid(thirdPlatform string, workWec
 	})
 	return bind.UserID, err == nil
 }
+
+// FindOrProvision 找到三方身份绑定的本地用户 没有绑定时创建用户和绑定关系 created 表示是否新建了用户
+// 新建的用户使用随机密码 只能通过三方登录 所有三方登录方式共用
+func (s SysThirdBindService) FindOrProvision(c echo.Context, identity LoginIdentity) (user model.SysUser, created bool, err error) {
+	err, bind := s.WithContext(c).SkipGlobalHook().FindOne(func(db *gorm.DB) *gorm.DB {
+		return db.Where("openid = ?", identity.OpenID).Where("login_type = ?", identity.Platform)
+	})
+	if err == nil {
+		if err, user = s.userService.WithContext(c).SkipGlobalHook().FindOneByPrimaryKey(bind.UserID); err != nil {
+			return user, false, err
+		}
+		if identity.Sync {
+			err = s.syncUser(c, &user, identity)
+		}
+		return user, false, err
+	}
+	profile := identity.Profile
+	taken := s.userService.WithContext(c).SkipGlobalHook().Exist(func(db *gorm.DB) *gorm.DB {
+		return db.Where("username = ?", profile.Username)
+	})
+	if taken {
+		return user, false, core.NewFrontShowErrMsg(fmt.Sprintf("账号%s已存在，请联系管理员绑定！", profile.Username))
+	}
+	profile.Password = core.HashPassword(core.GetUUID())
+	profile.EnableStatus = int64(core.BooleanTo(identity.AutoRegister, _const.CommonStateOk, _const.CommonStateBanned))
+	if identity.RoleCodes != nil {
+		profile.RoleCodeList = identity.RoleCodes
+	}
+	err = core.Transaction(c, func(tx core.TxScope) error {
+		var err error
+		if err, user = s.userService.WithContext(tx).SkipGlobalHook().InsertOne(profile); err != nil {
+			return err
+		}
+		err, _ = s.WithContext(tx).SkipGlobalHook().InsertOne(model.SysUserThirdBind{
+			UserID:    user.ID,
+			LoginType: identity.Platform,
+			Openid:    identity.OpenID,
+		})
+		// 同一个三方账号并发登录时 另一个请求已经创建了绑定
+		return bindConflict(err)
+	})
+	return user, err == nil, err
+}
+
+// ListByUid 用户绑定的三方账号
+func (s SysThirdBindService) ListByUid(c echo.Context, uid int64) ([]model.SysUserThirdBind, error) {
+	err, binds := s.WithContext(c).SkipGlobalHook().FindList(func(db *gorm.DB) *gorm.DB {
+		return db.Where("user_id = ?", uid).Order("id")
+	})
+	return binds, err
+}
+
+// Bind 把登录方式认证通过的三方身份绑定到用户 三方账号已经绑定其他用户或者用户已经绑定了同一平台的其他账号时拒绝
+func (s SysThirdBindService) Bind(c echo.Context, uid int64, identity LoginIdentity) error {
+	if identity.UserID != 0 || identity.Platform == "" || identity.OpenID == "" {
+		return core.NewFrontShowErrMsg("该登录方式不支持绑定！")
+	}
+	return core.Transaction(c, func(tx core.TxScope) error {
+		if bound, exist := s.findBind(tx, identity.Platform, identity.OpenID); exist {
+			if bound.UserID == uid {
+				return nil
+			}
+			return core.NewFrontShowErrMsg("该三方账号已绑定其他用户！")
+		}
+		if s.WithContext(tx).SkipGlobalHook().Exist(func(db *gorm.DB) *gorm.DB {
+			return db.Where("user_id = ?", uid).Where("login_type = ?", identity.Platform)
+		}) {
+			return core.NewFrontShowErrMsg(fmt.Sprintf("已绑定%s账号，请先解绑！", identity.Platform))
+		}
+		err, _ := s.WithContext(tx).SkipGlobalHook().InsertOne(model.SysUserThirdBind{
+			UserID:    uid,
+			LoginType: identity.Platform,
+			Openid:    identity.OpenID,
+		})
+		return bindConflict(err)
+	})
+}
+
+// Unbind 解除用户在某个平台的绑定 物理删除 软删除的记录会占用 (login_type, openid) 唯一索引
+func (s SysThirdBindService) Unbind(c echo.Context, uid int64, platform string) error {
+	err, rows := s.WithContext(c).SkipGlobalHook().Unscoped().DeleteBy(func(db *gorm.DB) *gorm.DB {
+		return db.Where("user_id = ?", uid).Where("login_type = ?", platform)
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return core.NewFrontShowErrMsg("没有绑定该平台的账号！")
+	}
+	return nil
+}
+
+// Merge 把重复的用户合并到目标用户 三方绑定转移到目标用户之后删除重复的用户
+// 两个用户绑定了同一平台时拒绝合并 需要先解绑其中一个
+func (s SysThirdBindService) Merge(c echo.Context, sourceId, targetId int64) error {
+	if sourceId == targetId {
+		return core.NewFrontShowErrMsg("不能合并同一个用户！")
+	}
+	err := core.Transaction(c, func(tx core.TxScope) error {
+		for _, id := range []int64{sourceId, targetId} {
+			if err, _ := s.userService.WithContext(tx).SkipGlobalHook().FindOneByPrimaryKey(id); err != nil {
+				return core.NewFrontShowErrMsg(fmt.Sprintf("用户%d不存在！", id))
+			}
+		}
+		sources, err := s.ListByUid(tx, sourceId)
+		if err != nil {
+			return err
+		}
+		targets, err := s.ListByUid(tx, targetId)
+		if err != nil {
+			return err
+		}
+		for _, source := range sources {
+			if slice.ContainBy(targets, func(target model.SysUserThirdBind) bool { return target.LoginType == source.LoginType }) {
+				return core.NewFrontShowErrMsg(fmt.Sprintf("两个用户都绑定了%s账号，请先解绑！", source.LoginType))
+			}
+		}
+		if err = s.WithContext(tx).SkipGlobalHook().Where("user_id = ?", sourceId).UpdateColumn("user_id", targetId).Error; err != nil {
+			return err
+		}
+		err, _ = s.userService.WithContext(tx).SkipGlobalHook().DeleteByPrimaryKeys([]int64{sourceId})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	core.GetContextTokenManager(c).RemoveTokenByUid(sourceId)
+	return nil
+}
+
+// findBind 三方账号的绑定关系
+func (s SysThirdBindService) findBind(c echo.Context, platform, openid string) (model.SysUserThirdBind, bool) {
+	err, bind := s.WithContext(c).SkipGlobalHook().FindOne(func(db *gorm.DB) *gorm.DB {
+		return db.Where("openid = ?", openid).Where("login_type = ?", platform)
+	})
+	return bind, err == nil
+}
+
+// bindConflict 三方账号已经绑定时 唯一索引冲突转换成提示
+func bindConflict(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return core.NewFrontShowErrMsg("该三方账号已绑定其他用户！")
+	}
+	return err
+}
+
+// syncUser 用三方的信息更新用户 空值不覆盖
+func (s SysThirdBindService) syncUser(c echo.Context, user *model.SysUser, identity LoginIdentity) error {
+	columns := map[string]any{}
+	profile := identity.Profile
+	for column, value := range map[string]string{
+		"nick_name": profile.NickName,
+		"real_name": profile.RealName,
+		"email":     profile.Email,
+		"phone":     profile.Phone,
+		"avatar":    profile.Avatar,
+	} {
+		if value != "" {
+			columns[column] = value
+		}
+	}
+	if identity.RoleCodes != nil {
+		columns["role_code_list"] = core.Array[string](identity.RoleCodes)
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+	if err := s.userService.WithContext(c).SkipGlobalHook().Where("id = ?", user.ID).UpdateColumns(columns).Error; err != nil {
+		return err
+	}
+	err, updated := s.userService.WithContext(c).SkipGlobalHook().FindOneByPrimaryKey(user.ID)
+	if err == nil {
+		*user = updated
+	}
+	return err
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/super-sunshines/echo-server-core/core"
+	_const "github.com/super-sunshines/echo-server-core/vben/const"
+	"github.com/super-sunshines/echo-server-core/vben/gorm/model"
+	"strconv"
+	"time"
+)
+
+// 没有配置 Jwt.LoginLockDuration 时锁定 15 分钟
+const defaultLoginLockDuration int64 = 15 * 60
+
+// SysUserLockService 登录失败次数达到上限之后临时锁定账号
+// 锁定记录保存在 Redis 中 到期自动解锁 管理员封禁的账号不受影响 需要管理员解锁
+type SysUserLockService struct{}
+
+func NewSysUserLockService() SysUserLockService {
+	return SysUserLockService{}
+}
+
+// cache 值是解锁时间 秒
+func (s SysUserLockService) cache(c echo.Context) *core.RedisCache[int64] {
+	return core.GetContextRedisCache[int64](c, "sys:user:login:lock:")
+}
+
+func (s SysUserLockService) duration(c echo.Context) time.Duration {
+	seconds := core.GetContextConfig(c).Jwt.LoginLockDuration
+	if seconds <= 0 {
+		seconds = defaultLoginLockDuration
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// Lock 锁定账号 返回给用户的提示
+func (s SysUserLockService) Lock(c echo.Context, uid int64) string {
+	duration := s.duration(c)
+	s.cache(c).XSetCodeEX(strconv.FormatInt(uid, 10), core.GetNowTimeUnix()+int64(duration.Seconds()), duration)
+	return lockedMessage(duration)
+}
+
+// Unlock 提前解除临时锁定
+func (s SysUserLockService) Unlock(c echo.Context, uid int64) {
+	s.cache(c).XCodeDel(strconv.FormatInt(uid, 10))
+}
+
+// LockedMessage 账号被封禁或者临时锁定时返回提示 可以登录时返回空字符串
+func (s SysUserLockService) LockedMessage(c echo.Context, a model.SysUser) string {
+	if a.EnableStatus == _const.CommonStateBanned {
+		return "账户已锁定，请联系管理员解锁！"
+	}
+	have, until := s.cache(c).XCodeGet(strconv.FormatInt(a.ID, 10))
+	if remain := until - core.GetNowTimeUnix(); have && remain > 0 {
+		return lockedMessage(time.Duration(remain) * time.Second)
+	}
+	return ""
+}
+
+// lockedMessage 临时锁定的提示 不足一分钟按一分钟计算
+func lockedMessage(remain time.Duration) string {
+	return fmt.Sprintf("登录失败次数过多，请%d分钟后再试！", int64((remain+time.Minute-time.Second)/time.Minute))
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"github.com/duke-git/lancet/v2/slice"
+	"github.com/labstack/echo/v4"
+	"github.com/super-sunshines/echo-server-core/core"
+	"github.com/super-sunshines/echo-server-core/vben/gorm/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+type SysUserPasswordService struct {
+	core.PreGorm[model.SysUserPasswordHistory, model.SysUserPasswordHistory]
+}
+
+func NewSysUserPasswordService() SysUserPasswordService {
+	return SysUserPasswordService{
+		PreGorm: core.NewService[model.SysUserPasswordHistory, model.SysUserPasswordHistory](),
+	}
+}
+
+// Check 校验新密码 需要符合密码策略 不能包含用户名 也不能和最近使用过的密码相同
+func (s SysUserPasswordService) Check(c echo.Context, user model.SysUser, password string) error {
+	if err := core.GetContextValidator(c).CheckPassword(password, user.Username); err != nil {
+		return err
+	}
+	count := core.GetContextConfig(c).Password.HistoryCount
+	if count <= 0 {
+		return nil
+	}
+	// 最新的一条记录就是当前密码 没有记录的老用户只比较当前密码
+	hashes := []string{user.Password}
+	err, histories := s.recent(c, user.ID, count)
+	if err != nil {
+		return err
+	}
+	hashes = append(hashes, slice.Map(histories, func(_ int, item model.SysUserPasswordHistory) string { return item.Password })...)
+	for _, hashed := range hashes {
+		if hashed != "" && core.ComparePasswords(hashed, password) {
+			return core.GetContextValidator(c).PasswordReusedError()
+		}
+	}
+	return nil
+}
+
+// Record 记录一次密码修改 hashed 是加密后的新密码 只保留密码策略需要的条数
+func (s SysUserPasswordService) Record(c echo.Context, uid int64, hashed string) error {
+	err, _ := s.WithContext(c).SkipGlobalHook().InsertOne(model.SysUserPasswordHistory{UserID: uid, Password: hashed})
+	if err != nil {
+		return err
+	}
+	// 至少保留一条 用来计算密码有效期
+	keep := max(core.GetContextConfig(c).Password.HistoryCount, 1)
+	err, histories := s.WithContext(c).SkipGlobalHook().FindList(func(db *gorm.DB) *gorm.DB {
+		return db.Select("id").Where("user_id = ?", uid).Order("id desc")
+	})
+	if err != nil || len(histories) <= keep {
+		return err
+	}
+	ids := slice.Map(histories[keep:], func(_ int, item model.SysUserPasswordHistory) int64 { return item.ID })
+	err, _ = s.WithContext(c).SkipGlobalHook().DeleteByPrimaryKeys(ids)
+	return err
+}
+
+// Expired 密码是否超过有效期 没有修改记录时从用户创建时间开始计算
+func (s SysUserPasswordService) Expired(c echo.Context, user model.SysUser) bool {
+	maxAge := core.GetContextConfig(c).Password.MaxAge
+	if maxAge <= 0 {
+		return false
+	}
+	changed := user.CreateTime.Time
+	if err, histories := s.recent(c, user.ID, 1); err == nil && len(histories) > 0 {
+		changed = histories[0].CreateTime.Time
+	}
+	if changed.IsZero() {
+		return false
+	}
+	return time.Since(changed) > time.Duration(maxAge)*24*time.Hour
+}
+
+// recent 最近的几条密码记录 按时间倒序
+func (s SysUserPasswordService) recent(c echo.Context, uid int64, limit int) (error, []model.SysUserPasswordHistory) {
+	return s.WithContext(c).SkipGlobalHook().FindList(func(db *gorm.DB) *gorm.DB {
+		return db.Where("user_id = ?", uid).Order("id desc").Limit(limit)
+	})
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"github.com/duke-git/lancet/v2/slice"
+	"github.com/labstack/echo/v4"
+	"github.com/super-sunshines/echo-server-core/core"
+	"github.com/super-sunshines/echo-server-core/vben/gorm/model"
+	"gorm.io/gorm"
+	"strings"
+)
+
+const (
+	// 恢复码数量 每个恢复码只能使用一次
+	totpRecoveryCodeCount = 10
+	// 认证器 App 中显示的默认发行方
+	defaultTotpIssuer = "echo-server"
+)
+
+type SysUserTotpService struct {
+	core.PreGorm[model.SysUserTotp, model.SysUserTotp]
+}
+
+func NewSysUserTotpService() SysUserTotpService {
+	return SysUserTotpService{
+		PreGorm: core.NewService[model.SysUserTotp, model.SysUserTotp](),
+	}
+}
+
+// IsEnabled 用户是否开启了两步验证
+func (s SysUserTotpService) IsEnabled(c echo.Context, uid int64) bool {
+	return s.WithContext(c).SkipGlobalHook().Exist(func(db *gorm.DB) *gorm.DB {
+		return db.Where("user_id = ?", uid).Where("enabled = ?", core.IntBoolTrue)
+	})
+}
+
+// Enroll 生成新的密钥 验证通过之后才会启用 已经启用时需要先关闭
+func (s SysUserTotpService) Enroll(c echo.Context, user model.SysUser) (secret string, uri string, err error) {
+	err, totp := s.findByUid(c, user.ID)
+	if err == nil && totp.Enabled {
+		return "", "", core.NewFrontShowErrMsg("已开启两步验证，请先关闭！")
+	}
+	if secret, err = core.GenTotpSecret(); err != nil {
+		return "", "", err
+	}
+	if totp.ID == 0 {
+		err, _ = s.WithContext(c).SkipGlobalHook().InsertOne(model.SysUserTotp{UserID: user.ID, Secret: secret})
+	} else {
+		err = s.WithContext(c).SkipGlobalHook().Where("id = ?", totp.ID).UpdateColumns(map[string]any{
+			"secret":         secret,
+			"last_counter":   0,
+			"recovery_codes": core.Array[string]{},
+		}).Error
+	}
+	if err != nil {
+		return "", "", err
+	}
+	issuer := core.GetContextConfig(c).Jwt.TotpIssuer
+	if issuer == "" {
+		issuer = defaultTotpIssuer
+	}
+	return secret, core.TotpURI(issuer, user.Username, secret), nil
+}
+
+// Verify 校验认证器上的验证码并启用两步验证 返回明文恢复码 只在这里返回一次
+func (s SysUserTotpService) Verify(c echo.Context, uid int64, code string) ([]string, error) {
+	err, totp := s.findByUid(c, uid)
+	if err != nil || totp.Enabled {
+		return nil, core.NewFrontShowErrMsg("请先获取两步验证密钥！")
+	}
+	counter, ok := core.ValidateTotp(totp.Secret, code, totp.LastCounter)
+	if !ok {
+		return nil, core.NewFrontShowErrMsg("验证码错误！")
+	}
+	codes := make([]string, 0, totpRecoveryCodeCount)
+	for range totpRecoveryCodeCount {
+		recoveryCode, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, recoveryCode)
+	}
+	err = s.WithContext(c).SkipGlobalHook().Where("id = ?", totp.ID).UpdateColumns(map[string]any{
+		"enabled":        core.IntBoolTrue,
+		"last_counter":   counter,
+		"recovery_codes": core.Array[string](slice.Map(codes, func(_ int, item string) string { return hashRecoveryCode(item) })),
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable 关闭两步验证 需要验证码或者恢复码
+func (s SysUserTotpService) Disable(c echo.Context, uid int64, code string) error {
+	if err := s.Check(c, uid, code); err != nil {
+		return err
+	}
+	return s.WithContext(c).SkipGlobalHook().Unscoped().Where("user_id = ?", uid).Delete(&model.SysUserTotp{}).Error
+}
+
+// Check 校验验证码或者恢复码 验证码不能重复使用 恢复码使用后作废
+func (s SysUserTotpService) Check(c echo.Context, uid int64, code string) error {
+	err, totp := s.findByUid(c, uid)
+	if err != nil || !totp.Enabled {
+		return core.NewFrontShowErrMsg("未开启两步验证！")
+	}
+	if counter, ok := core.ValidateTotp(totp.Secret, code, totp.LastCounter); ok {
+		// 并发提交同一个验证码时只有一个能更新成功
+		tx := s.WithContext(c).SkipGlobalHook().Where("id = ?", totp.ID).Where("last_counter < ?", counter).
+			UpdateColumn("last_counter", counter)
+		if tx.Error == nil && tx.RowsAffected == 1 {
+			return nil
+		}
+		return core.NewFrontShowErrMsg("验证码错误！")
+	}
+	digest := hashRecoveryCode(code)
+	if !slice.Contain(totp.RecoveryCodes, digest) {
+		return core.NewFrontShowErrMsg("验证码错误！")
+	}
+	remain := slice.Filter(totp.RecoveryCodes, func(_ int, item string) bool { return item != digest })
+	// 恢复码在读取之后被其他请求用掉时更新不到数据 同一个恢复码只有一个请求能成功
+	service := s.WithContext(c).SkipGlobalHook()
+	tx := service.Where("id = ?", totp.ID).Where(totp.RecoveryCodes.EqualExpr(service.GetDb(), "recovery_codes")).
+		UpdateColumn("recovery_codes", core.Array[string](remain))
+	if tx.Error == nil && tx.RowsAffected == 1 {
+		return nil
+	}
+	return core.NewFrontShowErrMsg("验证码错误！")
+}
+
+// RecoveryCodeCount 剩余可用的恢复码数量
+func (s SysUserTotpService) RecoveryCodeCount(c echo.Context, uid int64) int {
+	_, totp := s.findByUid(c, uid)
+	return len(totp.RecoveryCodes)
+}
+
+func (s SysUserTotpService) findByUid(c echo.Context, uid int64) (error, model.SysUserTotp) {
+	return s.WithContext(c).SkipGlobalHook().Primary().FindOne(func(db *gorm.DB) *gorm.DB {
+		return db.Where("user_id = ?", uid)
+	})
+}
+
+// newRecoveryCode 恢复码 xxxxx-xxxxx 格式 方便抄写
+func newRecoveryCode() (string, error) {
+	buf := make([]byte, 7)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(base32.StdEncoding.EncodeToString(buf))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// hashRecoveryCode 恢复码只保存摘要 忽略大小写和分隔符
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
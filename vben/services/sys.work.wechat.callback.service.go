@@ -0,0 +1,275 @@
+package services
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/super-sunshines/echo-server-core/core"
+	eventCenter "github.com/super-sunshines/echo-server-core/vben/event"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 回调的时间戳和当前时间相差超过 5 分钟时拒绝 签名包含时间戳和 nonce 重放只能使用原来的值 在这段时间内记录处理过的 nonce
+const workWeChatCallbackWindow = 5 * time.Minute
+
+var (
+	errWorkWeChatCallbackSignature = errors.New("企业微信回调签名错误")
+	errWorkWeChatCallbackPayload   = errors.New("企业微信回调消息解密失败")
+	errWorkWeChatCallbackExpired   = errors.New("企业微信回调时间戳超出有效期")
+	// ErrWorkWeChatCallbackReplay 消息已经处理过 企业微信超时重试时也会重复发送
+	ErrWorkWeChatCallbackReplay = errors.New("企业微信回调消息重复")
+)
+
+// 通讯录回调的变更类型对应的事件
+var workWeChatContactEventKeys = map[string]string{
+	"create_user":  eventCenter.TencentWorkWeChatCreateUserEventBusKey,
+	"update_user":  eventCenter.TencentWorkWeChatUpdateUserEventBusKey,
+	"delete_user":  eventCenter.TencentWorkWeChatDeleteUserEventBusKey,
+	"create_party": eventCenter.TencentWorkWeChatCreatePartyEventBusKey,
+	"update_party": eventCenter.TencentWorkWeChatUpdatePartyEventBusKey,
+	"delete_party": eventCenter.TencentWorkWeChatDeletePartyEventBusKey,
+}
+
+// WorkWeChatCallbackService 企业微信回调 校验 msg_signature 解密消息并发布到 TencentWorkWeChatEventBus
+type WorkWeChatCallbackService struct {
+	token     string
+	aesKey    []byte
+	receiveId string
+}
+
+func NewWorkWeChatCallbackService(config *core.Config) WorkWeChatCallbackService {
+	qywx := config.Tencent.WorkWechat
+	// EncodingAESKey 是去掉了末尾 = 的 Base64 长度错误时 aesKey 为空 由 Check 报错
+	aesKey, _ := base64.StdEncoding.DecodeString(qywx.Callback.EncodingAESKey + "=")
+	return WorkWeChatCallbackService{
+		token:     qywx.Callback.Token,
+		aesKey:    aesKey,
+		receiveId: qywx.CorpId,
+	}
+}
+
+// Check 检查回调配置 注册回调路由时调用
+func (s WorkWeChatCallbackService) Check() error {
+	if s.token == "" {
+		return errors.New("企业微信回调需要配置 Tencent.WorkWechat.Callback.Token")
+	}
+	if len(s.aesKey) != 32 {
+		return errors.New("企业微信回调的 Tencent.WorkWechat.Callback.EncodingAESKey 需要是 43 位")
+	}
+	return nil
+}
+
+// VerifyURL 验证回调地址 返回解密之后的 echostr
+func (s WorkWeChatCallbackService) VerifyURL(signature, timestamp, nonce, echoStr string) (string, error) {
+	if !s.verifySignature(signature, timestamp, nonce, echoStr) {
+		return "", errWorkWeChatCallbackSignature
+	}
+	if err := checkWorkWeChatTimestamp(timestamp, time.Now()); err != nil {
+		return "", err
+	}
+	message, err := s.decrypt(echoStr)
+	if err != nil {
+		return "", err
+	}
+	return string(message), nil
+}
+
+// DecryptMessage 校验签名和时间戳并解密回调消息的 Encrypt 字段
+// 同一个时间戳和 nonce 的消息只解密一次 重复的返回 ErrWorkWeChatCallbackReplay
+func (s WorkWeChatCallbackService) DecryptMessage(c echo.Context, signature, timestamp, nonce string, body []byte) ([]byte, error) {
+	var envelope struct {
+		Encrypt string `xml:"Encrypt"`
+	}
+	if err := xml.Unmarshal(body, &envelope); err != nil || envelope.Encrypt == "" {
+		return nil, errWorkWeChatCallbackPayload
+	}
+	if !s.verifySignature(signature, timestamp, nonce, envelope.Encrypt) {
+		return nil, errWorkWeChatCallbackSignature
+	}
+	if err := checkWorkWeChatTimestamp(timestamp, time.Now()); err != nil {
+		return nil, err
+	}
+	message, err := s.decrypt(envelope.Encrypt)
+	if err != nil {
+		return nil, err
+	}
+	// 时间戳前后都可能偏差 5 分钟 记录 10 分钟之后同样的时间戳已经过期
+	nonces := core.GetContextRedisCache[int64](c, "work:wechat:callback:nonce:")
+	first, err := nonces.SetNX(c.Request().Context(), "work:wechat:callback:nonce:"+timestamp+":"+nonce, 1, 2*workWeChatCallbackWindow).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !first {
+		return nil, ErrWorkWeChatCallbackReplay
+	}
+	return message, nil
+}
+
+// checkWorkWeChatTimestamp 时间戳是秒 和 now 相差不能超过 workWeChatCallbackWindow
+func checkWorkWeChatTimestamp(timestamp string, now time.Time) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errWorkWeChatCallbackExpired
+	}
+	diff := now.Sub(time.Unix(seconds, 0))
+	if diff > workWeChatCallbackWindow || diff < -workWeChatCallbackWindow {
+		return errWorkWeChatCallbackExpired
+	}
+	return nil
+}
+
+// Dispatch 把解密之后的消息发布到 TencentWorkWeChatEventBus
+// 所有消息都发布 TencentWorkWeChatCallbackEventBusKey 成员、部门和审批事件再按类型发布
+func (s WorkWeChatCallbackService) Dispatch(message []byte) error {
+	var common struct {
+		eventCenter.TencentWorkWeChatCallbackEventBusData
+		UserID         string `xml:"UserID"`
+		NewUserID      string `xml:"NewUserID"`
+		Name           string `xml:"Name"`
+		Department     string `xml:"Department"`
+		MainDepartment int64  `xml:"MainDepartment"`
+		Mobile         string `xml:"Mobile"`
+		Email          string `xml:"Email"`
+		Position       string `xml:"Position"`
+		Gender         int    `xml:"Gender"`
+		Status         int    `xml:"Status"`
+		Avatar         string `xml:"Avatar"`
+		Alias          string `xml:"Alias"`
+		ID             int64  `xml:"Id"`
+		ParentID       int64  `xml:"ParentId"`
+		Order          int64  `xml:"Order"`
+		ApprovalInfo   struct {
+			SpNo             string `xml:"SpNo"`
+			SpName           string `xml:"SpName"`
+			SpStatus         int    `xml:"SpStatus"`
+			TemplateID       string `xml:"TemplateId"`
+			ApplyTime        int64  `xml:"ApplyTime"`
+			ApplyerUserID    string `xml:"Applyer>UserId"`
+			StatuChangeEvent int    `xml:"StatuChangeEvent"`
+		} `xml:"ApprovalInfo"`
+	}
+	if err := xml.Unmarshal(message, &common); err != nil {
+		return err
+	}
+	data := common.TencentWorkWeChatCallbackEventBusData
+	data.Raw = string(message)
+	eventCenter.TencentWorkWeChatEventBus.Publish(eventCenter.TencentWorkWeChatCallbackEventBusKey, data)
+	if data.MsgType != "event" {
+		return nil
+	}
+	switch data.Event {
+	case "change_contact":
+		key, ok := workWeChatContactEventKeys[data.ChangeType]
+		if !ok {
+			return nil
+		}
+		if strings.HasSuffix(data.ChangeType, "_user") {
+			eventCenter.TencentWorkWeChatEventBus.Publish(key, eventCenter.TencentWorkWeChatUserEventBusData{
+				UserID:         common.UserID,
+				NewUserID:      common.NewUserID,
+				Name:           common.Name,
+				Department:     parseWorkWeChatIds(common.Department),
+				MainDepartment: common.MainDepartment,
+				Mobile:         common.Mobile,
+				Email:          common.Email,
+				Position:       common.Position,
+				Gender:         common.Gender,
+				Status:         common.Status,
+				Avatar:         common.Avatar,
+				Alias:          common.Alias,
+				CreateTime:     data.CreateTime,
+			})
+		} else {
+			eventCenter.TencentWorkWeChatEventBus.Publish(key, eventCenter.TencentWorkWeChatPartyEventBusData{
+				ID:         common.ID,
+				Name:       common.Name,
+				ParentID:   common.ParentID,
+				Order:      common.Order,
+				CreateTime: data.CreateTime,
+			})
+		}
+	case "sys_approval_change":
+		approval := common.ApprovalInfo
+		eventCenter.TencentWorkWeChatEventBus.Publish(eventCenter.TencentWorkWeChatApprovalChangeEventBusKey, eventCenter.TencentWorkWeChatApprovalEventBusData{
+			SpNo:              approval.SpNo,
+			SpName:            approval.SpName,
+			SpStatus:          approval.SpStatus,
+			TemplateID:        approval.TemplateID,
+			ApplyTime:         approval.ApplyTime,
+			ApplyerUserID:     approval.ApplyerUserID,
+			StatusChangeEvent: approval.StatuChangeEvent,
+			CreateTime:        data.CreateTime,
+		})
+	}
+	return nil
+}
+
+// signature msg_signature 是 token、timestamp、nonce 和密文按字典序排序拼接之后的 SHA1
+func (s WorkWeChatCallbackService) signature(timestamp, nonce, encrypted string) string {
+	values := []string{s.token, timestamp, nonce, encrypted}
+	sort.Strings(values)
+	sum := sha1.Sum([]byte(strings.Join(values, "")))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s WorkWeChatCallbackService) verifySignature(signature, timestamp, nonce, encrypted string) bool {
+	expected := s.signature(timestamp, nonce, encrypted)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(strings.ToLower(signature))) == 1
+}
+
+// decrypt AES-256-CBC 解密 IV 是密钥的前 16 位 PKCS#7 按 32 字节补位
+// 明文是 16 字节随机数 + 4 字节网络序的消息长度 + 消息 + ReceiveId 配置了 CorpId 时校验 ReceiveId
+func (s WorkWeChatCallbackService) decrypt(encrypted string) ([]byte, error) {
+	if len(s.aesKey) != 32 {
+		return nil, errWorkWeChatCallbackPayload
+	}
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil || len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, errWorkWeChatCallbackPayload
+	}
+	block, err := aes.NewCipher(s.aesKey)
+	if err != nil {
+		return nil, err
+	}
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, s.aesKey[:aes.BlockSize]).CryptBlocks(plain, data)
+	pad := int(plain[len(plain)-1])
+	if pad < 1 || pad > 32 || pad > len(plain) || !bytes.Equal(plain[len(plain)-pad:], bytes.Repeat([]byte{byte(pad)}, pad)) {
+		return nil, errWorkWeChatCallbackPayload
+	}
+	plain = plain[:len(plain)-pad]
+	if len(plain) < 20 {
+		return nil, errWorkWeChatCallbackPayload
+	}
+	length := int(binary.BigEndian.Uint32(plain[16:20]))
+	if length > len(plain)-20 {
+		return nil, errWorkWeChatCallbackPayload
+	}
+	message, receiveId := plain[20:20+length], string(plain[20+length:])
+	if s.receiveId != "" && receiveId != s.receiveId {
+		return nil, errWorkWeChatCallbackPayload
+	}
+	return message, nil
+}
+
+// parseWorkWeChatIds 解析逗号分隔的部门ID
+func parseWorkWeChatIds(value string) []int64 {
+	ids := make([]int64, 0)
+	for _, item := range strings.Split(value, ",") {
+		if id, err := strconv.ParseInt(strings.TrimSpace(item), 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/super-sunshines/echo-server-core/core"
+	"github.com/super-sunshines/echo-server-core/core/coretest"
+	eventCenter "github.com/super-sunshines/echo-server-core/vben/event"
+)
+
+// 按企业微信加解密方案生成的一组消息 receiveId 是 ww1234567890
+const (
+	testCallbackToken     = "callback-token"
+	testCallbackAESKey    = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+	testCallbackTimestamp = "1700000000"
+	testCallbackNonce     = "1372623149"
+	testCallbackSignature = "ccf28e4d400721780b6568814e5dcb5e884f6f9a"
+	testCallbackEncrypt   = "Q3stYC6hdFzMh9T8HCvyDMQ2MxAeDymC8rqluoPzIHcR9jNNlkyuRd+iRQJUJgFr76NIzVPfCewIc0KzrJDxv+tGP/WdIjuuTGwcGkE8cgYTq+AJ/zPDSwuxxpKFbeJFiUWSD8zk/GDl3uMcoZMmFdov/kSMVwtQfQk1IAYjyaAxGi2bJk40D1uR67kmnnQ0L57Jv7AEywtPgscTu2CkAd87ntKuAww+V+RZV1ys83Vkj/r7GxgeUQLfeGpSAu5fcNpk6QdAVntQFt5u+PhyZZ1DoRJBGKLWqbLviUq9n87ssZ3TJ4qQfnGVgxCOshuP6xNgp1eq//Ztnb1nhP8zx+WGaIpbHDTm9GzQ8ps3Fpofi8Jj4VzRxa7Xg/ANYH+hbrCT5Fkf2CslWrh3l/DDL8/71ghCIIK0qCXiguudiUX7Sl2PC47v8otuDQUHewdIcGcyLDsrIYKLfHLKiSfSSy0BUfnvZ4sXy2CcfJxPiNdkRowzzWRgJ78725MaSCxh9c6Vg9KHbZpMFjh/bW8DRlzpAGnDLB3ZUfB39mC5y29OVXwN3CVMb2KE0WNrBncjCdUNdvi8oYq696A5r0kaFQ=="
+	testCallbackMessage   = "<xml><ToUserName><![CDATA[ww1234567890]]></ToUserName><FromUserName><![CDATA[sys]]></FromUserName><CreateTime>1700000000</CreateTime><MsgType><![CDATA[event]]></MsgType><Event><![CDATA[change_contact]]></Event><ChangeType><![CDATA[create_user]]></ChangeType><UserID><![CDATA[zhangsan]]></UserID><Name><![CDATA[张三]]></Name><Department><![CDATA[1,2]]></Department><MainDepartment>1</MainDepartment></xml>"
+)
+
+func newTestCallbackService(corpId string) WorkWeChatCallbackService {
+	var cfg core.Config
+	cfg.Tencent.WorkWechat.CorpId = corpId
+	cfg.Tencent.WorkWechat.Callback.Token = testCallbackToken
+	cfg.Tencent.WorkWechat.Callback.EncodingAESKey = testCallbackAESKey
+	return NewWorkWeChatCallbackService(&cfg)
+}
+
+// 签名、解密和事件分发
+func TestWorkWeChatCallbackKnownMessage(t *testing.T) {
+	s := newTestCallbackService("ww1234567890")
+	if err := s.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if !s.verifySignature(testCallbackSignature, testCallbackTimestamp, testCallbackNonce, testCallbackEncrypt) {
+		t.Fatal("signature of the known message rejected")
+	}
+	if s.verifySignature(testCallbackSignature, testCallbackTimestamp, "1", testCallbackEncrypt) {
+		t.Fatal("signature accepted with another nonce")
+	}
+	message, err := s.decrypt(testCallbackEncrypt)
+	if err != nil || string(message) != testCallbackMessage {
+		t.Fatalf("decrypt: %v %q", err, message)
+	}
+	// 发给其他企业的消息不能解密
+	if _, err = newTestCallbackService("ww0000000000").decrypt(testCallbackEncrypt); err == nil {
+		t.Fatal("decrypted a message for another receiveId")
+	}
+
+	var got []eventCenter.TencentWorkWeChatUserEventBusData
+	handler := func(data eventCenter.TencentWorkWeChatUserEventBusData) { got = append(got, data) }
+	if err = eventCenter.TencentWorkWeChatEventBus.Subscribe(eventCenter.TencentWorkWeChatCreateUserEventBusKey, handler); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = eventCenter.TencentWorkWeChatEventBus.Unsubscribe(eventCenter.TencentWorkWeChatCreateUserEventBusKey, handler)
+	})
+	if err = s.Dispatch(message); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].UserID != "zhangsan" || got[0].Name != "张三" ||
+		fmt.Sprint(got[0].Department) != "[1 2]" || got[0].MainDepartment != 1 {
+		t.Fatalf("create_user event: %+v", got)
+	}
+}
+
+// 过期的时间戳和重复的 nonce 不处理
+func TestWorkWeChatCallbackReplay(t *testing.T) {
+	s := newTestCallbackService("ww1234567890")
+	server := core.NewServerFromConfig(coretest.NewConfig(t), nil, core.ServerRunOption{})
+	e, _, buildErr := server.Build()
+	if buildErr != nil {
+		t.Fatal(buildErr)
+	}
+	t.Cleanup(func() { _ = server.Close() })
+	var err error
+	e.POST("/callback", func(c echo.Context) error {
+		body := "<xml><Encrypt><![CDATA[" + testCallbackEncrypt + "]]></Encrypt></xml>"
+		_, err = s.DecryptMessage(c, c.QueryParam("msg_signature"), c.QueryParam("timestamp"), c.QueryParam("nonce"), []byte(body))
+		return nil
+	})
+	decrypt := func(signature, timestamp, nonce string) error {
+		err = nil
+		target := "/callback?msg_signature=" + signature + "&timestamp=" + timestamp + "&nonce=" + nonce
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, target, strings.NewReader("")))
+		return err
+	}
+
+	// 签名正确但是时间戳是很久之前的
+	if err = decrypt(testCallbackSignature, testCallbackTimestamp, testCallbackNonce); !errors.Is(err, errWorkWeChatCallbackExpired) {
+		t.Fatalf("stale timestamp: %v", err)
+	}
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	if err = decrypt(testCallbackSignature, now, testCallbackNonce); !errors.Is(err, errWorkWeChatCallbackSignature) {
+		t.Fatalf("timestamp changed without signing: %v", err)
+	}
+	signature := s.signature(now, testCallbackNonce, testCallbackEncrypt)
+	if err = decrypt(signature, now, testCallbackNonce); err != nil {
+		t.Fatalf("first delivery: %v", err)
+	}
+	if err = decrypt(signature, now, testCallbackNonce); !errors.Is(err, ErrWorkWeChatCallbackReplay) {
+		t.Fatalf("replay: %v", err)
+	}
+	// 同一时间的其他消息使用不同的 nonce
+	if err = decrypt(s.signature(now, "1", testCallbackEncrypt), now, "1"); err != nil {
+		t.Fatalf("another nonce: %v", err)
+	}
+}
+
+func TestCheckWorkWeChatTimestamp(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	for timestamp, valid := range map[string]bool{
+		"1700000000": true, "1699999700": true, "1700000300": true,
+		"1699999699": false, "1700000301": false, "": false, "abc": false,
+	} {
+		if err := checkWorkWeChatTimestamp(timestamp, now); (err == nil) != valid {
+			t.Errorf("timestamp %q: %v", timestamp, err)
+		}
+	}
+}
@@ -1,30 +1,65 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"github.com/super-sunshines/echo-server-core/core"
 	"github.com/xen0n/go-workwx/v2"
 	"net/url"
+	"sync"
 )
 
-var tencentWorkWeChatService *TencentWorkWeChatService
+// tencentWorkWeChatServices 每个 Server 的配置对应一个企业微信客户端
+var tencentWorkWeChatServices = struct {
+	sync.Mutex
+	items map[*core.Config]*TencentWorkWeChatService
+}{items: map[*core.Config]*TencentWorkWeChatService{}}
 
 type TencentWorkWeChatService struct {
 	*workwx.WorkwxApp
+	cancel context.CancelFunc // 停止后台刷新 AccessToken/JSAPITicket 的协程
+	config core.WorkWechat
 }
 
-func NewTencentWorkWeChatService() *TencentWorkWeChatService {
-	if tencentWorkWeChatService != nil {
-		return tencentWorkWeChatService
+// NewTencentWorkWeChatService 获取 config 对应的企业微信客户端 第一次使用时创建并启动后台刷新协程
+func NewTencentWorkWeChatService(config *core.Config) *TencentWorkWeChatService {
+	tencentWorkWeChatServices.Lock()
+	defer tencentWorkWeChatServices.Unlock()
+	if service, ok := tencentWorkWeChatServices.items[config]; ok {
+		return service
 	}
-	qywx := core.GetConfig().Tencent.WorkWechat
-	tencentWorkWeChatService = &TencentWorkWeChatService{
-		WorkwxApp: workwx.New(qywx.CorpId).WithApp(qywx.CorpSecret, qywx.AgentId),
+	qywx := config.Tencent.WorkWechat
+	refreshCtx, cancel := context.WithCancel(context.Background())
+	service := &TencentWorkWeChatService{
+		WorkwxApp: workwx.New(qywx.CorpId, workWeChatOptions(qywx)...).WithApp(qywx.CorpSecret, qywx.AgentId),
+		cancel:    cancel,
+		config:    qywx,
 	}
-	tencentWorkWeChatService.SpawnAccessTokenRefresher()
-	tencentWorkWeChatService.SpawnJSAPITicketRefresher()
-	tencentWorkWeChatService.SpawnJSAPITicketAgentConfigRefresher()
-	return tencentWorkWeChatService
+	service.SpawnAccessTokenRefresherWithContext(refreshCtx)
+	service.SpawnJSAPITicketRefresherWithContext(refreshCtx)
+	service.SpawnJSAPITicketAgentConfigRefresherWithContext(refreshCtx)
+	tencentWorkWeChatServices.items[config] = service
+	return service
+}
+
+// workWeChatOptions 企业微信客户端的参数 配置了 ApiHost 时使用指定的接口地址
+func workWeChatOptions(config core.WorkWechat) []workwx.CtorOption {
+	if config.ApiHost != "" {
+		return []workwx.CtorOption{workwx.WithQYAPIHost(config.ApiHost)}
+	}
+	return nil
+}
+
+// StopTencentWorkWeChatService 停止钩子所属 Server 的企业微信后台刷新协程 用作服务关闭的钩子
+func StopTencentWorkWeChatService(ctx context.Context) error {
+	config := core.GetContextConfig(core.NewBackgroundContext(ctx))
+	tencentWorkWeChatServices.Lock()
+	defer tencentWorkWeChatServices.Unlock()
+	if service, ok := tencentWorkWeChatServices.items[config]; ok {
+		service.cancel()
+		delete(tencentWorkWeChatServices.items, config)
+	}
+	return nil
 }
 
 func (r *TencentWorkWeChatService) UserInfoByCode(code string) (userInfo *workwx.UserInfo, err error) {
@@ -42,7 +77,7 @@ func (r *TencentWorkWeChatService) UserInfoByCode(code string) (userInfo *workwx
 }
 
 func (r *TencentWorkWeChatService) GetAuthUrl() string {
-	qywx := core.GetConfig().Tencent.WorkWechat
+	qywx := r.config
 	return fmt.Sprintf(
 		"https://open.weixin.qq.com/connect/oauth2/authorize?appid=%s&redirect_uri=%s&response_type=code&scope=snsapi_base&debug=1&state=#wechat_redirect",
 		qywx.CorpId, url.QueryEscape(qywx.RedirectionUrl))
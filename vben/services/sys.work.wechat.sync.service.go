@@ -0,0 +1,490 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/super-sunshines/echo-server-core/core"
+	_const "github.com/super-sunshines/echo-server-core/vben/const"
+	"github.com/super-sunshines/echo-server-core/vben/gorm/model"
+	"github.com/super-sunshines/echo-server-core/vben/vo"
+	"github.com/xen0n/go-workwx/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// 通讯录同步的变化类型
+const (
+	WorkWeChatSyncCreate     = "create"
+	WorkWeChatSyncUpdate     = "update"
+	WorkWeChatSyncDeactivate = "deactivate"
+	WorkWeChatSyncConflict   = "conflict"
+)
+
+const (
+	defaultWorkWeChatSyncDepartment int64 = 1
+	workWeChatSyncLockExpire              = 10 * time.Minute
+	// 企业微信成员的状态 1 已激活 2 已禁用 4 未激活 5 退出企业
+	workWeChatUserStatusQuit workwx.UserStatus = 5
+)
+
+// workWeChatSyncCancels 每个 Server 的定时同步 按 Server 的配置区分
+var workWeChatSyncCancels = struct {
+	sync.Mutex
+	items map[*core.Config]context.CancelFunc
+}{items: map[*core.Config]context.CancelFunc{}}
+
+// SysWorkWeChatSyncService 企业微信通讯录同步 部门通过 sys_department_third_bind 对应 成员通过 sys_user_third_bind 对应
+type SysWorkWeChatSyncService struct {
+	userService           core.PreGorm[model.SysUser, any]
+	departmentService     SysDepartmentService
+	departmentBindService core.PreGorm[model.SysDepartmentThirdBind, any]
+	thirdBindService      SysThirdBindService
+}
+
+func NewSysWorkWeChatSyncService() SysWorkWeChatSyncService {
+	return SysWorkWeChatSyncService{
+		userService:           core.NewService[model.SysUser, any](),
+		departmentService:     NewDepartmentService(),
+		departmentBindService: core.NewService[model.SysDepartmentThirdBind, any](),
+		thirdBindService:      NewSysThirdBindService(),
+	}
+}
+
+// lock 同步锁 存在处理当前请求的 Server 的 Redis 中
+func (s SysWorkWeChatSyncService) lock(c echo.Context) *core.RedisCache[int64] {
+	return core.GetContextRedisCache[int64](c, "sys:work-wechat:sync:lock")
+}
+
+// schedule 定时同步的周期锁 多个实例时每个周期只有一个实例执行
+func (s SysWorkWeChatSyncService) schedule(c echo.Context) *core.RedisCache[int64] {
+	return core.GetContextRedisCache[int64](c, "sys:work-wechat:sync:schedule")
+}
+
+// workWeChatSyncPlan 同步计划 预览时只返回 result 同步时在一个事务中按顺序执行 steps
+type workWeChatSyncPlan struct {
+	result        vo.WorkWechatSyncVo
+	steps         []func(tx core.TxScope) error
+	departmentIds map[int64]int64  // 企业微信部门ID对应的本地部门ID 新建的部门执行之后补上
+	names         map[int64]string // 企业微信部门ID对应的名称
+	deactivated   []int64          // 禁用的用户 同步之后强制下线
+}
+
+// Sync 从企业微信拉取部门和成员同步到本地 dryRun 时只返回变化不写入
+// 企业微信中禁用、离职或者不在同步范围内的成员会被禁用 拉取不到任何成员时不禁用
+func (s SysWorkWeChatSyncService) Sync(c echo.Context, dryRun bool) (vo.WorkWechatSyncVo, error) {
+	if !dryRun {
+		if !s.lock(c).XSetNXEX(1, workWeChatSyncLockExpire) {
+			return vo.WorkWechatSyncVo{}, core.NewFrontShowErrMsg("通讯录正在同步，请稍后再试！")
+		}
+		defer s.lock(c).XDel()
+	}
+	departments, users, err := fetchWorkWeChatDirectory(core.GetContextConfig(c).Tencent.WorkWechat)
+	if err != nil {
+		zap.L().Error("获取企业微信通讯录失败", zap.Error(err))
+		return vo.WorkWechatSyncVo{}, core.NewFrontShowErrMsg("获取企业微信通讯录失败！")
+	}
+	plan := &workWeChatSyncPlan{
+		result:        vo.WorkWechatSyncVo{DryRun: dryRun, Departments: []vo.WorkWechatSyncChangeVo{}, Users: []vo.WorkWechatSyncChangeVo{}},
+		departmentIds: map[int64]int64{},
+		names:         map[int64]string{},
+	}
+	if err = s.planDepartments(c, plan, departments); err != nil {
+		return vo.WorkWechatSyncVo{}, err
+	}
+	if err = s.planUsers(c, plan, users); err != nil {
+		return vo.WorkWechatSyncVo{}, err
+	}
+	if dryRun || len(plan.steps) == 0 {
+		return plan.result, nil
+	}
+	err = core.Transaction(c, func(tx core.TxScope) error {
+		for _, step := range plan.steps {
+			if err := step(tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		zap.L().Error("同步企业微信通讯录失败", zap.Error(err))
+		return vo.WorkWechatSyncVo{}, err
+	}
+	s.departmentService.ClearCache(c)
+	for _, uid := range plan.deactivated {
+		core.GetContextTokenManager(c).RemoveTokenByUid(uid)
+	}
+	zap.L().Info("企业微信通讯录同步完成", zap.Int("departments", len(plan.result.Departments)), zap.Int("users", len(plan.result.Users)))
+	return plan.result, nil
+}
+
+// planDepartments 按父部门在前的顺序对比部门 新建的部门在执行时才知道本地ID
+func (s SysWorkWeChatSyncService) planDepartments(c echo.Context, plan *workWeChatSyncPlan, departments []*workwx.DeptInfo) error {
+	err, binds := s.departmentBindService.WithContext(c).SkipGlobalHook().FindList(func(db *gorm.DB) *gorm.DB {
+		return db.Where("platform = ?", _const.ThirdPlatformWorkWeChat)
+	})
+	if err != nil {
+		return err
+	}
+	err, locals := s.departmentService.WithContext(c).SkipGlobalHook().FindList()
+	if err != nil {
+		return err
+	}
+	localById := map[int64]model.SysDepartment{}
+	for _, local := range locals {
+		localById[local.ID] = local
+	}
+	bound := map[string]model.SysDepartment{}
+	for _, bind := range binds {
+		if local, ok := localById[bind.DepartmentID]; ok {
+			bound[bind.ThirdID] = local
+		}
+	}
+	fetched := map[int64]bool{}
+	for _, department := range departments {
+		fetched[department.ID] = true
+		plan.names[department.ID] = department.Name
+	}
+	parentId := core.GetContextConfig(c).Tencent.WorkWechat.Sync.ParentId
+	for _, item := range departments {
+		department := item
+		thirdId := strconv.FormatInt(department.ID, 10)
+		top := !fetched[department.ParentID]
+		// 执行时父部门已经处理过 可以拿到本地ID
+		resolvePid := func() int64 {
+			return core.BooleanTo(top, parentId, plan.departmentIds[department.ParentID])
+		}
+		parentName := func(pid int64) string {
+			if pid == 0 {
+				return "无"
+			}
+			return localById[pid].Name
+		}
+		local, exist := bound[thirdId]
+		if !exist {
+			index := len(plan.result.Departments)
+			plan.result.Departments = append(plan.result.Departments, vo.WorkWechatSyncChangeVo{
+				Action:  WorkWeChatSyncCreate,
+				ThirdId: thirdId,
+				Name:    department.Name,
+			})
+			plan.steps = append(plan.steps, func(tx core.TxScope) error {
+				err, created := s.departmentService.WithContext(tx).SkipGlobalHook().InsertOne(model.SysDepartment{
+					Pid:          resolvePid(),
+					Name:         department.Name,
+					EnableStatus: _const.CommonStateOk,
+				})
+				if err != nil {
+					return err
+				}
+				// 对应的本地部门被删除之后重新创建
+				err, _ = s.departmentBindService.WithContext(tx).SkipGlobalHook().DeleteBy(func(db *gorm.DB) *gorm.DB {
+					return db.Where("platform = ?", _const.ThirdPlatformWorkWeChat).Where("third_id = ?", thirdId)
+				})
+				if err != nil {
+					return err
+				}
+				err, _ = s.departmentBindService.WithContext(tx).SkipGlobalHook().InsertOne(model.SysDepartmentThirdBind{
+					DepartmentID: created.ID,
+					Platform:     _const.ThirdPlatformWorkWeChat,
+					ThirdID:      thirdId,
+				})
+				plan.departmentIds[department.ID] = created.ID
+				plan.result.Departments[index].LocalId = created.ID
+				return err
+			})
+			continue
+		}
+		plan.departmentIds[department.ID] = local.ID
+		var changes []string
+		if local.Name != department.Name {
+			changes = append(changes, syncChange("名称", local.Name, department.Name))
+		}
+		// 父部门是新建的时候 expectPid 为 0 一定会更新
+		expectPid, parentExist := plan.departmentIds[department.ParentID]
+		if top {
+			expectPid, parentExist = parentId, true
+		}
+		if !parentExist || local.Pid != expectPid {
+			changes = append(changes, syncChange("上级部门", parentName(local.Pid), core.BooleanTo(top, parentName(parentId), plan.names[department.ParentID])))
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		plan.result.Departments = append(plan.result.Departments, vo.WorkWechatSyncChangeVo{
+			Action:  WorkWeChatSyncUpdate,
+			ThirdId: thirdId,
+			LocalId: local.ID,
+			Name:    department.Name,
+			Changes: changes,
+		})
+		plan.steps = append(plan.steps, func(tx core.TxScope) error {
+			return s.departmentService.WithContext(tx).SkipGlobalHook().Where("id = ?", local.ID).UpdateColumns(map[string]any{
+				"name": department.Name,
+				"pid":  resolvePid(),
+			}).Error
+		})
+	}
+	return nil
+}
+
+// planUsers 对比成员 只同步真实姓名、邮箱、手机号和部门 昵称和头像用户可以自己修改 不覆盖
+func (s SysWorkWeChatSyncService) planUsers(c echo.Context, plan *workWeChatSyncPlan, users []*workwx.UserInfo) error {
+	err, binds := s.thirdBindService.WithContext(c).SkipGlobalHook().FindList(func(db *gorm.DB) *gorm.DB {
+		return db.Where("login_type = ?", _const.ThirdPlatformWorkWeChat)
+	})
+	if err != nil {
+		return err
+	}
+	uidList := make([]int64, 0, len(binds))
+	for _, bind := range binds {
+		uidList = append(uidList, bind.UserID)
+	}
+	err, locals := s.userService.WithContext(c).SkipGlobalHook().FindList(func(db *gorm.DB) *gorm.DB {
+		return db.Where("id IN ?", append(uidList, 0))
+	})
+	if err != nil {
+		return err
+	}
+	localById := map[int64]model.SysUser{}
+	for _, local := range locals {
+		localById[local.ID] = local
+	}
+	bound := map[string]model.SysUser{}
+	for _, bind := range binds {
+		if local, ok := localById[bind.UserID]; ok {
+			bound[bind.Openid] = local
+		}
+	}
+	err, departments := s.departmentService.WithContext(c).SkipGlobalHook().FindList()
+	if err != nil {
+		return err
+	}
+	departmentNames := map[int64]string{0: "无"}
+	for _, department := range departments {
+		departmentNames[department.ID] = department.Name
+	}
+	config := core.GetContextConfig(c).Tencent.WorkWechat
+	seen := map[string]bool{}
+	for _, item := range users {
+		user := item
+		if seen[user.UserID] {
+			continue
+		}
+		seen[user.UserID] = true
+		active := user.Status != workwx.UserStatusDeactivated && user.Status != workWeChatUserStatusQuit
+		// 成员属于多个部门时使用第一个部门 部门不在同步范围内时不修改
+		var thirdDepartment int64
+		if len(user.Departments) > 0 {
+			if _, ok := plan.names[user.Departments[0].DeptID]; ok {
+				thirdDepartment = user.Departments[0].DeptID
+			}
+		}
+		resolveDepartment := func() int64 {
+			return plan.departmentIds[thirdDepartment]
+		}
+		phone := core.BooleanTo(len(user.Mobile) <= 11, user.Mobile, "")
+		local, exist := bound[user.UserID]
+		if !exist {
+			if !active {
+				continue
+			}
+			taken := s.userService.WithContext(c).SkipGlobalHook().Exist(func(db *gorm.DB) *gorm.DB {
+				return db.Where("username = ?", user.UserID)
+			})
+			if taken {
+				plan.result.Users = append(plan.result.Users, vo.WorkWechatSyncChangeVo{
+					Action:  WorkWeChatSyncConflict,
+					ThirdId: user.UserID,
+					Name:    user.Name,
+					Message: fmt.Sprintf("账号%s已存在，请绑定或者合并之后再同步", user.UserID),
+				})
+				continue
+			}
+			index := len(plan.result.Users)
+			plan.result.Users = append(plan.result.Users, vo.WorkWechatSyncChangeVo{
+				Action:  WorkWeChatSyncCreate,
+				ThirdId: user.UserID,
+				Name:    user.Name,
+			})
+			plan.steps = append(plan.steps, func(tx core.TxScope) error {
+				err, created := s.userService.WithContext(tx).SkipGlobalHook().InsertOne(model.SysUser{
+					Username:     user.UserID,
+					Password:     core.HashPassword(core.GetUUID()),
+					NickName:     user.Name,
+					RealName:     user.Name,
+					Email:        user.Email,
+					Phone:        phone,
+					Avatar:       user.AvatarURL,
+					DepartmentID: resolveDepartment(),
+					RoleCodeList: config.DefaultRoles,
+					EnableStatus: int64(core.BooleanTo(config.AutoRegister, _const.CommonStateOk, _const.CommonStateBanned)),
+				})
+				if err != nil {
+					return err
+				}
+				err, _ = s.thirdBindService.WithContext(tx).SkipGlobalHook().InsertOne(model.SysUserThirdBind{
+					UserID:    created.ID,
+					LoginType: _const.ThirdPlatformWorkWeChat,
+					Openid:    user.UserID,
+				})
+				plan.result.Users[index].LocalId = created.ID
+				return err
+			})
+			continue
+		}
+		columns := map[string]any{}
+		var changes []string
+		for _, field := range []struct{ column, label, old, value string }{
+			{"real_name", "真实姓名", local.RealName, user.Name},
+			{"email", "邮箱", local.Email, user.Email},
+			{"phone", "手机号", local.Phone, phone},
+		} {
+			// 企业微信没有返回的字段不覆盖
+			if field.value != "" && field.value != field.old {
+				columns[field.column] = field.value
+				changes = append(changes, syncChange(field.label, field.old, field.value))
+			}
+		}
+		expectDepartment, departmentExist := plan.departmentIds[thirdDepartment]
+		if thirdDepartment != 0 && (!departmentExist || local.DepartmentID != expectDepartment) {
+			changes = append(changes, syncChange("部门", departmentNames[local.DepartmentID], plan.names[thirdDepartment]))
+		}
+		deactivate := !active && local.EnableStatus != _const.CommonStateBanned
+		if deactivate {
+			columns["enable_status"] = _const.CommonStateBanned
+			plan.deactivated = append(plan.deactivated, local.ID)
+		}
+		if len(changes) == 0 && !deactivate {
+			continue
+		}
+		plan.result.Users = append(plan.result.Users, vo.WorkWechatSyncChangeVo{
+			Action:  core.BooleanTo(deactivate, WorkWeChatSyncDeactivate, WorkWeChatSyncUpdate),
+			ThirdId: user.UserID,
+			LocalId: local.ID,
+			Name:    user.Name,
+			Changes: changes,
+			Message: core.BooleanTo(deactivate, "企业微信中已禁用", ""),
+		})
+		plan.steps = append(plan.steps, func(tx core.TxScope) error {
+			if thirdDepartment != 0 {
+				columns["department_id"] = resolveDepartment()
+			}
+			return s.userService.WithContext(tx).SkipGlobalHook().Where("id = ?", local.ID).UpdateColumns(columns).Error
+		})
+	}
+	// 接口异常或者没有权限时可能拉取不到成员 这时不禁用 避免所有用户被禁用
+	if len(seen) == 0 {
+		zap.L().Warn("没有拉取到企业微信成员，跳过禁用离职成员")
+		return nil
+	}
+	for openid, local := range bound {
+		if seen[openid] || local.EnableStatus == _const.CommonStateBanned {
+			continue
+		}
+		plan.deactivated = append(plan.deactivated, local.ID)
+		plan.result.Users = append(plan.result.Users, vo.WorkWechatSyncChangeVo{
+			Action:  WorkWeChatSyncDeactivate,
+			ThirdId: openid,
+			LocalId: local.ID,
+			Name:    local.RealName,
+			Message: "已离开企业或者不在同步范围内",
+		})
+		uid := local.ID
+		plan.steps = append(plan.steps, func(tx core.TxScope) error {
+			return s.userService.WithContext(tx).SkipGlobalHook().Where("id = ?", uid).UpdateColumn("enable_status", _const.CommonStateBanned).Error
+		})
+	}
+	return nil
+}
+
+// fetchWorkWeChatDirectory 拉取同步范围内的部门和成员 部门按父部门在前的顺序排列
+// 每次同步使用新的客户端 AccessToken 在第一次请求时获取 不需要后台刷新
+func fetchWorkWeChatDirectory(config core.WorkWechat) ([]*workwx.DeptInfo, []*workwx.UserInfo, error) {
+	secret := core.BooleanTo(config.Sync.Secret == "", config.CorpSecret, config.Sync.Secret)
+	root := core.BooleanTo(config.Sync.DepartmentId == 0, defaultWorkWeChatSyncDepartment, config.Sync.DepartmentId)
+	app := workwx.New(config.CorpId, workWeChatOptions(config)...).WithApp(secret, config.AgentId)
+	departments, err := app.ListDepts(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	users, err := app.ListUsersByDeptID(root, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sortWorkWeChatDepartments(departments), users, nil
+}
+
+// sortWorkWeChatDepartments 按层级排序 父部门不在列表中的作为顶级部门
+func sortWorkWeChatDepartments(departments []*workwx.DeptInfo) []*workwx.DeptInfo {
+	fetched := map[int64]bool{}
+	children := map[int64][]*workwx.DeptInfo{}
+	for _, department := range departments {
+		fetched[department.ID] = true
+		children[department.ParentID] = append(children[department.ParentID], department)
+	}
+	sorted := make([]*workwx.DeptInfo, 0, len(departments))
+	for _, department := range departments {
+		if !fetched[department.ParentID] {
+			sorted = append(sorted, department)
+		}
+	}
+	for i := 0; i < len(sorted); i++ {
+		sorted = append(sorted, children[sorted[i].ID]...)
+	}
+	return sorted
+}
+
+func syncChange(label, old, value string) string {
+	return fmt.Sprintf("%s: %s -> %s", label, old, value)
+}
+
+// StartWorkWeChatSync 按 Sync.Interval 定时同步企业微信通讯录 用作服务启动的钩子
+// 多个实例时每个周期只有一个实例执行 同步使用钩子所属 Server 的配置和数据库
+func StartWorkWeChatSync(hookCtx context.Context) error {
+	config := core.GetContextConfig(core.NewBackgroundContext(hookCtx))
+	interval := time.Duration(config.Tencent.WorkWechat.Sync.Interval) * time.Second
+	if interval <= 0 {
+		return nil
+	}
+	// 钩子的 ctx 启动之后就会结束 只保留其中的 Server 依赖
+	ctx, cancel := context.WithCancel(context.WithoutCancel(hookCtx))
+	workWeChatSyncCancels.Lock()
+	workWeChatSyncCancels.items[config] = cancel
+	workWeChatSyncCancels.Unlock()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				service := NewSysWorkWeChatSyncService()
+				ec := core.NewBackgroundContext(ctx)
+				if !service.schedule(ec).XSetNXEX(1, max(interval-time.Second, time.Second)) {
+					continue
+				}
+				if _, err := service.Sync(ec, false); err != nil {
+					zap.L().Error("定时同步企业微信通讯录失败", zap.Error(err))
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// StopWorkWeChatSync 停止钩子所属 Server 的定时同步 用作服务关闭的钩子
+func StopWorkWeChatSync(ctx context.Context) error {
+	config := core.GetContextConfig(core.NewBackgroundContext(ctx))
+	workWeChatSyncCancels.Lock()
+	defer workWeChatSyncCancels.Unlock()
+	if cancel, ok := workWeChatSyncCancels.items[config]; ok {
+		cancel()
+		delete(workWeChatSyncCancels.items, config)
+	}
+	return nil
+}
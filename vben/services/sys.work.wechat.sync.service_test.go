@@ -0,0 +1,192 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/super-sunshines/echo-server-core/core"
+	"github.com/super-sunshines/echo-server-core/core/coretest"
+	_const "github.com/super-sunshines/echo-server-core/vben/const"
+	"github.com/super-sunshines/echo-server-core/vben/gorm/model"
+	"github.com/super-sunshines/echo-server-core/vben/migrations"
+	"github.com/super-sunshines/echo-server-core/vben/vo"
+)
+
+// workWeChatTestDirectory 模拟企业微信的 gettoken、department/list 和 user/list 接口
+type workWeChatTestDirectory struct {
+	mu          sync.Mutex
+	departments []map[string]any
+	users       []map[string]any
+}
+
+func (d *workWeChatTestDirectory) setUsers(users ...map[string]any) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.users = users
+}
+
+func (d *workWeChatTestDirectory) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	response := map[string]any{"errcode": 0, "errmsg": "ok"}
+	switch r.URL.Path {
+	case "/cgi-bin/gettoken":
+		response["access_token"], response["expires_in"] = "test-token", 7200
+	case "/cgi-bin/department/list":
+		response["department"] = d.departments
+	case "/cgi-bin/user/list":
+		response["userlist"] = d.users
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func workWeChatTestUser(userId, name string, status int, departments ...int64) map[string]any {
+	return map[string]any{
+		"userid": userId, "name": name, "status": status,
+		"department": departments, "order": make([]uint32, len(departments)),
+	}
+}
+
+// 部门按层级创建 离职和禁用的成员被禁用 预览不写入数据库
+func TestWorkWeChatSync(t *testing.T) {
+	directory := &workWeChatTestDirectory{
+		// 子部门在父部门前面返回
+		departments: []map[string]any{
+			{"id": 3, "name": "研发组", "parentid": 2},
+			{"id": 1, "name": "企业", "parentid": 0},
+			{"id": 2, "name": "研发部", "parentid": 1},
+		},
+	}
+	directory.setUsers(
+		workWeChatTestUser("zhangsan", "张三", 1, 3),
+		workWeChatTestUser("wangwu", "王五", 2, 2),
+		workWeChatTestUser("sunqi", "孙七", 5, 2),
+		// 本地已经有同名的账号
+		workWeChatTestUser("admin", "管理员", 1, 1),
+	)
+	stub := httptest.NewServer(directory)
+	t.Cleanup(stub.Close)
+
+	cfg := coretest.NewConfig(t)
+	cfg.Tencent.WorkWechat = core.WorkWechat{CorpId: "ww1234567890", CorpSecret: "secret", AutoRegister: true, ApiHost: stub.URL}
+	server := core.NewServerFromConfig(cfg, nil, core.ServerRunOption{Migrations: migrations.Migrations})
+	e, deps, err := server.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = server.Close() })
+	e.POST("/sync", func(c echo.Context) error {
+		result, err := NewSysWorkWeChatSyncService().Sync(c, c.QueryParam("dryRun") == "true")
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, result)
+	})
+	run := func(dryRun bool) vo.WorkWechatSyncVo {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, fmt.Sprintf("/sync?dryRun=%t", dryRun), nil))
+		var result vo.WorkWechatSyncVo
+		if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil || recorder.Code != http.StatusOK {
+			t.Fatalf("sync: %d %s", recorder.Code, recorder.Body.String())
+		}
+		return result
+	}
+	changes := func(items []vo.WorkWechatSyncChangeVo) string {
+		list := make([]string, 0, len(items))
+		for _, item := range items {
+			list = append(list, item.Action+":"+item.ThirdId)
+		}
+		sort.Strings(list)
+		return strings.Join(list, " ")
+	}
+
+	db := deps.DB.WithContext(core.NewSkipGormGlobalHookContext())
+	bound := func(username string) model.SysUser {
+		user := model.SysUser{Username: username, NickName: username, EnableStatus: _const.CommonStateOk}
+		if err := db.Create(&user).Error; err != nil {
+			t.Fatal(err)
+		}
+		bind := model.SysUserThirdBind{UserID: user.ID, LoginType: _const.ThirdPlatformWorkWeChat, Openid: username}
+		if err := db.Create(&bind).Error; err != nil {
+			t.Fatal(err)
+		}
+		return user
+	}
+	wangwu, sunqi, zhaoliu := bound("wangwu"), bound("sunqi"), bound("zhaoliu")
+	enabled := func(uid int64) bool {
+		var user model.SysUser
+		if err := db.First(&user, uid).Error; err != nil {
+			t.Fatal(err)
+		}
+		return user.EnableStatus == _const.CommonStateOk
+	}
+
+	// 预览 禁用的、退出企业的和不在通讯录中的成员都会被禁用
+	result := run(true)
+	if got := changes(result.Departments); got != "create:1 create:2 create:3" {
+		t.Fatalf("dry run departments: %s", got)
+	}
+	if got := changes(result.Users); got != "conflict:admin create:zhangsan deactivate:sunqi deactivate:wangwu deactivate:zhaoliu" {
+		t.Fatalf("dry run users: %s", got)
+	}
+	var count int64
+	db.Model(&model.SysDepartmentThirdBind{}).Count(&count)
+	if count != 0 || !enabled(wangwu.ID) || !enabled(sunqi.ID) || !enabled(zhaoliu.ID) {
+		t.Fatal("dry run changed the database")
+	}
+
+	result = run(false)
+	if got := changes(result.Users); got != "conflict:admin create:zhangsan deactivate:sunqi deactivate:wangwu deactivate:zhaoliu" {
+		t.Fatalf("sync users: %s", got)
+	}
+	departmentOf := func(thirdId string) model.SysDepartment {
+		var department model.SysDepartment
+		err := db.Where("id = (?)", db.Model(&model.SysDepartmentThirdBind{}).Select("department_id").
+			Where("platform = ? AND third_id = ?", _const.ThirdPlatformWorkWeChat, thirdId)).First(&department).Error
+		if err != nil {
+			t.Fatalf("department %s: %v", thirdId, err)
+		}
+		return department
+	}
+	root, dept, team := departmentOf("1"), departmentOf("2"), departmentOf("3")
+	if root.Pid != 0 || dept.Pid != root.ID || team.Pid != dept.ID || team.Name != "研发组" {
+		t.Fatalf("department hierarchy: %+v %+v %+v", root, dept, team)
+	}
+	var zhangsan model.SysUser
+	if err = db.Where("username = ?", "zhangsan").First(&zhangsan).Error; err != nil {
+		t.Fatal(err)
+	}
+	if zhangsan.DepartmentID != team.ID || zhangsan.RealName != "张三" || zhangsan.EnableStatus != _const.CommonStateOk {
+		t.Fatalf("created user: %+v", zhangsan)
+	}
+	if enabled(wangwu.ID) || enabled(sunqi.ID) || enabled(zhaoliu.ID) {
+		t.Fatal("inactive members are still enabled")
+	}
+
+	// 同步之后没有变化
+	if result = run(true); len(result.Departments) != 0 || changes(result.Users) != "conflict:admin" {
+		t.Fatalf("second dry run: %+v", result)
+	}
+
+	// 拉取不到成员时不禁用任何人
+	countBanned := func() (count int64) {
+		db.Model(&model.SysUser{}).Where("enable_status = ?", _const.CommonStateBanned).Count(&count)
+		return count
+	}
+	banned := countBanned()
+	directory.setUsers()
+	if result = run(false); len(result.Users) != 0 || !enabled(zhangsan.ID) || countBanned() != banned {
+		t.Fatalf("empty member list: %+v", result)
+	}
+}
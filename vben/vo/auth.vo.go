@@ -1,9 +1,12 @@
 package vo
 
 type LoginVo struct {
-	AccessToken        string `json:"accessToken"` //token
+	AccessToken        string `json:"accessToken"`  //token
+	RefreshToken       string `json:"refreshToken"` // 刷新令牌
 	NeedChangePassword bool   `json:"needChangePassword"`
 	ChangePasswordCode string `json:"changePasswordCode"`
+	NeedTwoFactor      bool   `json:"needTwoFactor"`  // 需要两步验证 使用 challengeToken 调用 /auth/login/2fa 完成登录
+	ChallengeToken     string `json:"challengeToken"` // 两步验证令牌 5 分钟内有效
 }
 
 type LoginUserInfoVo struct {
@@ -17,6 +20,17 @@ type LoginUserInfoVo struct {
 	Department string   `json:"department"`
 }
 
+type TotpEnrollVo struct {
+	Secret string `json:"secret"` // 无法扫码时手动输入的密钥
+	Uri    string `json:"uri"`    // otpauth:// 链接 生成二维码给认证器 App 扫描
+}
+
+type TotpStatusVo struct {
+	Enabled           bool `json:"enabled"`
+	RecoveryCodeCount int  `json:"recoveryCodeCount"` // 剩余可用的恢复码数量
+}
+
 type OauthLoginVo struct {
-	AccessToken string `json:"accessToken"`
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
 }
@@ -9,6 +9,7 @@ type SysDictVo struct {
 	Describe     string           `json:"describe"`     // 字典描述
 	ValueType    int64            `json:"valueType"`    // 值类型
 	EnableStatus int64            `json:"enableStatus"` // 字典状态
+	Version      int64            `json:"version"`      // 版本号
 	Children     []SysDictChildVo `json:"children"`     // 字典值
 }
 
@@ -12,4 +12,5 @@ type SysRoleVo struct {
 	UpdateStrategy int64             `json:"updateStrategy"` // 更新策略
 	EnableStatus   core.IntBool      `json:"enableStatus"`   // 启用状态
 	HomePath       string            `json:"homePath"`       // 主页目录
+	Version        int64             `json:"version"`        // 版本号
 }
@@ -0,0 +1,9 @@
+package vo
+
+import "github.com/super-sunshines/echo-server-core/core"
+
+type ThirdBindVo struct {
+	LoginType  string    `json:"loginType"`  // 三方平台
+	Openid     string    `json:"openid"`     // 三方唯一标识
+	CreateTime core.Time `json:"createTime"` // 绑定时间
+}
@@ -5,3 +5,18 @@ type SignatureVo struct {
 	NonceStr  string `json:"nonceStr"`  // 过期时间
 	Signature string `json:"signature"` // 过期时间
 }
+
+type WorkWechatSyncVo struct {
+	DryRun      bool                     `json:"dryRun"`      // 是否只预览 没有写入数据库
+	Departments []WorkWechatSyncChangeVo `json:"departments"` // 部门的变化
+	Users       []WorkWechatSyncChangeVo `json:"users"`       // 用户的变化
+}
+
+type WorkWechatSyncChangeVo struct {
+	Action  string   `json:"action"`  // create 新建 update 更新 deactivate 禁用 conflict 冲突没有同步
+	ThirdId string   `json:"thirdId"` // 企业微信的部门ID或者成员UserID
+	LocalId int64    `json:"localId"` // 本地的部门ID或者用户ID 预览新建时为 0
+	Name    string   `json:"name"`    // 名称
+	Changes []string `json:"changes"` // 修改的字段 格式 字段: 旧值 -> 新值
+	Message string   `json:"message"` // 冲突或者禁用的原因
+}